@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ansibleFacts is the content of the jfind local facts file, available as ansible_local.jfind
+type ansibleFacts struct {
+	ScanTimestamp  string                `json:"scan_ts"`
+	Count          int                   `json:"count"`
+	HasOracle      bool                  `json:"has_oracle"`
+	RequireLicense bool                  `json:"require_license"`
+	Majors         []int                 `json:"majors"`
+	Distributions  []string              `json:"distributions"`
+	Default        *ansibleRuntimeFacts  `json:"default_runtime"`
+	Runtimes       []ansibleRuntimeFacts `json:"runtimes"`
+}
+
+type ansibleRuntimeFacts struct {
+	Path           string `json:"path"`
+	Home           string `json:"home"`
+	Version        string `json:"version"`
+	Major          int    `json:"major"`
+	Vendor         string `json:"vendor"`
+	Distribution   string `json:"distribution"`
+	RequireLicense bool   `json:"require_license"`
+}
+
+// newAnsibleFacts flattens a report into facts, with summaries for conditionals in playbooks
+func newAnsibleFacts(output *JSONOutput) ansibleFacts {
+	facts := ansibleFacts{
+		ScanTimestamp: output.Meta.ScanTimestamp,
+		Count:         len(output.Runtimes),
+		HasOracle:     output.Meta.HasOracleJDK,
+		Majors:        []int{},
+		Distributions: []string{},
+		Runtimes:      make([]ansibleRuntimeFacts, 0, len(output.Runtimes)),
+	}
+
+	majors := make(map[int]bool)
+	distributions := make(map[string]bool)
+	defaultIndex := -1
+	for _, runtime := range output.Runtimes {
+		rf := ansibleRuntimeFacts{
+			Path:           runtime.JavaExecutable,
+			Home:           runtime.JavaHome,
+			Version:        runtime.JavaVersion,
+			Major:          runtime.VersionMajor,
+			Vendor:         runtime.JavaVendor,
+			Distribution:   runtime.Distribution,
+			RequireLicense: runtime.RequireLicense != nil && *runtime.RequireLicense,
+		}
+		facts.Runtimes = append(facts.Runtimes, rf)
+		facts.RequireLicense = facts.RequireLicense || rf.RequireLicense
+		if runtime.DefaultForUser {
+			defaultIndex = len(facts.Runtimes) - 1
+		}
+		if rf.Major > 0 && !majors[rf.Major] {
+			majors[rf.Major] = true
+			facts.Majors = append(facts.Majors, rf.Major)
+		}
+		if rf.Distribution != "" && !distributions[rf.Distribution] {
+			distributions[rf.Distribution] = true
+			facts.Distributions = append(facts.Distributions, rf.Distribution)
+		}
+	}
+	sort.Ints(facts.Majors)
+	sort.Strings(facts.Distributions)
+
+	if defaultIndex >= 0 {
+		facts.Default = &facts.Runtimes[defaultIndex]
+	}
+	return facts
+}
+
+// renderAnsibleFacts renders the local facts file content (/etc/ansible/facts.d/jfind.fact)
+func renderAnsibleFacts(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	return json.MarshalIndent(newAnsibleFacts(output), "", "  ")
+}
+
+// renderAnsibleInventory renders a dynamic inventory for the scanned host. The host is put into groups by
+// the discovered attributes (jfind_require_license, jfind_oracle, jfind_java_<major>, jfind_dist_<distribution>),
+// inventories of several hosts are merged by the collecting side.
+func renderAnsibleInventory(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	host := output.Meta.ComputerName
+	facts := newAnsibleFacts(output)
+
+	inventory := map[string]interface{}{
+		"_meta": map[string]interface{}{
+			"hostvars": map[string]interface{}{
+				host: map[string]interface{}{"jfind": facts},
+			},
+		},
+		"all": map[string][]string{"hosts": {host}},
+	}
+
+	groups := []string{"jfind_scanned"}
+	if facts.Count > 0 {
+		groups = append(groups, "jfind_java")
+	}
+	if facts.RequireLicense {
+		groups = append(groups, "jfind_require_license")
+	}
+	if facts.HasOracle {
+		groups = append(groups, "jfind_oracle")
+	}
+	for _, major := range facts.Majors {
+		groups = append(groups, fmt.Sprintf("jfind_java_%d", major))
+	}
+	for _, distribution := range facts.Distributions {
+		groups = append(groups, "jfind_dist_"+ansibleGroupName(distribution))
+	}
+	for _, group := range groups {
+		inventory[group] = map[string][]string{"hosts": {host}}
+	}
+
+	return json.MarshalIndent(inventory, "", "  ")
+}
+
+// ansibleGroupName replaces characters not allowed in group names
+func ansibleGroupName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(name))
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAnsibleFacts(t *testing.T) {
+	output := testOutput()
+	output.Meta.HasOracleJDK = true
+	output.Runtimes[0].VersionMajor = 17
+	output.Runtimes[1].DefaultForUser = true
+
+	facts := newAnsibleFacts(output)
+	if facts.Count != 2 || !facts.RequireLicense || !facts.HasOracle {
+		t.Errorf("unexpected summary %+v", facts)
+	}
+	if len(facts.Majors) != 1 || facts.Majors[0] != 17 || len(facts.Distributions) != 1 {
+		t.Errorf("unexpected majors %v or distributions %v", facts.Majors, facts.Distributions)
+	}
+	if facts.Default == nil || facts.Default.Path != "/opt/unknown/bin/java" {
+		t.Errorf("unexpected default runtime %+v", facts.Default)
+	}
+}
+
+func TestAnsibleInventory(t *testing.T) {
+	output := testOutput()
+	output.Runtimes[0].VersionMajor = 17
+
+	data, err := renderAnsibleInventory(output, renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inventory map[string]json.RawMessage
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		t.Fatal(err)
+	}
+	for _, group := range []string{"_meta", "all", "jfind_scanned", "jfind_java", "jfind_require_license", "jfind_java_17", "jfind_dist_oracle"} {
+		if _, ok := inventory[group]; !ok {
+			t.Errorf("missing group %s", group)
+		}
+	}
+	if _, ok := inventory["jfind_oracle"]; ok {
+		t.Error("unexpected jfind_oracle group without Oracle runtime flag")
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+)
+
+// Deviations of a runtime from the baseline
+const (
+	baselineNew     = "new"
+	baselineChanged = "changed"
+	baselineExpired = "expired"
+)
+
+// Baseline is the approved state of the runtimes of a host or fleet. A scan with -baseline reports only the
+// deviations from it, jfind baseline promotes the runtimes of a report to a baseline.
+type Baseline struct {
+	Created  string          `json:"created,omitempty"`
+	Runtimes []BaselineEntry `json:"runtimes"`
+}
+
+// BaselineEntry approves a runtime by the path of its java executable, its java_home or the SHA-256 of the
+// executable. With a path or java_home a SHA-256 and version of the entry must match as well. Exceptions carry a
+// reason and an expiry date, after which the runtime deviates again.
+type BaselineEntry struct {
+	Path     string `json:"path,omitempty"`
+	JavaHome string `json:"java_home,omitempty"`
+	SHA256   string `json:"sha256,omitempty"`
+	Version  string `json:"java_version,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// Expires is the date (YYYY-MM-DD) the approval ends
+	Expires string `json:"expires,omitempty"`
+}
+
+// BaselineSummary is the comparison of a scan with the baseline in the meta section
+type BaselineSummary struct {
+	Approved   int `json:"approved"`
+	Deviations int `json:"deviations"`
+	Missing    int `json:"missing"`
+}
+
+// loadBaseline reads a baseline file
+func loadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %v", err)
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %v", path, err)
+	}
+	for _, entry := range baseline.Runtimes {
+		if entry.Path == "" && entry.JavaHome == "" && entry.SHA256 == "" {
+			return nil, fmt.Errorf("baseline %s: entry without path, java_home or sha256", path)
+		}
+		if entry.Expires != "" {
+			if _, err := time.Parse(time.DateOnly, entry.Expires); err != nil {
+				return nil, fmt.Errorf("baseline %s: invalid expiry date of %s: %v", path, entry.key(), err)
+			}
+		}
+	}
+	return &baseline, nil
+}
+
+// key returns the identifying field of an entry
+func (e BaselineEntry) key() string {
+	switch {
+	case e.Path != "":
+		return e.Path
+	case e.JavaHome != "":
+		return e.JavaHome
+	}
+	return e.SHA256
+}
+
+// expired reports whether the approval of an entry has ended
+func (e BaselineEntry) expired(now time.Time) bool {
+	if e.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse(time.DateOnly, e.Expires)
+	return err == nil && !now.Before(expires.AddDate(0, 0, 1))
+}
+
+// compareBaseline returns the results deviating from the baseline, with their deviation set, and the path and
+// java_home entries matching no runtime of the scan
+func compareBaseline(baseline *Baseline, results []*JavaResult, now time.Time) ([]*JavaResult, []BaselineEntry, BaselineSummary) {
+	matched := make([]bool, len(baseline.Runtimes))
+	hashes := make(map[string]string)
+	hash := func(path string) string {
+		if _, ok := hashes[path]; !ok {
+			hashes[path], _ = fileSHA256(path)
+		}
+		return hashes[path]
+	}
+
+	var deviations []*JavaResult
+	var summary BaselineSummary
+	for _, result := range results {
+		deviation := baselineNew
+		for i, entry := range baseline.Runtimes {
+			switch {
+			case entry.Path != "" && entry.Path != result.Path:
+				continue
+			case entry.Path == "" && entry.JavaHome != "" && entry.JavaHome != result.JavaHome:
+				continue
+			case entry.Path == "" && entry.JavaHome == "" && entry.SHA256 != hash(result.Path):
+				continue
+			}
+			matched[i] = true
+			version := ""
+			if result.Properties != nil {
+				version = result.Properties.Version
+			}
+			switch {
+			case entry.SHA256 != "" && entry.SHA256 != hash(result.Path),
+				entry.Version != "" && version != "" && entry.Version != version:
+				deviation = baselineChanged
+			case entry.expired(now):
+				deviation = baselineExpired
+			default:
+				deviation = ""
+			}
+			if deviation == "" {
+				break
+			}
+		}
+		if deviation == "" {
+			summary.Approved++
+			continue
+		}
+		result.BaselineDeviation = deviation
+		deviations = append(deviations, result)
+	}
+
+	var missing []BaselineEntry
+	for i, entry := range baseline.Runtimes {
+		if !matched[i] && (entry.Path != "" || entry.JavaHome != "") {
+			missing = append(missing, entry)
+		}
+	}
+	summary.Deviations = len(deviations)
+	summary.Missing = len(missing)
+	return deviations, missing, summary
+}
+
+// promoteBaseline creates a baseline approving the runtimes of a report. The SHA-256 of the executables is recorded
+// for a report of this host, the files of another host cannot be read. Reasons and expiry dates of the entries of
+// the previous baseline are kept.
+func promoteBaseline(report *JSONOutput, previous *Baseline, now time.Time) *Baseline {
+	local := report.Meta.ComputerName == getComputerName()
+	if !local {
+		slog.Warn("the report is not of this host, the SHA-256 of the executables is not recorded", "computer_name", report.Meta.ComputerName)
+	}
+
+	kept := make(map[string]BaselineEntry)
+	if previous != nil {
+		for _, entry := range previous.Runtimes {
+			kept[entry.key()] = entry
+		}
+	}
+
+	baseline := &Baseline{Created: now.UTC().Format(time.RFC3339), Runtimes: []BaselineEntry{}}
+	for _, runtime := range report.Runtimes {
+		entry := BaselineEntry{Path: runtime.JavaExecutable, JavaHome: runtime.JavaHome, Version: runtime.JavaVersion}
+		if local {
+			entry.SHA256, _ = fileSHA256(runtime.JavaExecutable)
+		}
+		if old, ok := kept[entry.Path]; ok {
+			entry.Reason, entry.Expires = old.Reason, old.Expires
+		}
+		baseline.Runtimes = append(baseline.Runtimes, entry)
+	}
+	sort.Slice(baseline.Runtimes, func(i, j int) bool { return baseline.Runtimes[i].Path < baseline.Runtimes[j].Path })
+	return baseline
+}
+
+// printBaselineMissing prints the approved runtimes which were not found by the scan
+func printBaselineMissing(missing []BaselineEntry) {
+	if len(missing) == 0 {
+		return
+	}
+	printf("Approved runtimes of the baseline not found:\n")
+	for _, entry := range missing {
+		printf("- %s\n", entry.key())
+	}
+}
+
+// baselineConfig holds the options of the baseline command
+type baselineConfig struct {
+	outputPath string
+	previous   string
+	help       bool
+}
+
+// runBaseline promotes the runtimes of a JSON report to a new baseline
+func runBaseline(args []string) int {
+	var config baselineConfig
+
+	flags := flag.NewFlagSet("baseline", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s baseline [-o <baseline.json>] [-previous <baseline.json>] <report.json|->\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Promote the runtimes of a JSON report to a new baseline for --baseline.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&config.outputPath, "o", "", "File to write the baseline to (default: standard output)")
+	flags.StringVar(&config.previous, "previous", "", "Previous baseline, the reasons and expiry dates of its entries are kept")
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+	_ = flags.Parse(args)
+
+	if config.help || flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	var data []byte
+	var err error
+	if flags.Arg(0) == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(flags.Arg(0))
+	}
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	var report JSONOutput
+	if err := json.Unmarshal(data, &report); err != nil {
+		slog.Error("parsing report", "error", err)
+		return 1
+	}
+
+	var previous *Baseline
+	if config.previous != "" {
+		if previous, err = loadBaseline(config.previous); err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+	}
+
+	baseline, err := json.MarshalIndent(promoteBaseline(&report, previous, time.Now()), "", "  ")
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	if config.outputPath == "" {
+		fmt.Println(string(baseline))
+		return 0
+	}
+	if err := writeFileAtomic(config.outputPath, append(baseline, '\n')); err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	slog.Info("Baseline written", "runtimes", len(report.Runtimes), "path", config.outputPath)
+	return 0
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCompareBaseline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	root := t.TempDir()
+	approved := writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+	patched := writeFakeJava(t, filepath.Join(root, "jdk-21", "bin"))
+	excepted := writeFakeJava(t, filepath.Join(root, "jdk-8", "bin"))
+	unknown := writeFakeJava(t, filepath.Join(root, "jdk-11", "bin"))
+	// the approved executable is also approved by its hash, e.g. a copy
+	copied := filepath.Join(root, "copy", "bin", "java")
+	for _, path := range []string{approved, copied} {
+		writeTestFile(t, path, "#!/bin/sh\n# jdk-17\n")
+	}
+	hash, err := fileSHA256(approved)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseline := &Baseline{Runtimes: []BaselineEntry{
+		{Path: approved, SHA256: hash, Version: "17.0.9"},
+		{JavaHome: filepath.Join(root, "jdk-21"), Version: "21.0.1"},
+		{Path: excepted, Reason: "legacy application", Expires: "2026-06-30"},
+		{SHA256: hash},
+		{Path: filepath.Join(root, "removed", "bin", "java")},
+	}}
+	results := []*JavaResult{
+		{Path: approved, JavaHome: filepath.Join(root, "jdk-17"), Properties: &JavaProperties{Version: "17.0.9"}},
+		{Path: patched, JavaHome: filepath.Join(root, "jdk-21"), Properties: &JavaProperties{Version: "21.0.2"}},
+		{Path: excepted, JavaHome: filepath.Join(root, "jdk-8")},
+		{Path: unknown, JavaHome: filepath.Join(root, "jdk-11")},
+		{Path: copied, JavaHome: filepath.Join(root, "copy")},
+	}
+
+	deviations, missing, summary := compareBaseline(baseline, results, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	got := make(map[string]string)
+	for _, result := range deviations {
+		got[result.Path] = result.BaselineDeviation
+	}
+	want := map[string]string{patched: baselineChanged, excepted: baselineExpired, unknown: baselineNew}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected deviations %v", got)
+	}
+	if len(missing) != 1 || missing[0].Path != filepath.Join(root, "removed", "bin", "java") {
+		t.Errorf("unexpected missing entries %+v", missing)
+	}
+	if summary != (BaselineSummary{Approved: 2, Deviations: 3, Missing: 1}) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	// the exception is valid until the end of its expiry date
+	results[2].BaselineDeviation = ""
+	deviations, _, _ = compareBaseline(baseline, results[2:3], time.Date(2026, 6, 30, 23, 0, 0, 0, time.UTC))
+	if len(deviations) != 0 {
+		t.Errorf("expected the exception to be valid, got %v", deviations[0].BaselineDeviation)
+	}
+}
+
+func TestPromoteBaseline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	root := t.TempDir()
+	java := writeFakeJava(t, filepath.Join(root, "jdk-8", "bin"))
+	report := &JSONOutput{Meta: MetaInfo{ComputerName: getComputerName()}, Runtimes: []JavaRuntimeJSON{
+		{JavaExecutable: java, JavaHome: filepath.Join(root, "jdk-8"), JavaVersion: "1.8.0_202"},
+		{JavaExecutable: "/gone/bin/java"},
+	}}
+	previous := &Baseline{Runtimes: []BaselineEntry{{Path: java, Reason: "legacy application", Expires: "2027-01-31"}}}
+
+	baseline := promoteBaseline(report, previous, time.Now())
+	if len(baseline.Runtimes) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", baseline.Runtimes)
+	}
+	gone, entry := baseline.Runtimes[0], baseline.Runtimes[1]
+	if gone.Path != "/gone/bin/java" || gone.SHA256 != "" {
+		t.Errorf("unexpected entry of a missing executable %+v", gone)
+	}
+	if entry.SHA256 == "" || entry.Version != "1.8.0_202" || entry.Reason != "legacy application" || entry.Expires != "2027-01-31" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	// a promoted baseline approves the scan it was created from
+	path := filepath.Join(root, "baseline.json")
+	data := `{"runtimes": [{"path": "` + java + `", "sha256": "` + entry.SHA256 + `"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deviations, _, _ := compareBaseline(loaded, []*JavaResult{{Path: java}}, time.Now()); len(deviations) != 0 {
+		t.Errorf("expected no deviations, got %d", len(deviations))
+	}
+
+	// the files at the paths of a report of another host are not the scanned executables
+	report.Meta.ComputerName = "other-host"
+	if entry := promoteBaseline(report, nil, time.Now()).Runtimes[1]; entry.Path != java || entry.SHA256 != "" {
+		t.Errorf("expected no SHA-256 for the report of another host, got %+v", entry)
+	}
+}
+
+func TestLoadBaselineInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"empty-entry.json": `{"runtimes": [{"reason": "no key"}]}`,
+		"expiry.json":      `{"runtimes": [{"path": "/opt/jdk/bin/java", "expires": "next year"}]}`,
+		"syntax.json":      `{"runtimes": [`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadBaseline(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
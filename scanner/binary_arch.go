@@ -0,0 +1,129 @@
+package main
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Architecture names as used by macOS tooling
+const (
+	archX86_64 = "x86_64"
+	archARM64  = "arm64"
+)
+
+// BinaryArch describes the CPU architectures of a java executable
+type BinaryArch struct {
+	Arches          []string
+	IsUniversal     bool
+	RequiresRosetta bool
+}
+
+// isAppleSilicon reports whether the host is an Apple Silicon Mac. The check does not rely on
+// runtime.GOARCH, because an x86_64 build of jfind itself may be running under Rosetta.
+var isAppleSilicon = sync.OnceValue(func() bool {
+	if runtime.GOOS != "darwin" {
+		return false
+	}
+	out, err := exec.Command("sysctl", "-n", "hw.optional.arm64").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "1"
+})
+
+// inspectBinaryArch reads the architectures from the executable headers without executing it
+func inspectBinaryArch(path string) *BinaryArch {
+	arches, universal, err := binaryArchitectures(path)
+	if err != nil || len(arches) == 0 {
+		return nil
+	}
+
+	arch := &BinaryArch{Arches: arches, IsUniversal: universal}
+	if isAppleSilicon() && containsString(arches, archX86_64) && !containsString(arches, archARM64) {
+		arch.RequiresRosetta = true
+	}
+	return arch
+}
+
+// binaryArchitectures returns the architectures of a Mach-O (including universal), ELF or PE executable
+func binaryArchitectures(path string) ([]string, bool, error) {
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer func() { _ = fat.Close() }()
+		arches := make([]string, 0, len(fat.Arches))
+		for _, arch := range fat.Arches {
+			arches = append(arches, machoArch(arch.Cpu))
+		}
+		return arches, true, nil
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer func() { _ = f.Close() }()
+		return []string{machoArch(f.Cpu)}, false, nil
+	}
+	if f, err := elf.Open(path); err == nil {
+		defer func() { _ = f.Close() }()
+		return []string{elfArch(f.Machine)}, false, nil
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer func() { _ = f.Close() }()
+		return []string{peArch(f.Machine)}, false, nil
+	}
+	return nil, false, errors.New("unknown executable format")
+}
+
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return archX86_64
+	case macho.CpuArm64:
+		return archARM64
+	case macho.Cpu386:
+		return "i386"
+	case macho.CpuPpc, macho.CpuPpc64:
+		return "ppc"
+	}
+	return strings.ToLower(cpu.String())
+}
+
+func elfArch(machine elf.Machine) string {
+	switch machine {
+	case elf.EM_X86_64:
+		return archX86_64
+	case elf.EM_AARCH64:
+		return archARM64
+	case elf.EM_386:
+		return "i386"
+	case elf.EM_ARM:
+		return "arm"
+	case elf.EM_PPC64:
+		return "ppc64"
+	case elf.EM_S390:
+		return "s390x"
+	case elf.EM_RISCV:
+		return "riscv64"
+	}
+	return strings.ToLower(strings.TrimPrefix(machine.String(), "EM_"))
+}
+
+func peArch(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return archX86_64
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return archARM64
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "i386"
+	}
+	return "unknown"
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchNames(t *testing.T) {
+	if got := machoArch(macho.CpuArm64); got != archARM64 {
+		t.Errorf("machoArch(arm64) = %s", got)
+	}
+	if got := machoArch(macho.CpuAmd64); got != archX86_64 {
+		t.Errorf("machoArch(amd64) = %s", got)
+	}
+	if got := elfArch(elf.EM_AARCH64); got != archARM64 {
+		t.Errorf("elfArch(aarch64) = %s", got)
+	}
+	if got := elfArch(elf.EM_X86_64); got != archX86_64 {
+		t.Errorf("elfArch(x86_64) = %s", got)
+	}
+}
+
+func TestBinaryArchitecturesUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "java")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho java\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := binaryArchitectures(path); err == nil {
+		t.Error("expected error for shell script")
+	}
+	if inspectBinaryArch(path) != nil {
+		t.Error("expected no architecture for shell script")
+	}
+}
+
+func TestBinaryArchitecturesTestBinary(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	arches, universal, err := binaryArchitectures(exe)
+	if err != nil {
+		t.Fatalf("reading test binary: %v", err)
+	}
+	if len(arches) == 0 || arches[0] == "unknown" {
+		t.Errorf("unexpected architectures %v", arches)
+	}
+	if universal {
+		t.Error("test binary is not a universal binary")
+	}
+}
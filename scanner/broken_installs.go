@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+)
+
+// Status of suspected installations
+const (
+	// suspectedPartial installations have no java executable at all
+	suspectedPartial = "partial"
+	// suspectedBroken installations have a java executable which cannot be run
+	suspectedBroken = "broken"
+)
+
+// installDirPattern matches the directory names of JDK and JRE installations and extracted archives,
+// e.g. jdk1.8.0_202, jdk-17.0.2, jdk-17.0.2.jdk, zulu11.50.19-ca-jdk11.0.12-linux_x64 or java-17-openjdk-amd64
+var installDirPattern = regexp.MustCompile(`(?i)^((jdk|jre|openjdk|zulu|temurin|amazon-corretto|corretto|graalvm(-ce|-community)?(-jdk)?|` +
+	`microsoft-jdk|bellsoft-jdk|liberica-jdk|sapmachine|ibm-semeru|semeru)[-_]?\d[\w.+-]*|java-\d+-openjdk[\w.+-]*)$`)
+
+// installMarkers are present in every installation and left behind by a partial one: the release file, the modules
+// image of Java 9+, the rt.jar of Java 8 and older, or the bin directory. Directories named like an installation
+// without any of them, e.g. /etc/java-17-openjdk or /usr/share/doc/openjdk-17-jre-headless, are not suspected.
+var installMarkers = []string{
+	"release",
+	filepath.Join("lib", "modules"),
+	filepath.Join("lib", "rt.jar"),
+	filepath.Join("jre", "lib", "rt.jar"),
+	"bin",
+}
+
+// SuspectedInstall is a directory named like a Java installation without a usable java executable,
+// usually the leftover of an uninstallation or an aborted extraction
+type SuspectedInstall struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// javaExecutableName returns the file name of the java executable on this platform
+func javaExecutableName() string {
+	if runtime.GOOS == "windows" {
+		return "java.exe"
+	}
+	return "java"
+}
+
+// hasInstallMarker reports whether a directory contains one of the installMarkers, directly, in the Contents/Home
+// of a macOS bundle or in a directory nested by the extraction of an archive
+func hasInstallMarker(dir string) bool {
+	for _, marker := range installMarkers {
+		for _, pattern := range []string{
+			filepath.Join(dir, marker),
+			filepath.Join(dir, "Contents", "Home", marker),
+			filepath.Join(dir, "*", marker),
+		} {
+			if matches, err := filepath.Glob(pattern); err == nil && len(matches) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkInstallDir returns the suspected installation of a directory named like a Java installation,
+// or false if the name does not match, the directory is not an installation or it has a usable java executable
+func checkInstallDir(dir string) (SuspectedInstall, bool) {
+	if !installDirPattern.MatchString(filepath.Base(dir)) || !hasInstallMarker(dir) {
+		return SuspectedInstall{}, false
+	}
+
+	java := javaExecutableName()
+	candidates := []string{
+		filepath.Join(dir, "bin", java),
+		filepath.Join(dir, "jre", "bin", java),
+		filepath.Join(dir, "Contents", "Home", "bin", java),
+	}
+	// archives extracted into a directory of the same name nest the installation one level deeper
+	if nested, err := filepath.Glob(filepath.Join(dir, "*", "bin", java)); err == nil {
+		candidates = append(candidates, nested...)
+	}
+
+	var unusable string
+	for _, candidate := range candidates {
+		if _, err := os.Lstat(candidate); err != nil {
+			continue
+		}
+		// reasons are relative to the installation, so path redaction applies to them as well
+		rel, _ := filepath.Rel(dir, candidate)
+		target, err := os.Stat(candidate)
+		switch {
+		case err != nil:
+			unusable = rel + " is a dangling link"
+		case target.IsDir():
+			unusable = rel + " is a directory"
+		case target.Size() == 0:
+			unusable = rel + " is empty"
+		case !isExecutable(target):
+			unusable = rel + " is not executable"
+		default:
+			return SuspectedInstall{}, false
+		}
+	}
+
+	if unusable != "" {
+		return SuspectedInstall{Path: dir, Status: suspectedBroken, Reason: unusable}, true
+	}
+	return SuspectedInstall{Path: dir, Status: suspectedPartial, Reason: "no bin/" + java}, true
+}
+
+// checkSuspected records the directory if it looks like a broken or partial installation, it is called by the walkers
+func (f *JavaFinder) checkSuspected(dir string) {
+	suspected, ok := checkInstallDir(dir)
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	f.suspected = append(f.suspected, suspected)
+	f.mu.Unlock()
+}
+
+// sortSuspected sorts suspected installations by path
+func sortSuspected(suspected []SuspectedInstall) []SuspectedInstall {
+	sort.Slice(suspected, func(i, j int) bool { return suspected[i].Path < suspected[j].Path })
+	return suspected
+}
+
+// printSuspected prints the suspected installations in text output mode
+func printSuspected(suspected []SuspectedInstall) {
+	if len(suspected) == 0 {
+		return
+	}
+	printf("Suspected broken or partial installations:\n")
+	for _, s := range suspected {
+		printf("- %s: %s (%s)\n", s.Path, s.Status, s.Reason)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestInstallDirPattern(t *testing.T) {
+	for _, name := range []string{"jdk1.8.0_202", "jdk-17.0.2", "jdk-17.0.2.jdk", "jre1.8.0_351", "zulu11.50.19-ca-jdk11.0.12-linux_x64",
+		"java-17-openjdk-amd64", "amazon-corretto-17.0.9.8.1-linux-x64", "openjdk-21"} {
+		if !installDirPattern.MatchString(name) {
+			t.Errorf("expected %s to match", name)
+		}
+	}
+	for _, name := range []string{"jdk", "jre", "jdk-utils", "openjdk", "java", "bin", "jdkmanager-2"} {
+		if installDirPattern.MatchString(name) {
+			t.Errorf("unexpected match %s", name)
+		}
+	}
+}
+
+func TestCheckInstallDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX permissions and symlinks")
+	}
+
+	root := t.TempDir()
+	ok := filepath.Join(root, "jdk-17.0.2")
+	writeFakeJava(t, filepath.Join(ok, "bin"))
+	nested := filepath.Join(root, "jdk-21.0.1")
+	writeFakeJava(t, filepath.Join(nested, "jdk-21.0.1", "bin"))
+	partial := filepath.Join(root, "jdk1.8.0_202")
+	if err := os.MkdirAll(filepath.Join(partial, "jre", "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(partial, "jre", "lib", "rt.jar"), []byte("PK"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Debian keeps the configuration and documentation of a package in directories named like the installation
+	config := filepath.Join(root, "etc", "java-17-openjdk")
+	doc := filepath.Join(root, "doc", "openjdk-17-jre-headless")
+	for _, file := range []string{filepath.Join(config, "security", "java.security"), filepath.Join(config, "jvm.cfg"),
+		filepath.Join(doc, "changelog.Debian.gz")} {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	broken := filepath.Join(root, "jre1.8.0_351")
+	writeFakeJava(t, filepath.Join(broken, "bin"))
+	if err := os.Chmod(filepath.Join(broken, "bin", "java"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dangling := filepath.Join(root, "zulu17.46.19-ca-jdk17.0.9-linux_x64")
+	if err := os.MkdirAll(filepath.Join(dangling, "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "missing"), filepath.Join(dangling, "bin", "java")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{ok, nested, filepath.Join(root, "other"), config, doc} {
+		if s, found := checkInstallDir(dir); found {
+			t.Errorf("unexpected suspected installation %+v", s)
+		}
+	}
+	expected := map[string]SuspectedInstall{
+		partial:  {Path: partial, Status: suspectedPartial, Reason: "no bin/java"},
+		broken:   {Path: broken, Status: suspectedBroken, Reason: filepath.Join("bin", "java") + " is not executable"},
+		dangling: {Path: dangling, Status: suspectedBroken, Reason: filepath.Join("bin", "java") + " is a dangling link"},
+	}
+	for dir, want := range expected {
+		if s, found := checkInstallDir(dir); !found || s != want {
+			t.Errorf("checkInstallDir(%s) = %+v, %v", dir, s, found)
+		}
+	}
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the dangling link is found by the walk as well, its evaluation fails
+		if len(results) != 3 || len(finder.suspected) != 3 {
+			t.Errorf("threads %d: expected 3 results and 3 suspected installations, got %d and %+v",
+				threads, len(results), finder.suspected)
+		}
+	}
+}
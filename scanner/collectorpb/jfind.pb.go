@@ -0,0 +1,738 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.28.0
+// source: jfind.proto
+
+package collectorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubmitScanRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scan *Scan `protobuf:"bytes,1,opt,name=scan,proto3" json:"scan,omitempty"`
+}
+
+func (x *SubmitScanRequest) Reset() {
+	*x = SubmitScanRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jfind_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitScanRequest) ProtoMessage() {}
+
+func (x *SubmitScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_jfind_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitScanRequest.ProtoReflect.Descriptor instead.
+func (*SubmitScanRequest) Descriptor() ([]byte, []int) {
+	return file_jfind_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubmitScanRequest) GetScan() *Scan {
+	if x != nil {
+		return x.Scan
+	}
+	return nil
+}
+
+type SubmitScanResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ScanId int64 `protobuf:"varint,1,opt,name=scan_id,json=scanId,proto3" json:"scan_id,omitempty"`
+}
+
+func (x *SubmitScanResponse) Reset() {
+	*x = SubmitScanResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jfind_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitScanResponse) ProtoMessage() {}
+
+func (x *SubmitScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_jfind_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitScanResponse.ProtoReflect.Descriptor instead.
+func (*SubmitScanResponse) Descriptor() ([]byte, []int) {
+	return file_jfind_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitScanResponse) GetScanId() int64 {
+	if x != nil {
+		return x.ScanId
+	}
+	return 0
+}
+
+type Scan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Meta       *ScanMeta  `protobuf:"bytes,1,opt,name=meta,proto3" json:"meta,omitempty"`
+	Runtimes   []*Runtime `protobuf:"bytes,2,rep,name=runtimes,proto3" json:"runtimes,omitempty"`
+	ReportJson []byte     `protobuf:"bytes,3,opt,name=report_json,json=reportJson,proto3" json:"report_json,omitempty"`
+}
+
+func (x *Scan) Reset() {
+	*x = Scan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jfind_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Scan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scan) ProtoMessage() {}
+
+func (x *Scan) ProtoReflect() protoreflect.Message {
+	mi := &file_jfind_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scan.ProtoReflect.Descriptor instead.
+func (*Scan) Descriptor() ([]byte, []int) {
+	return file_jfind_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Scan) GetMeta() *ScanMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *Scan) GetRuntimes() []*Runtime {
+	if x != nil {
+		return x.Runtimes
+	}
+	return nil
+}
+
+func (x *Scan) GetReportJson() []byte {
+	if x != nil {
+		return x.ReportJson
+	}
+	return nil
+}
+
+type ScanMeta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ScanTs                 string `protobuf:"bytes,1,opt,name=scan_ts,json=scanTs,proto3" json:"scan_ts,omitempty"`
+	ComputerName           string `protobuf:"bytes,2,opt,name=computer_name,json=computerName,proto3" json:"computer_name,omitempty"`
+	UserName               string `protobuf:"bytes,3,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	ScanDuration           string `protobuf:"bytes,4,opt,name=scan_duration,json=scanDuration,proto3" json:"scan_duration,omitempty"`
+	HasOracleJdk           bool   `protobuf:"varint,5,opt,name=has_oracle_jdk,json=hasOracleJdk,proto3" json:"has_oracle_jdk,omitempty"`
+	CountResult            int32  `protobuf:"varint,6,opt,name=count_result,json=countResult,proto3" json:"count_result,omitempty"`
+	CountRequireLicense    int32  `protobuf:"varint,7,opt,name=count_require_license,json=countRequireLicense,proto3" json:"count_require_license,omitempty"`
+	ScannedDirs            int32  `protobuf:"varint,8,opt,name=scanned_dirs,json=scannedDirs,proto3" json:"scanned_dirs,omitempty"`
+	ScanPath               string `protobuf:"bytes,9,opt,name=scan_path,json=scanPath,proto3" json:"scan_path,omitempty"`
+	PlatformInfo           string `protobuf:"bytes,10,opt,name=platform_info,json=platformInfo,proto3" json:"platform_info,omitempty"`
+	HasEntitlementEvidence bool   `protobuf:"varint,11,opt,name=has_entitlement_evidence,json=hasEntitlementEvidence,proto3" json:"has_entitlement_evidence,omitempty"`
+	Privileged             bool   `protobuf:"varint,12,opt,name=privileged,proto3" json:"privileged,omitempty"`
+	ScanProfile            string `protobuf:"bytes,13,opt,name=scan_profile,json=scanProfile,proto3" json:"scan_profile,omitempty"`
+}
+
+func (x *ScanMeta) Reset() {
+	*x = ScanMeta{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jfind_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScanMeta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanMeta) ProtoMessage() {}
+
+func (x *ScanMeta) ProtoReflect() protoreflect.Message {
+	mi := &file_jfind_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanMeta.ProtoReflect.Descriptor instead.
+func (*ScanMeta) Descriptor() ([]byte, []int) {
+	return file_jfind_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ScanMeta) GetScanTs() string {
+	if x != nil {
+		return x.ScanTs
+	}
+	return ""
+}
+
+func (x *ScanMeta) GetComputerName() string {
+	if x != nil {
+		return x.ComputerName
+	}
+	return ""
+}
+
+func (x *ScanMeta) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *ScanMeta) GetScanDuration() string {
+	if x != nil {
+		return x.ScanDuration
+	}
+	return ""
+}
+
+func (x *ScanMeta) GetHasOracleJdk() bool {
+	if x != nil {
+		return x.HasOracleJdk
+	}
+	return false
+}
+
+func (x *ScanMeta) GetCountResult() int32 {
+	if x != nil {
+		return x.CountResult
+	}
+	return 0
+}
+
+func (x *ScanMeta) GetCountRequireLicense() int32 {
+	if x != nil {
+		return x.CountRequireLicense
+	}
+	return 0
+}
+
+func (x *ScanMeta) GetScannedDirs() int32 {
+	if x != nil {
+		return x.ScannedDirs
+	}
+	return 0
+}
+
+func (x *ScanMeta) GetScanPath() string {
+	if x != nil {
+		return x.ScanPath
+	}
+	return ""
+}
+
+func (x *ScanMeta) GetPlatformInfo() string {
+	if x != nil {
+		return x.PlatformInfo
+	}
+	return ""
+}
+
+func (x *ScanMeta) GetHasEntitlementEvidence() bool {
+	if x != nil {
+		return x.HasEntitlementEvidence
+	}
+	return false
+}
+
+func (x *ScanMeta) GetPrivileged() bool {
+	if x != nil {
+		return x.Privileged
+	}
+	return false
+}
+
+func (x *ScanMeta) GetScanProfile() string {
+	if x != nil {
+		return x.ScanProfile
+	}
+	return ""
+}
+
+type Runtime struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JavaExecutable    string   `protobuf:"bytes,1,opt,name=java_executable,json=javaExecutable,proto3" json:"java_executable,omitempty"`
+	JavaHome          string   `protobuf:"bytes,2,opt,name=java_home,json=javaHome,proto3" json:"java_home,omitempty"`
+	JavaRuntime       string   `protobuf:"bytes,3,opt,name=java_runtime,json=javaRuntime,proto3" json:"java_runtime,omitempty"`
+	JavaVendor        string   `protobuf:"bytes,4,opt,name=java_vendor,json=javaVendor,proto3" json:"java_vendor,omitempty"`
+	Distribution      string   `protobuf:"bytes,5,opt,name=distribution,proto3" json:"distribution,omitempty"`
+	Edition           string   `protobuf:"bytes,6,opt,name=edition,proto3" json:"edition,omitempty"`
+	IsOracle          bool     `protobuf:"varint,7,opt,name=is_oracle,json=isOracle,proto3" json:"is_oracle,omitempty"`
+	JavaVersion       string   `protobuf:"bytes,8,opt,name=java_version,json=javaVersion,proto3" json:"java_version,omitempty"`
+	JavaVersionMajor  int32    `protobuf:"varint,9,opt,name=java_version_major,json=javaVersionMajor,proto3" json:"java_version_major,omitempty"`
+	JavaVersionUpdate int32    `protobuf:"varint,10,opt,name=java_version_update,json=javaVersionUpdate,proto3" json:"java_version_update,omitempty"`
+	JavaVersionDate   string   `protobuf:"bytes,11,opt,name=java_version_date,json=javaVersionDate,proto3" json:"java_version_date,omitempty"`
+	ExecFailed        bool     `protobuf:"varint,12,opt,name=exec_failed,json=execFailed,proto3" json:"exec_failed,omitempty"`
+	VersionSource     string   `protobuf:"bytes,13,opt,name=version_source,json=versionSource,proto3" json:"version_source,omitempty"`
+	RequireLicense    *bool    `protobuf:"varint,14,opt,name=require_license,json=requireLicense,proto3,oneof" json:"require_license,omitempty"`
+	LicenseReason     string   `protobuf:"bytes,15,opt,name=license_reason,json=licenseReason,proto3" json:"license_reason,omitempty"`
+	LicenseFreeUntil  string   `protobuf:"bytes,16,opt,name=license_free_until,json=licenseFreeUntil,proto3" json:"license_free_until,omitempty"`
+	BinaryArch        []string `protobuf:"bytes,17,rep,name=binary_arch,json=binaryArch,proto3" json:"binary_arch,omitempty"`
+	Symlinks          []string `protobuf:"bytes,18,rep,name=symlinks,proto3" json:"symlinks,omitempty"`
+	Aliases           []string `protobuf:"bytes,19,rep,name=aliases,proto3" json:"aliases,omitempty"`
+}
+
+func (x *Runtime) Reset() {
+	*x = Runtime{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_jfind_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Runtime) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Runtime) ProtoMessage() {}
+
+func (x *Runtime) ProtoReflect() protoreflect.Message {
+	mi := &file_jfind_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Runtime.ProtoReflect.Descriptor instead.
+func (*Runtime) Descriptor() ([]byte, []int) {
+	return file_jfind_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Runtime) GetJavaExecutable() string {
+	if x != nil {
+		return x.JavaExecutable
+	}
+	return ""
+}
+
+func (x *Runtime) GetJavaHome() string {
+	if x != nil {
+		return x.JavaHome
+	}
+	return ""
+}
+
+func (x *Runtime) GetJavaRuntime() string {
+	if x != nil {
+		return x.JavaRuntime
+	}
+	return ""
+}
+
+func (x *Runtime) GetJavaVendor() string {
+	if x != nil {
+		return x.JavaVendor
+	}
+	return ""
+}
+
+func (x *Runtime) GetDistribution() string {
+	if x != nil {
+		return x.Distribution
+	}
+	return ""
+}
+
+func (x *Runtime) GetEdition() string {
+	if x != nil {
+		return x.Edition
+	}
+	return ""
+}
+
+func (x *Runtime) GetIsOracle() bool {
+	if x != nil {
+		return x.IsOracle
+	}
+	return false
+}
+
+func (x *Runtime) GetJavaVersion() string {
+	if x != nil {
+		return x.JavaVersion
+	}
+	return ""
+}
+
+func (x *Runtime) GetJavaVersionMajor() int32 {
+	if x != nil {
+		return x.JavaVersionMajor
+	}
+	return 0
+}
+
+func (x *Runtime) GetJavaVersionUpdate() int32 {
+	if x != nil {
+		return x.JavaVersionUpdate
+	}
+	return 0
+}
+
+func (x *Runtime) GetJavaVersionDate() string {
+	if x != nil {
+		return x.JavaVersionDate
+	}
+	return ""
+}
+
+func (x *Runtime) GetExecFailed() bool {
+	if x != nil {
+		return x.ExecFailed
+	}
+	return false
+}
+
+func (x *Runtime) GetVersionSource() string {
+	if x != nil {
+		return x.VersionSource
+	}
+	return ""
+}
+
+func (x *Runtime) GetRequireLicense() bool {
+	if x != nil && x.RequireLicense != nil {
+		return *x.RequireLicense
+	}
+	return false
+}
+
+func (x *Runtime) GetLicenseReason() string {
+	if x != nil {
+		return x.LicenseReason
+	}
+	return ""
+}
+
+func (x *Runtime) GetLicenseFreeUntil() string {
+	if x != nil {
+		return x.LicenseFreeUntil
+	}
+	return ""
+}
+
+func (x *Runtime) GetBinaryArch() []string {
+	if x != nil {
+		return x.BinaryArch
+	}
+	return nil
+}
+
+func (x *Runtime) GetSymlinks() []string {
+	if x != nil {
+		return x.Symlinks
+	}
+	return nil
+}
+
+func (x *Runtime) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+var File_jfind_proto protoreflect.FileDescriptor
+
+var file_jfind_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x6a, 0x66, 0x69, 0x6e, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x6a,
+	0x66, 0x69, 0x6e, 0x64, 0x2e, 0x76, 0x31, 0x22, 0x37, 0x0a, 0x11, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x04,
+	0x73, 0x63, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6a, 0x66, 0x69,
+	0x6e, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x63, 0x61, 0x6e,
+	0x22, 0x2d, 0x0a, 0x12, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x63, 0x61, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x63, 0x61, 0x6e, 0x49, 0x64, 0x22,
+	0x7e, 0x0a, 0x04, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6a, 0x66, 0x69, 0x6e, 0x64, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x63, 0x61, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61, 0x12,
+	0x2d, 0x0a, 0x08, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x6a, 0x66, 0x69, 0x6e, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e,
+	0x74, 0x69, 0x6d, 0x65, 0x52, 0x08, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x4a, 0x73, 0x6f, 0x6e, 0x22,
+	0xe9, 0x03, 0x0a, 0x08, 0x53, 0x63, 0x61, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x17, 0x0a, 0x07,
+	0x73, 0x63, 0x61, 0x6e, 0x5f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x63, 0x61, 0x6e, 0x54, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65,
+	0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f,
+	0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75,
+	0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x63, 0x61, 0x6e, 0x5f,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x73, 0x63, 0x61, 0x6e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0e,
+	0x68, 0x61, 0x73, 0x5f, 0x6f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x5f, 0x6a, 0x64, 0x6b, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x68, 0x61, 0x73, 0x4f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x4a,
+	0x64, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x4c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x63, 0x61,
+	0x6e, 0x6e, 0x65, 0x64, 0x5f, 0x64, 0x69, 0x72, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x73, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x44, 0x69, 0x72, 0x73, 0x12, 0x1b, 0x0a, 0x09,
+	0x73, 0x63, 0x61, 0x6e, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x63, 0x61, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x38,
+	0x0a, 0x18, 0x68, 0x61, 0x73, 0x5f, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x65, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x16, 0x68, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x45, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x76,
+	0x69, 0x6c, 0x65, 0x67, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x72,
+	0x69, 0x76, 0x69, 0x6c, 0x65, 0x67, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x63, 0x61, 0x6e,
+	0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x73, 0x63, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x22, 0xd1, 0x05, 0x0a, 0x07,
+	0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x6a, 0x61, 0x76, 0x61, 0x5f,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x6a, 0x61, 0x76, 0x61, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x6a, 0x61, 0x76, 0x61, 0x5f, 0x68, 0x6f, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x61, 0x76, 0x61, 0x48, 0x6f, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x6a, 0x61, 0x76, 0x61, 0x5f, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x6a, 0x61, 0x76, 0x61, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x6a, 0x61, 0x76, 0x61, 0x5f, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6a, 0x61, 0x76, 0x61, 0x56, 0x65, 0x6e, 0x64, 0x6f,
+	0x72, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x6f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x4f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x6a, 0x61, 0x76, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x6a, 0x61, 0x76, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x2c, 0x0a, 0x12, 0x6a, 0x61, 0x76, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6d, 0x61, 0x6a, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x6a, 0x61, 0x76,
+	0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x61, 0x6a, 0x6f, 0x72, 0x12, 0x2e, 0x0a,
+	0x13, 0x6a, 0x61, 0x76, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x6a, 0x61, 0x76, 0x61,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a,
+	0x11, 0x6a, 0x61, 0x76, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6a, 0x61, 0x76, 0x61, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x78, 0x65,
+	0x63, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x65, 0x78, 0x65, 0x63, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x2c, 0x0a, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x6c, 0x69, 0x63,
+	0x65, 0x6e, 0x73, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0e, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x4c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x25, 0x0a, 0x0e, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65,
+	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x12, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73,
+	0x65, 0x5f, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x10, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x46, 0x72, 0x65, 0x65, 0x55,
+	0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f, 0x61,
+	0x72, 0x63, 0x68, 0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x69, 0x6e, 0x61, 0x72,
+	0x79, 0x41, 0x72, 0x63, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x13, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x42, 0x12, 0x0a, 0x10, 0x5f,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x32,
+	0x54, 0x0a, 0x09, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x47, 0x0a, 0x0a,
+	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x12, 0x1b, 0x2e, 0x6a, 0x66, 0x69,
+	0x6e, 0x64, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x63, 0x61, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6a, 0x66, 0x69, 0x6e, 0x64, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x63, 0x61, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x13, 0x5a, 0x11, 0x6a, 0x66, 0x69, 0x6e, 0x64, 0x2f, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_jfind_proto_rawDescOnce sync.Once
+	file_jfind_proto_rawDescData = file_jfind_proto_rawDesc
+)
+
+func file_jfind_proto_rawDescGZIP() []byte {
+	file_jfind_proto_rawDescOnce.Do(func() {
+		file_jfind_proto_rawDescData = protoimpl.X.CompressGZIP(file_jfind_proto_rawDescData)
+	})
+	return file_jfind_proto_rawDescData
+}
+
+var file_jfind_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_jfind_proto_goTypes = []any{
+	(*SubmitScanRequest)(nil),  // 0: jfind.v1.SubmitScanRequest
+	(*SubmitScanResponse)(nil), // 1: jfind.v1.SubmitScanResponse
+	(*Scan)(nil),               // 2: jfind.v1.Scan
+	(*ScanMeta)(nil),           // 3: jfind.v1.ScanMeta
+	(*Runtime)(nil),            // 4: jfind.v1.Runtime
+}
+var file_jfind_proto_depIdxs = []int32{
+	2, // 0: jfind.v1.SubmitScanRequest.scan:type_name -> jfind.v1.Scan
+	3, // 1: jfind.v1.Scan.meta:type_name -> jfind.v1.ScanMeta
+	4, // 2: jfind.v1.Scan.runtimes:type_name -> jfind.v1.Runtime
+	0, // 3: jfind.v1.Collector.SubmitScan:input_type -> jfind.v1.SubmitScanRequest
+	1, // 4: jfind.v1.Collector.SubmitScan:output_type -> jfind.v1.SubmitScanResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_jfind_proto_init() }
+func file_jfind_proto_init() {
+	if File_jfind_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_jfind_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitScanRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jfind_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SubmitScanResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jfind_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Scan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jfind_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ScanMeta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_jfind_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*Runtime); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_jfind_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_jfind_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_jfind_proto_goTypes,
+		DependencyIndexes: file_jfind_proto_depIdxs,
+		MessageInfos:      file_jfind_proto_msgTypes,
+	}.Build()
+	File_jfind_proto = out.File
+	file_jfind_proto_rawDesc = nil
+	file_jfind_proto_goTypes = nil
+	file_jfind_proto_depIdxs = nil
+}
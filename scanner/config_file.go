@@ -0,0 +1,165 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the content of the jfind configuration file, in YAML or, with the extension .toml, in TOML
+type fileConfig struct {
+	// Settings are the top-level keys, they set flags by their name like a profile, e.g. eval: true
+	Settings map[string]any `yaml:",inline"`
+	// Profiles map profile names to flag settings, e.g. threads: auto or exclude: [node_modules]
+	Profiles map[string]map[string]any `yaml:"profiles"`
+	// Roots are scanned concurrently when no path is given on the command line, each with its own depth, excludes
+	// and profile
+	Roots []rootConfig `yaml:"roots"`
+	// ExitCodes map finding categories to exit codes, e.g. policy-violation: 2
+	ExitCodes map[string]int `yaml:"exit_codes"`
+}
+
+// configSearchPaths returns the configuration files used without --config, the first existing one is loaded
+func configSearchPaths() []string {
+	var dirs []string
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			dirs = append(dirs, filepath.Join(dir, "jfind"))
+		}
+	} else {
+		dirs = append(dirs, "/etc/jfind")
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "jfind"))
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		paths = append(paths, filepath.Join(dir, "jfind.yaml"), filepath.Join(dir, "jfind.toml"))
+	}
+	return paths
+}
+
+// loadConfigFile reads the configuration file at path, or the first existing file of the search paths if path is
+// empty. It returns nil without error if there is no configuration file.
+func loadConfigFile(path string) (*fileConfig, error) {
+	if path == "" {
+		for _, candidate := range configSearchPaths() {
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration file: %v", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		// TOML is converted to YAML to decode both formats into the same structure
+		var content map[string]any
+		if err := toml.Unmarshal(data, &content); err != nil {
+			return nil, fmt.Errorf("parsing configuration file %s: %v", path, err)
+		}
+		if data, err = yaml.Marshal(content); err != nil {
+			return nil, fmt.Errorf("parsing configuration file %s: %v", path, err)
+		}
+	}
+	var config fileConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing configuration file %s: %v", path, err)
+	}
+	return &config, nil
+}
+
+// profileNames returns the built-in profiles and the profiles of the configuration file
+func (c *fileConfig) profileNames() []string {
+	names := append([]string{}, scanProfiles...)
+	if c != nil {
+		for name := range c.Profiles {
+			if validateProfile(name) != nil {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile sets the flags of a profile that are not set on the command line, the command line takes
+// precedence. Lists set repeatable flags once per element. A profile named like a built-in profile extends it.
+func (c *fileConfig) applyProfile(flags *flag.FlagSet, name string) error {
+	settings, ok := map[string]any(nil), false
+	if c != nil {
+		settings, ok = c.Profiles[name]
+	}
+	if !ok {
+		if validateProfile(name) == nil {
+			return nil
+		}
+		return fmt.Errorf("unknown scan profile '%s', available: %s", name, strings.Join(c.profileNames(), ", "))
+	}
+	if _, ok := settings["profile"]; ok {
+		return fmt.Errorf("profile '%s': unknown setting 'profile'", name)
+	}
+	return setFlags(flags, settings, fmt.Sprintf("profile '%s'", name))
+}
+
+// applySettings sets the flags of the top-level settings that are not set on the command line or by the profile,
+// restricted to the given names if there are any. The settings can select a profile with profile: NAME.
+func (c *fileConfig) applySettings(flags *flag.FlagSet, names ...string) error {
+	if c == nil {
+		return nil
+	}
+	settings := c.Settings
+	if len(names) > 0 {
+		settings = make(map[string]any)
+		for _, name := range names {
+			if value, ok := c.Settings[name]; ok {
+				settings[name] = value
+			}
+		}
+	}
+	return setFlags(flags, settings, "configuration file")
+}
+
+// setFlags sets the flags of settings that are not set yet, lists set repeatable flags once per element
+func setFlags(flags *flag.FlagSet, settings map[string]any, source string) error {
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "config" || flags.Lookup(key) == nil {
+			return fmt.Errorf("%s: unknown setting '%s'", source, key)
+		}
+		if set[key] {
+			continue
+		}
+		values, isList := settings[key].([]any)
+		if !isList {
+			values = []any{settings[key]}
+		}
+		for _, value := range values {
+			if err := flags.Set(key, fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("%s: invalid %s: %v", source, key, err)
+			}
+		}
+	}
+	return nil
+}
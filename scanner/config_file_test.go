@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jfind.yaml")
+	content := `profiles:
+  deep:
+    path: /srv
+    eval: true
+    depth: 12
+    threads: auto
+    exclude: [node_modules, /proc]
+  quick:
+    post: true
+  broken:
+    colour: blue
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newFlags := func(args ...string) (*flag.FlagSet, *config) {
+		var c config
+		flags := flag.NewFlagSet("jfind", flag.ContinueOnError)
+		flags.StringVar(&c.startPath, "path", "", "")
+		flags.BoolVar(&c.evaluate, "eval", false, "")
+		flags.IntVar(&c.maxDepth, "depth", -1, "")
+		flags.StringVar(&c.threads, "threads", "", "")
+		flags.BoolVar(&c.doPost, "post", false, "")
+		flags.Var(&c.excludes, "exclude", "")
+		if err := flags.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return flags, &c
+	}
+
+	flags, c := newFlags("-depth", "3")
+	if err := profiles.applyProfile(flags, "deep"); err != nil {
+		t.Fatal(err)
+	}
+	if c.startPath != "/srv" || !c.evaluate || c.threads != "auto" || strings.Join(c.excludes, ",") != "node_modules,/proc" {
+		t.Errorf("profile not applied: %+v", c)
+	}
+	if c.maxDepth != 3 {
+		t.Errorf("expected the command line depth to take precedence, got %d", c.maxDepth)
+	}
+
+	// profiles named like a built-in profile extend it
+	flags, c = newFlags()
+	if err := profiles.applyProfile(flags, "quick"); err != nil || !c.doPost {
+		t.Errorf("expected the quick profile to set post: %v", err)
+	}
+
+	flags, _ = newFlags()
+	if err := profiles.applyProfile(flags, "broken"); err == nil || !strings.Contains(err.Error(), "unknown setting 'colour'") {
+		t.Errorf("expected an unknown setting error, got %v", err)
+	}
+	if err := profiles.applyProfile(flags, "standard"); err == nil || !strings.Contains(err.Error(), "available: broken, deep, quick") {
+		t.Errorf("expected an unknown profile error, got %v", err)
+	}
+
+	// without a configuration file only the built-in profiles exist
+	var none *fileConfig
+	if err := none.applyProfile(flags, "quick"); err != nil {
+		t.Errorf("expected the built-in profile, got %v", err)
+	}
+	if err := none.applyProfile(flags, "deep"); err == nil {
+		t.Errorf("expected an unknown profile error")
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	if _, err := loadConfigFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Errorf("expected an error for a missing explicit configuration file")
+	}
+	path := filepath.Join(t.TempDir(), "jfind.yaml")
+	if err := os.WriteFile(path, []byte("profiles: [deep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfigFile(path); err == nil {
+		t.Errorf("expected a parse error")
+	}
+}
+
+func TestApplySettings(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "jfind.yaml")
+	content := `profile: deep
+eval: true
+depth: 4
+exclude: [node_modules]
+url: https://inventory.example.com/api/jfind
+profiles:
+  deep:
+    depth: 12
+`
+	if err := os.WriteFile(yamlPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tomlPath := filepath.Join(dir, "jfind.toml")
+	content = `profile = "deep"
+eval = true
+depth = 4
+exclude = ["node_modules"]
+url = "https://inventory.example.com/api/jfind"
+
+[profiles.deep]
+depth = 12
+`
+	if err := os.WriteFile(tomlPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{yamlPath, tomlPath} {
+		configFile, err := loadConfigFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var c config
+		flags := flag.NewFlagSet("jfind", flag.ContinueOnError)
+		flags.StringVar(&c.profile, "profile", "", "")
+		flags.BoolVar(&c.evaluate, "eval", false, "")
+		flags.IntVar(&c.maxDepth, "depth", -1, "")
+		flags.StringVar(&c.postURL, "url", "", "")
+		flags.Var(&c.excludes, "exclude", "")
+		if err := flags.Parse([]string{"-exclude", "/proc"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// the profile selected by the file takes precedence over the settings, the command line over both
+		if err := configFile.applySettings(flags, "profile"); err != nil || c.profile != "deep" {
+			t.Fatalf("%s: expected the deep profile, got %q: %v", path, c.profile, err)
+		}
+		if err := configFile.applyProfile(flags, c.profile); err != nil {
+			t.Fatal(err)
+		}
+		if err := configFile.applySettings(flags); err != nil {
+			t.Fatal(err)
+		}
+		if !c.evaluate || c.maxDepth != 12 || c.postURL != "https://inventory.example.com/api/jfind" {
+			t.Errorf("%s: settings not applied: %+v", path, c)
+		}
+		if strings.Join(c.excludes, ",") != "/proc" {
+			t.Errorf("%s: expected the command line excludes, got %v", path, c.excludes)
+		}
+	}
+
+	broken := &fileConfig{Settings: map[string]any{"colour": "blue"}}
+	if err := broken.applySettings(flag.NewFlagSet("jfind", flag.ContinueOnError)); err == nil || !strings.Contains(err.Error(), "configuration file: unknown setting 'colour'") {
+		t.Errorf("expected an unknown setting error, got %v", err)
+	}
+}
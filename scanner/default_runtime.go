@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// appPathsKeys are the registry keys the Windows shell consults for 'java' when it is not found on PATH
+var appPathsKeys = []string{
+	`HKCU\SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths\java.exe`,
+	`HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths\java.exe`,
+}
+
+// javaCommandNames returns the file names the command lookup tries for 'java'
+func javaCommandNames() []string {
+	if runtime.GOOS != "windows" {
+		return []string{"java"}
+	}
+
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".COM;.EXE;.BAT;.CMD"
+	}
+	var names []string
+	for _, ext := range strings.Split(pathext, ";") {
+		if ext != "" {
+			names = append(names, "java"+strings.ToLower(ext))
+		}
+	}
+	return names
+}
+
+// resolveDefaultJava resolves the java executable started when typing 'java', following the PATH order
+// like 'which java', and returns it with the PATH entry selecting it. Oracle's javapath directory on Windows
+// is a regular PATH entry containing links, it is resolved by the caller.
+func resolveDefaultJava() (string, string) {
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		for _, name := range javaCommandNames() {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() && isExecutable(info) {
+				return candidate, dir
+			}
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		for _, key := range appPathsKeys {
+			if path := queryRegistryDefault(key); path != "" {
+				return path, key
+			}
+		}
+	}
+	return "", ""
+}
+
+// queryRegistryDefault returns the default value of a registry key using reg.exe
+func queryRegistryDefault(key string) string {
+	out, err := exec.Command("reg", "query", key, "/ve").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		for _, valueType := range []string{"REG_SZ", "REG_EXPAND_SZ"} {
+			if _, value, ok := strings.Cut(line, valueType); ok {
+				return expandRegistryValue(strings.Trim(strings.TrimSpace(value), `"`))
+			}
+		}
+	}
+	return ""
+}
+
+// canonicalPath resolves symbolic links, so links like /usr/bin/java or javapath\java.exe match their target
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if runtime.GOOS == "windows" {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
+// markDefaultRuntime flags the discovered runtime which runs when the scanning user types 'java'.
+// At most one result is flagged, links to the same executable only flag the first one found.
+func markDefaultRuntime(results []*JavaResult) {
+	defaultJava, entry := resolveDefaultJava()
+	if defaultJava == "" {
+		return
+	}
+
+	target := canonicalPath(defaultJava)
+	for _, result := range results {
+		if canonicalPath(result.Path) == target {
+			result.DefaultPathEntry = entry
+			return
+		}
+	}
+}
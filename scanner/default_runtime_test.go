@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeFakeJava(t *testing.T, dir string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "java")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMarkDefaultRuntime(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables and symlinks")
+	}
+
+	root := t.TempDir()
+	jdk17 := writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+	jdk21 := writeFakeJava(t, filepath.Join(root, "jdk-21", "bin"))
+
+	// /usr/bin/java style link to the JDK 21 executable, listed first on PATH
+	linkDir := filepath.Join(root, "usr", "bin")
+	if err := os.MkdirAll(linkDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(jdk21, filepath.Join(linkDir, "java")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", linkDir+string(os.PathListSeparator)+filepath.Dir(jdk17))
+
+	results := []*JavaResult{{Path: jdk17}, {Path: jdk21}}
+	markDefaultRuntime(results)
+
+	if results[0].DefaultPathEntry != "" {
+		t.Errorf("jdk-17 must not be the default, got entry %s", results[0].DefaultPathEntry)
+	}
+	if results[1].DefaultPathEntry != linkDir {
+		t.Errorf("expected jdk-21 selected by %s, got %q", linkDir, results[1].DefaultPathEntry)
+	}
+
+	runtimeJSON := createRuntimeJSON(results[1], false)
+	if !runtimeJSON.DefaultForUser || runtimeJSON.DefaultPathEntry != linkDir {
+		t.Errorf("unexpected JSON default fields: %v %q", runtimeJSON.DefaultForUser, runtimeJSON.DefaultPathEntry)
+	}
+}
+
+func TestMarkDefaultRuntimeNotOnPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	jdk := writeFakeJava(t, filepath.Join(t.TempDir(), "bin"))
+	t.Setenv("PATH", t.TempDir())
+
+	results := []*JavaResult{{Path: jdk}}
+	markDefaultRuntime(results)
+	if results[0].DefaultPathEntry != "" {
+		t.Errorf("expected no default runtime, got %q", results[0].DefaultPathEntry)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// DeploymentSettings are the security relevant settings of a deployment.properties file
+type DeploymentSettings struct {
+	// SecurityLevel is deployment.security.level, HIGH if not set
+	SecurityLevel string `json:"security_level,omitempty"`
+	// ExceptionSitesFile is the exception site list, ExceptionSites its entries
+	ExceptionSitesFile string   `json:"exception_sites_file,omitempty"`
+	ExceptionSites     []string `json:"exception_sites,omitempty"`
+	// Risks describe the settings weakening the applet and Web Start security checks
+	Risks []string `json:"risks,omitempty"`
+}
+
+// deploymentRiskSettings are settings which weaken the checks of applets and Web Start applications when set to the
+// value, with the description of the risk
+var deploymentRiskSettings = []struct {
+	key   string
+	value string
+	risk  string
+}{
+	{"deployment.security.mixcode", "HIDE_RUN", "mixed signed and unsigned code runs without warning"},
+	{"deployment.security.mixcode", "DISABLE", "mixed code verification disabled"},
+	{"deployment.security.revocation.check", "NO_CHECK", "certificate revocation checks disabled"},
+	{"deployment.security.validation.ocsp", "false", "OCSP certificate validation disabled"},
+	{"deployment.security.validation.crl", "false", "CRL certificate validation disabled"},
+	{"deployment.expiration.check.enabled", "false", "out-of-date runtime warning disabled"},
+	{"deployment.insecure.jres", "ALWAYS", "insecure runtimes run without prompt"},
+	{"deployment.security.SSLv3", "true", "SSLv3 enabled"},
+	{"deployment.security.SSLv2Hello", "true", "SSLv2Hello enabled"},
+}
+
+// inspectDeploymentSettings reads the security settings of a deployment.properties file and its exception site
+// list, user lists are below security/ next to the file unless configured otherwise
+func inspectDeploymentSettings(path string) *DeploymentSettings {
+	props, err := readPropertiesFile(path)
+	if err != nil {
+		return nil
+	}
+	settings := &DeploymentSettings{SecurityLevel: strings.ToUpper(props["deployment.security.level"])}
+
+	if level := settings.SecurityLevel; level == "MEDIUM" || level == "LOW" {
+		settings.Risks = append(settings.Risks, fmt.Sprintf("security level %s runs unsigned applets", level))
+	}
+	for _, setting := range deploymentRiskSettings {
+		if strings.EqualFold(props[setting.key], setting.value) {
+			settings.Risks = append(settings.Risks, setting.risk)
+		}
+	}
+
+	sites := configFilePath(props["deployment.user.security.exception.sites"])
+	if sites == "" {
+		sites = configFilePath(props["deployment.system.security.exception.sites"])
+	}
+	if sites == "" {
+		sites = filepath.Join(filepath.Dir(path), "security", "exception.sites")
+	}
+	if entries, err := readExceptionSites(sites); err == nil {
+		settings.ExceptionSitesFile = sites
+		settings.ExceptionSites = entries
+		if len(entries) > 0 {
+			settings.Risks = append(settings.Risks, fmt.Sprintf("%d exception sites bypass the security level", len(entries)))
+		}
+		for _, site := range entries {
+			if strings.HasPrefix(strings.ToLower(site), "http://") || strings.HasPrefix(strings.ToLower(site), "file:") {
+				settings.Risks = append(settings.Risks, "exception site without TLS: "+site)
+			}
+		}
+	}
+	return settings
+}
+
+// readExceptionSites returns the entries of an exception site list, one URL per line
+func readExceptionSites(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var sites []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			sites = append(sites, line)
+		}
+	}
+	return sites, scanner.Err()
+}
+
+// configFilePath returns the local path of a file URL in a deployment configuration, e.g.
+// file\:/C\:/Windows/Sun/Java/Deployment/deployment.properties, or "" for other URLs
+func configFilePath(value string) string {
+	value = strings.ReplaceAll(strings.TrimSpace(value), `\:`, ":")
+	if value == "" {
+		return ""
+	}
+	if !strings.HasPrefix(strings.ToLower(value), "file:") {
+		if filepath.IsAbs(value) {
+			return value
+		}
+		return ""
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	path := u.Path
+	if path == "" {
+		path = u.Opaque
+	}
+	if runtime.GOOS == "windows" {
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path)
+}
+
+// inspectDeploymentConfigs attaches the settings to the deployment.properties components. The system
+// deployment.properties referenced by a deployment.config is added to the components, for the deployment.config of
+// a runtime with its java_home.
+func inspectDeploymentConfigs(components []LegacyComponent) []LegacyComponent {
+	seen := make(map[string]bool)
+	for _, component := range components {
+		seen[component.Path] = true
+	}
+	for i := 0; i < len(components); i++ {
+		component := &components[i]
+		if component.Kind != legacyDeployConfig {
+			continue
+		}
+		if filepath.Base(component.Path) != "deployment.config" {
+			component.Settings = inspectDeploymentSettings(component.Path)
+			continue
+		}
+		props, err := readPropertiesFile(component.Path)
+		if err != nil {
+			continue
+		}
+		system := configFilePath(props["deployment.system.config"])
+		if system == "" || seen[system] {
+			continue
+		}
+		if _, err := os.Stat(system); err == nil {
+			seen[system] = true
+			components = append(components, LegacyComponent{Path: system, Kind: legacyDeployConfig, JavaHome: component.JavaHome})
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i].Path < components[j].Path })
+	return components
+}
+
+// attachDeploymentRisks attaches the risks of the deployment settings to the runtimes with Web Start or the browser
+// plugin. User and system wide settings apply to all of them, the settings referenced by a runtime to the runtime.
+func attachDeploymentRisks(components []LegacyComponent, results []*JavaResult) {
+	capable := make(map[string]bool)
+	for _, component := range components {
+		if component.Kind == legacyWebStart || component.Kind == legacyPlugin {
+			capable[component.JavaHome] = true
+		}
+	}
+
+	for _, result := range results {
+		if result.JavaHome == "" || !capable[result.JavaHome] {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, component := range components {
+			if component.Settings == nil || (component.JavaHome != "" && component.JavaHome != result.JavaHome) {
+				continue
+			}
+			for _, risk := range component.Settings.Risks {
+				if !seen[risk] {
+					seen[risk] = true
+					result.DeploymentRisks = append(result.DeploymentRisks, risk)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestInspectDeploymentSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deployment.properties")
+	writeTestFile(t, path, "deployment.security.level=MEDIUM\n"+
+		"deployment.security.revocation.check=NO_CHECK\n"+
+		"deployment.expiration.check.enabled=false\n"+
+		"deployment.webjava.enabled=true\n")
+	writeTestFile(t, filepath.Join(dir, "security", "exception.sites"),
+		"# intranet applets\nhttps://erp.example.com\nhttp://legacy.example.com:8080/\n")
+
+	settings := inspectDeploymentSettings(path)
+	if settings == nil {
+		t.Fatal("expected settings")
+	}
+	if settings.SecurityLevel != "MEDIUM" || settings.ExceptionSitesFile != filepath.Join(dir, "security", "exception.sites") {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+	wantSites := []string{"https://erp.example.com", "http://legacy.example.com:8080/"}
+	if !reflect.DeepEqual(settings.ExceptionSites, wantSites) {
+		t.Errorf("exception sites = %v, want %v", settings.ExceptionSites, wantSites)
+	}
+	wantRisks := []string{
+		"security level MEDIUM runs unsigned applets",
+		"certificate revocation checks disabled",
+		"out-of-date runtime warning disabled",
+		"2 exception sites bypass the security level",
+		"exception site without TLS: http://legacy.example.com:8080/",
+	}
+	if !reflect.DeepEqual(settings.Risks, wantRisks) {
+		t.Errorf("risks = %q, want %q", settings.Risks, wantRisks)
+	}
+
+	// defaults are not a risk
+	safe := filepath.Join(t.TempDir(), "deployment.properties")
+	writeTestFile(t, safe, "deployment.security.level=VERY_HIGH\n")
+	if settings := inspectDeploymentSettings(safe); settings == nil || len(settings.Risks) != 0 || settings.ExceptionSitesFile != "" {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+}
+
+func TestConfigFilePath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		if got := configFilePath(`file\:/C\:/Windows/Sun/Java/Deployment/deployment.properties`); got != `C:\Windows\Sun\Java\Deployment\deployment.properties` {
+			t.Errorf("unexpected path %s", got)
+		}
+		return
+	}
+	tests := map[string]string{
+		`file\:/etc/.java/deployment/deployment.properties`: "/etc/.java/deployment/deployment.properties",
+		"file:///etc/java/exception.sites":                  "/etc/java/exception.sites",
+		"/etc/java/exception.sites":                         "/etc/java/exception.sites",
+		"https://config.example.com/deployment.properties":  "",
+		"": "",
+	}
+	for value, want := range tests {
+		if got := configFilePath(value); got != want {
+			t.Errorf("configFilePath(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestDeploymentRisksOfRuntimes(t *testing.T) {
+	root := t.TempDir()
+	jre := filepath.Join(root, "jre1.8.0_202")
+	writeTestFile(t, filepath.Join(jre, "bin", "javaws"), "#!/bin/sh\n")
+	system := filepath.Join(root, "etc", "deployment.properties")
+	writeTestFile(t, system, "deployment.security.mixcode=HIDE_RUN\n")
+	writeTestFile(t, filepath.Join(jre, "lib", "deployment.config"), "deployment.system.config=file\\:"+filepath.ToSlash(system)+"\n")
+	user := filepath.Join(root, "home", "deployment.properties")
+	writeTestFile(t, user, "deployment.security.level=MEDIUM\n")
+	other := filepath.Join(root, "jdk-17")
+
+	components := runtimeLegacyComponents(jre)
+	components = append(components, LegacyComponent{Path: user, Kind: legacyDeployConfig})
+	components = inspectDeploymentConfigs(components)
+	if len(components) != 4 {
+		t.Fatalf("expected the referenced system configuration, got %+v", components)
+	}
+
+	results := []*JavaResult{{JavaHome: jre}, {JavaHome: other}}
+	attachDeploymentRisks(components, results)
+	want := []string{"mixed signed and unsigned code runs without warning", "security level MEDIUM runs unsigned applets"}
+	if !reflect.DeepEqual(results[0].DeploymentRisks, want) {
+		t.Errorf("risks = %q, want %q", results[0].DeploymentRisks, want)
+	}
+	if results[1].DeploymentRisks != nil {
+		t.Errorf("unexpected risks of a runtime without Web Start: %q", results[1].DeploymentRisks)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import "strings"
+
+// Normalized distribution names
+const (
+	distTemurin       = "temurin"
+	distAdoptOpenJDK  = "adoptopenjdk"
+	distCorretto      = "corretto"
+	distZulu          = "zulu"
+	distLiberica      = "liberica"
+	distSapMachine    = "sapmachine"
+	distSemeru        = "semeru"
+	distIBM           = "ibm"
+	distOracle        = "oracle"
+	distOracleOpenJDK = "oracle-openjdk"
+	distGraalVM       = "graalvm"
+	distMicrosoft     = "microsoft"
+	distDragonwell    = "dragonwell"
+	distRedHat        = "redhat"
+	distDebian        = "debian"
+	distUbuntu        = "ubuntu"
+	distJetBrains     = "jetbrains"
+	distKona          = "kona"
+	distBiSheng       = "bisheng"
+	distApple         = "apple"
+	distOther         = "other"
+)
+
+// vendorDistributions maps lower case substrings of java.vendor (or the release file IMPLEMENTOR)
+// to distributions, the first match wins
+var vendorDistributions = []struct {
+	substring    string
+	distribution string
+}{
+	{"eclipse adoptium", distTemurin},
+	{"adoptium", distTemurin},
+	{"adoptopenjdk", distAdoptOpenJDK},
+	{"amazon", distCorretto},
+	{"azul", distZulu},
+	{"bellsoft", distLiberica},
+	{"sap se", distSapMachine},
+	{"microsoft", distMicrosoft},
+	{"alibaba", distDragonwell},
+	{"red hat", distRedHat},
+	{"debian", distDebian},
+	{"ubuntu", distUbuntu},
+	// Ubuntu packages report "Private Build"
+	{"private build", distUbuntu},
+	{"jetbrains", distJetBrains},
+	{"tencent", distKona},
+	{"huawei", distBiSheng},
+	{"graalvm", distGraalVM},
+	{"apple", distApple},
+}
+
+// VM implementations
+const (
+	vmHotSpot = "hotspot"
+	vmOpenJ9  = "openj9"
+	vmJ9      = "j9"
+	vmZing    = "zing"
+	vmOther   = "other"
+)
+
+// vmImplementation classifies java.vm.name, e.g. "OpenJDK 64-Bit Server VM" (HotSpot),
+// "Eclipse OpenJ9 VM" (IBM Semeru) or "IBM J9 VM" (IBM SDK 8 and earlier)
+func vmImplementation(vmName string) string {
+	name := strings.ToLower(vmName)
+	switch {
+	case name == "":
+		return ""
+	case strings.Contains(name, "openj9"):
+		return vmOpenJ9
+	case strings.Contains(name, "j9"):
+		return vmJ9
+	case strings.Contains(name, "zing") || strings.Contains(name, "prime"):
+		return vmZing
+	case strings.Contains(name, "hotspot") || strings.Contains(name, "server vm") || strings.Contains(name, "client vm"):
+		// HotSpot names its variants "<product> 64-Bit Server VM", GraalVM as well
+		return vmHotSpot
+	}
+	return vmOther
+}
+
+// normalizeDistribution maps the raw vendor and runtime name of a runtime to a distribution name,
+// empty if the vendor is unknown
+func normalizeDistribution(vendor, runtimeName string) string {
+	v := strings.ToLower(vendor)
+	name := strings.ToLower(runtimeName)
+	if v == "" {
+		return ""
+	}
+
+	// vendors shipping more than one distribution are told apart by the runtime name
+	switch {
+	case strings.Contains(v, "ibm") || strings.Contains(v, "international business machines"):
+		if strings.Contains(name, "semeru") {
+			return distSemeru
+		}
+		return distIBM
+	case strings.Contains(v, "oracle"):
+		switch {
+		case strings.Contains(name, "graalvm"):
+			return distGraalVM
+		case strings.Contains(name, "openjdk"):
+			return distOracleOpenJDK
+		}
+		return distOracle
+	case strings.Contains(v, "sun microsystems"):
+		return distOracle
+	}
+
+	for _, entry := range vendorDistributions {
+		if strings.Contains(v, entry.substring) {
+			return entry.distribution
+		}
+	}
+	return distOther
+}
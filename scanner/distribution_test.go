@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestNormalizeDistribution(t *testing.T) {
+	tests := []struct {
+		vendor      string
+		runtimeName string
+		want        string
+	}{
+		{"Eclipse Adoptium", "OpenJDK Runtime Environment", distTemurin},
+		{"AdoptOpenJDK", "OpenJDK Runtime Environment", distAdoptOpenJDK},
+		{"Amazon.com Inc.", "OpenJDK Runtime Environment", distCorretto},
+		{"Azul Systems, Inc.", "OpenJDK Runtime Environment", distZulu},
+		{"BellSoft", "OpenJDK Runtime Environment", distLiberica},
+		{"SAP SE", "OpenJDK Runtime Environment", distSapMachine},
+		{"IBM Corporation", "IBM Semeru Runtime Open Edition", distSemeru},
+		{"IBM Corporation", "Java(TM) SE Runtime Environment", distIBM},
+		{"Oracle Corporation", "Java(TM) SE Runtime Environment", distOracle},
+		{"Oracle Corporation", "OpenJDK Runtime Environment", distOracleOpenJDK},
+		{"Oracle Corporation", "Java(TM) SE Runtime Environment GraalVM EE", distGraalVM},
+		{"Microsoft", "OpenJDK Runtime Environment", distMicrosoft},
+		{"Alibaba", "OpenJDK Runtime Environment", distDragonwell},
+		{"Private Build", "OpenJDK Runtime Environment", distUbuntu},
+		{"Some Vendor", "OpenJDK Runtime Environment", distOther},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeDistribution(tt.vendor, tt.runtimeName); got != tt.want {
+			t.Errorf("normalizeDistribution(%q, %q) = %q, want %q", tt.vendor, tt.runtimeName, got, tt.want)
+		}
+	}
+}
+
+func TestVMImplementation(t *testing.T) {
+	tests := map[string]string{
+		"OpenJDK 64-Bit Server VM":          vmHotSpot,
+		"Java HotSpot(TM) 64-Bit Server VM": vmHotSpot,
+		"Eclipse OpenJ9 VM":                 vmOpenJ9,
+		"IBM J9 VM":                         vmJ9,
+		"Zing 64-Bit Tiered VM":             vmZing,
+		"Substrate VM":                      vmOther,
+		"":                                  "",
+	}
+	for name, want := range tests {
+		if got := vmImplementation(name); got != want {
+			t.Errorf("vmImplementation(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
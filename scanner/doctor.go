@@ -0,0 +1,248 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Doctor finding status
+const (
+	doctorOK   = "OK"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+)
+
+const (
+	// maxClockSkew is the tolerated difference between the local clock and the collector clock
+	maxClockSkew = 5 * time.Minute
+	// maxRulesAge is the age of a build after which the license rules are considered stale
+	maxRulesAge = 180 * 24 * time.Hour
+)
+
+type doctorConfig struct {
+	postURL string
+	path    string
+	help    bool
+}
+
+// doctorFinding is the result of a single environment check
+type doctorFinding struct {
+	Status  string
+	Check   string
+	Message string
+}
+
+// runDoctor implements the 'doctor' subcommand
+func runDoctor(args []string) int {
+	var config doctorConfig
+
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s doctor [options]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Check the environment for scanning and reporting and print actionable findings.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&config.postURL, "url", defaultPostURL, "URL of the collector the reports are posted to")
+	flags.StringVar(&config.path, "path", defaultScanRoot(), "Scan root to check for network mounts")
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+	_ = flags.Parse(args)
+
+	if config.help {
+		flags.Usage()
+		return 0
+	}
+
+	findings := []doctorFinding{checkPrivileges(), checkMounts(config.path)}
+	findings = append(findings, checkCollector(config.postURL)...)
+	findings = append(findings, checkRulesFreshness(time.Now()))
+
+	failed := false
+	for _, f := range findings {
+		printf("[%-4s] %s: %s\n", f.Status, f.Check, f.Message)
+		if f.Status == doctorFail {
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// defaultScanRoot returns the root of a full scan
+func defaultScanRoot() string {
+	if runtime.GOOS == "windows" {
+		return `C:\`
+	}
+	return "/"
+}
+
+// checkPrivileges checks whether jfind can read the directories of all users
+func checkPrivileges() doctorFinding {
+	finding := doctorFinding{Check: "Privileges", Status: doctorOK}
+
+	if isPrivileged() {
+		finding.Message = "running with administrative privileges"
+		return finding
+	}
+	finding.Status = doctorWarn
+	finding.Message = "running without administrative privileges, directories of other users are skipped " +
+		"(run as root or from an elevated prompt for a complete scan)"
+	return finding
+}
+
+// checkMounts reports network file systems below the scan root, a scan skips them unless allowlisted
+func checkMounts(root string) doctorFinding {
+	finding := doctorFinding{Check: "Mounts", Status: doctorOK}
+
+	mounts, err := listMounts()
+	if err != nil {
+		finding.Status = doctorWarn
+		finding.Message = fmt.Sprintf("cannot list mounts: %v", err)
+		return finding
+	}
+
+	var network []string
+	for _, m := range networkMountsBelow(mounts, root) {
+		network = append(network, fmt.Sprintf("%s (%s)", m.Path, m.FSType))
+	}
+	if len(network) == 0 {
+		finding.Message = fmt.Sprintf("no network file systems below %s", root)
+		return finding
+	}
+	finding.Message = fmt.Sprintf("network file systems below %s are skipped: %s (scan them with -allow-mount)",
+		root, strings.Join(network, ", "))
+	return finding
+}
+
+// checkCollector checks reachability, TLS trust and clock skew against the collector health endpoint
+func checkCollector(postURL string) []doctorFinding {
+	reachability := doctorFinding{Check: "Collector", Status: doctorOK}
+
+	parsed, err := url.Parse(postURL)
+	if err != nil || !parsed.IsAbs() {
+		reachability.Status = doctorFail
+		reachability.Message = fmt.Sprintf("invalid collector URL %s", postURL)
+		return []doctorFinding{reachability}
+	}
+
+	healthURL := (&url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: "/health"}).String()
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(healthURL)
+	if err != nil {
+		if finding, ok := tlsFinding(err); ok {
+			return []doctorFinding{finding}
+		}
+		reachability.Status = doctorFail
+		reachability.Message = fmt.Sprintf("%s is not reachable: %v (check the URL, proxy and firewall rules)", healthURL, err)
+		return []doctorFinding{reachability}
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		reachability.Status = doctorWarn
+		reachability.Message = fmt.Sprintf("%s returned %s", healthURL, resp.Status)
+	} else {
+		reachability.Message = fmt.Sprintf("%s is reachable", healthURL)
+	}
+
+	findings := []doctorFinding{reachability}
+	if parsed.Scheme == "https" {
+		findings = append(findings, doctorFinding{Check: "TLS", Status: doctorOK, Message: "collector certificate is trusted"})
+	} else {
+		findings = append(findings, doctorFinding{Check: "TLS", Status: doctorWarn,
+			Message: "collector uses plain HTTP, reports are sent unencrypted (use https or -encrypt-to)"})
+	}
+
+	if serverTime, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		findings = append(findings, clockFinding(time.Now(), serverTime))
+	}
+	return findings
+}
+
+// tlsFinding describes certificate errors, which are solved differently than connection errors
+func tlsFinding(err error) (doctorFinding, bool) {
+	finding := doctorFinding{Check: "TLS", Status: doctorFail}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	var record tls.RecordHeaderError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		finding.Message = "collector certificate is not trusted by the system trust store (install the issuing CA)"
+	case errors.As(err, &invalid):
+		finding.Message = fmt.Sprintf("collector certificate is invalid: %v (check the certificate and the local clock)", invalid)
+	case errors.As(err, &hostname):
+		finding.Message = fmt.Sprintf("collector certificate does not match the host name: %v", hostname)
+	case errors.As(err, &record):
+		finding.Message = "collector does not speak TLS (use an http:// URL or fix the port)"
+	default:
+		return finding, false
+	}
+	return finding, true
+}
+
+// clockFinding compares the local clock with the collector clock, scan timestamps and
+// the date based license rules depend on it
+func clockFinding(local, server time.Time) doctorFinding {
+	skew := local.Sub(server)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		return doctorFinding{Check: "Clock", Status: doctorWarn,
+			Message: fmt.Sprintf("local clock differs from the collector by %s (enable time synchronization)", skew.Round(time.Second))}
+	}
+	return doctorFinding{Check: "Clock", Status: doctorOK, Message: "local clock is in sync with the collector"}
+}
+
+// checkRulesFreshness checks the age of the built-in license rules by the build time of jfind
+func checkRulesFreshness(now time.Time) doctorFinding {
+	finding := doctorFinding{Check: "Rules", Status: doctorOK}
+
+	buildTime, ok := vcsBuildTime()
+	if !ok {
+		finding.Message = "build time unknown (development build), license rules cannot be dated"
+		return finding
+	}
+	return rulesAgeFinding(buildTime, now)
+}
+
+func rulesAgeFinding(buildTime, now time.Time) doctorFinding {
+	age := now.Sub(buildTime)
+	if age > maxRulesAge {
+		return doctorFinding{Check: "Rules", Status: doctorWarn,
+			Message: fmt.Sprintf("license rules are from %s, %d days old (update jfind to cover recent Java releases)",
+				buildTime.Format(time.DateOnly), int(age.Hours()/24))}
+	}
+	return doctorFinding{Check: "Rules", Status: doctorOK,
+		Message: fmt.Sprintf("license rules are from %s", buildTime.Format(time.DateOnly))}
+}
+
+// vcsBuildTime returns the commit time embedded by the Go toolchain
+func vcsBuildTime() (time.Time, bool) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.time" {
+			t, err := time.Parse(time.RFC3339, setting.Value)
+			return t, err == nil
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClockAndRulesFindings(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if f := clockFinding(now, now.Add(-time.Minute)); f.Status != doctorOK {
+		t.Errorf("expected small skew to be OK, got %s", f.Status)
+	}
+	if f := clockFinding(now, now.Add(time.Hour)); f.Status != doctorWarn {
+		t.Errorf("expected one hour skew to warn, got %s", f.Status)
+	}
+
+	if f := rulesAgeFinding(now.AddDate(0, -1, 0), now); f.Status != doctorOK {
+		t.Errorf("expected recent rules to be OK, got %s", f.Status)
+	}
+	if f := rulesAgeFinding(now.AddDate(-1, 0, 0), now); f.Status != doctorWarn {
+		t.Errorf("expected year old rules to warn, got %s", f.Status)
+	}
+}
+
+func TestCheckCollector(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	findings := checkCollector(server.URL + "/api/jfind")
+	if len(findings) != 3 || findings[0].Status != doctorOK || findings[1].Status != doctorWarn || findings[2].Status != doctorOK {
+		t.Errorf("unexpected findings for plain HTTP collector: %v", findings)
+	}
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	findings = checkCollector(tlsServer.URL + "/api/jfind")
+	if len(findings) != 1 || findings[0].Check != "TLS" || findings[0].Status != doctorFail {
+		t.Errorf("expected untrusted certificate finding, got %v", findings)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+)
+
+// installationFingerprint identifies identical installation trees without hashing every file:
+// the java executable and release file hashes, the file count and the total size must all match
+type installationFingerprint struct {
+	javaSHA256    string
+	releaseSHA256 string
+	files         int
+	size          int64
+	version       string
+	vendor        string
+}
+
+// fingerprintInstallation computes the fingerprint of the installation of a result
+func fingerprintInstallation(result *JavaResult) (installationFingerprint, error) {
+	fp := installationFingerprint{}
+	if result.Properties != nil {
+		fp.version = result.Properties.Version
+		fp.vendor = result.Properties.Vendor
+	}
+
+	var err error
+	if fp.javaSHA256, err = fileSHA256(result.Path); err != nil {
+		return fp, err
+	}
+	// the release file is optional, e.g. in JDK 8 JREs
+	fp.releaseSHA256, _ = fileSHA256(filepath.Join(result.JavaHome, "release"))
+
+	err = filepath.WalkDir(result.JavaHome, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fp.files++
+		fp.size += info.Size()
+		return nil
+	})
+	return fp, err
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// markDuplicates flags runtimes whose installation tree is identical to the tree of a runtime found before.
+// Executables sharing one installation (e.g. bin/java and jre/bin/java of a JDK 8) are not duplicates.
+// It returns the disk space used by the duplicate trees.
+func markDuplicates(results []*JavaResult) int64 {
+	primaries := make(map[installationFingerprint]string)
+	homes := make(map[string]installationFingerprint)
+	var savings int64
+
+	for _, result := range results {
+		if result.JavaHome == "" {
+			continue
+		}
+
+		fp, seen := homes[result.JavaHome]
+		if !seen {
+			var err error
+			if fp, err = fingerprintInstallation(result); err != nil {
+				slog.Warn("cannot fingerprint the runtime", "java_home", result.JavaHome, "error", err)
+				continue
+			}
+			homes[result.JavaHome] = fp
+		}
+		result.InstallSize = fp.size
+
+		primary, ok := primaries[fp]
+		if !ok {
+			primaries[fp] = result.JavaHome
+			continue
+		}
+		if primary != result.JavaHome {
+			result.DuplicateOf = primary
+			if !seen {
+				savings += fp.size
+			}
+		}
+	}
+	return savings
+}
+
+// printDuplicateSummary prints the duplicate installations summary in text output mode
+func printDuplicateSummary(results []*JavaResult, savings int64) {
+	homes := make(map[string]bool)
+	for _, result := range results {
+		if result.DuplicateOf != "" {
+			homes[result.JavaHome] = true
+		}
+	}
+	if len(homes) == 0 {
+		return
+	}
+	fmt.Printf("Duplicate installations: %d, potential disk savings: %s\n", len(homes), humanize.Bytes(uint64(savings)))
+}
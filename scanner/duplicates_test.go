@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeInstallation(t *testing.T, home, release string) *JavaResult {
+	t.Helper()
+	java := writeFakeJava(t, filepath.Join(home, "bin"))
+	if err := os.WriteFile(filepath.Join(home, "release"), []byte(release), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return &JavaResult{Path: java, JavaHome: home}
+}
+
+func TestMarkDuplicates(t *testing.T) {
+	root := t.TempDir()
+	primary := writeInstallation(t, filepath.Join(root, "build-1", "jdk-17"), `JAVA_VERSION="17.0.8"`)
+	duplicate := writeInstallation(t, filepath.Join(root, "build-2", "jdk-17"), `JAVA_VERSION="17.0.8"`)
+	other := writeInstallation(t, filepath.Join(root, "jdk-21"), `JAVA_VERSION="21.0.1"`)
+	// second executable in the primary installation
+	shared := &JavaResult{Path: filepath.Join(primary.JavaHome, "jre", "bin", "java"), JavaHome: primary.JavaHome}
+
+	savings := markDuplicates([]*JavaResult{primary, shared, duplicate, other})
+
+	if primary.DuplicateOf != "" || shared.DuplicateOf != "" {
+		t.Errorf("primary installation flagged as duplicate")
+	}
+	if duplicate.DuplicateOf != primary.JavaHome {
+		t.Errorf("expected duplicate of %s, got %q", primary.JavaHome, duplicate.DuplicateOf)
+	}
+	if other.DuplicateOf != "" {
+		t.Errorf("different installation flagged as duplicate of %s", other.DuplicateOf)
+	}
+	if savings == 0 || savings != duplicate.InstallSize {
+		t.Errorf("expected savings of %d bytes, got %d", duplicate.InstallSize, savings)
+	}
+}
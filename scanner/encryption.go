@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// encryptedContentType is the content type of posted encrypted payloads
+const encryptedContentType = "application/x-age-encryption"
+
+// parseRecipients converts --encrypt-to values into age recipients.
+// A value is either an age public key (age1...) or the path of a recipients file.
+func parseRecipients(values []string) ([]age.Recipient, error) {
+	var recipients []age.Recipient
+	for _, value := range values {
+		if strings.HasPrefix(value, "age1") {
+			recipient, err := age.ParseX25519Recipient(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recipient %s: %v", value, err)
+			}
+			recipients = append(recipients, recipient)
+			continue
+		}
+
+		file, err := os.Open(value)
+		if err != nil {
+			return nil, fmt.Errorf("reading recipients file: %v", err)
+		}
+		parsed, err := age.ParseRecipients(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing recipients file %s: %v", value, err)
+		}
+		recipients = append(recipients, parsed...)
+	}
+	return recipients, nil
+}
+
+// encryptPayload encrypts data for the recipients in ASCII armored age format,
+// it can be decrypted with 'age --decrypt -i key.txt'
+func encryptPayload(data []byte, recipientValues []string) ([]byte, error) {
+	recipients, err := parseRecipients(recipientValues)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	armorWriter := armor.NewWriter(&buf)
+	w, err := age.Encrypt(armorWriter, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting payload: %v", err)
+	}
+	if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("encrypting payload: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("encrypting payload: %v", err)
+	}
+	if err := armorWriter.Close(); err != nil {
+		return nil, fmt.Errorf("encrypting payload: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+func TestEncryptPayload(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte(`{"meta":{"computer_name":"host"}}`)
+	encrypted, err := encryptPayload(payload, []string{identity.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(encrypted, []byte("computer_name")) {
+		t.Fatal("Expected payload to be encrypted")
+	}
+
+	r, err := age.Decrypt(armor.NewReader(bytes.NewReader(encrypted)), identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, payload) {
+		t.Errorf("Expected %s, got %s", payload, decrypted)
+	}
+}
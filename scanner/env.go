@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// envPrefix is the prefix of the environment variables setting flags, JFIND_EVAL_TIMEOUT sets -eval-timeout
+const envPrefix = "JFIND_"
+
+// envName returns the environment variable of a flag
+func envName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyEnv sets the flags not set on the command line from their environment variables, which take precedence over
+// the configuration file. Repeatable flags take a comma separated list, empty variables are ignored.
+func applyEnv(flags *flag.FlagSet) error {
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var names []string
+	flags.VisitAll(func(f *flag.Flag) {
+		if !set[f.Name] {
+			names = append(names, f.Name)
+		}
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		value := os.Getenv(envName(name))
+		if value == "" {
+			continue
+		}
+		values := []string{value}
+		if _, isList := flags.Lookup(name).Value.(*stringList); isList {
+			values = strings.Split(value, ",")
+		}
+		for _, value := range values {
+			if err := flags.Set(name, strings.TrimSpace(value)); err != nil {
+				return fmt.Errorf("%s: invalid value %q: %v", envName(name), value, err)
+			}
+		}
+	}
+	return nil
+}
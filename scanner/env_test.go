@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	if name := envName("eval-timeout"); name != "JFIND_EVAL_TIMEOUT" {
+		t.Errorf("expected JFIND_EVAL_TIMEOUT, got %s", name)
+	}
+
+	t.Setenv("JFIND_EVAL", "true")
+	t.Setenv("JFIND_DEPTH", "5")
+	t.Setenv("JFIND_URL", "https://inventory.example.com/api/jfind")
+	t.Setenv("JFIND_EXCLUDE", "node_modules, /proc")
+	t.Setenv("JFIND_THREADS", "")
+
+	var c config
+	flags := flag.NewFlagSet("jfind", flag.ContinueOnError)
+	flags.BoolVar(&c.evaluate, "eval", false, "")
+	flags.IntVar(&c.maxDepth, "depth", -1, "")
+	flags.StringVar(&c.postURL, "url", "", "")
+	flags.StringVar(&c.threads, "threads", "1", "")
+	flags.Var(&c.excludes, "exclude", "")
+	if err := flags.Parse([]string{"-depth", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyEnv(flags); err != nil {
+		t.Fatal(err)
+	}
+	if !c.evaluate || c.postURL != "https://inventory.example.com/api/jfind" || strings.Join(c.excludes, ",") != "node_modules,/proc" {
+		t.Errorf("environment not applied: %+v", c)
+	}
+	if c.maxDepth != 3 {
+		t.Errorf("expected the command line depth to take precedence, got %d", c.maxDepth)
+	}
+	if c.threads != "1" {
+		t.Errorf("expected an empty variable to be ignored, got %q", c.threads)
+	}
+
+	// flags set from the environment take precedence over the configuration file
+	configFile := &fileConfig{Settings: map[string]any{"eval": false, "url": "https://other.example.com"}}
+	if err := configFile.applySettings(flags); err != nil || !c.evaluate || c.postURL != "https://inventory.example.com/api/jfind" {
+		t.Errorf("expected the environment to take precedence: %+v %v", c, err)
+	}
+
+	t.Setenv("JFIND_DEPTH", "deep")
+	flags = flag.NewFlagSet("jfind", flag.ContinueOnError)
+	flags.IntVar(&c.maxDepth, "depth", -1, "")
+	if err := applyEnv(flags); err == nil || !strings.Contains(err.Error(), "JFIND_DEPTH") {
+		t.Errorf("expected an invalid value error, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type evalConfig struct {
+	paths           []string
+	pathsFrom       string
+	jsonOutput      bool
+	doPost          bool
+	postURL         string
+	requireLicense  bool
+	listModules     bool
+	inspectCacerts  bool
+	inspectSecurity bool
+	evalArgs        stringList
+	evalTimeout     time.Duration
+	workers         int
+	logs            logFlags
+	help            bool
+}
+
+// runEval implements the 'eval' subcommand, it evaluates given java executables without walking the file system
+func runEval(args []string) int {
+	config := parseEvalFlags(args)
+
+	paths := config.paths
+	if config.pathsFrom != "" {
+		listed, err := readPathList(config.pathsFrom)
+		if err != nil {
+			slog.Error("reading paths", "error", err)
+			return 1
+		}
+		paths = append(paths, listed...)
+	}
+
+	startTime := time.Now()
+	finder := NewJavaFinder("", -1, true)
+	finder.inspectCacerts = config.inspectCacerts
+	finder.inspectSecurity = config.inspectSecurity
+	finder.evalArgs = config.evalArgs
+	finder.evalTimeout = config.evalTimeout
+
+	executables := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			slog.Info("Skipping path", "path", path, "error", err)
+			continue
+		}
+		info, err := os.Stat(absPath)
+		if err != nil {
+			slog.Info("Skipping path", "path", path, "error", err)
+			continue
+		}
+		if info.IsDir() {
+			slog.Info("Skipping directory", "path", path)
+			continue
+		}
+		executables = append(executables, absPath)
+	}
+	results := evaluateAll(finder, executables, config.workers)
+	markDefaultRuntime(results)
+	if config.pathsFrom != "" {
+		slog.Info("Evaluated java executables", "evaluated", len(results), "paths", len(paths))
+	}
+
+	outputConfig := config.outputConfig()
+	if outputConfig.jsonOutput {
+		if err := handleJSONOutput(results, 0, outputConfig, startTime); err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+		return 0
+	}
+	handleRegularOutput(results, outputConfig)
+	return 0
+}
+
+// evaluateAll evaluates the executables with a pool of workers, the results are in the order of the executables
+func evaluateAll(finder *JavaFinder, executables []string, workers int) []*JavaResult {
+	results := make([]*JavaResult, len(executables))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < min(max(1, workers), len(executables)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				result := finder.evaluateJava(executables[i])
+				results[i] = &result
+			}
+		}()
+	}
+	for i := range executables {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+func parseEvalFlags(args []string) evalConfig {
+	var config evalConfig
+
+	flags := flag.NewFlagSet("eval", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s eval [options] <java_executable>...\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s eval --paths-from <file|-> [options]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Evaluate java executables without scanning. Executables given as arguments are reported with")
+		fmt.Fprintln(os.Stderr, "all their properties, lists read with --paths-from are one path per line or NUL delimited.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&config.pathsFrom, "paths-from", "", "File with java executable paths, '-' for stdin")
+	flags.BoolVar(&config.jsonOutput, "json", false, "Output results in JSON format")
+	flags.BoolVar(&config.doPost, "post", false, "Post JSON output to server (implies --json)")
+	flags.StringVar(&config.postURL, "url", defaultPostURL, "URL to post JSON output to (only used with --post)")
+	flags.BoolVar(&config.requireLicense, "require-license", false, "Filter only Java runtimes that require a commercial license")
+	flags.BoolVar(&config.listModules, "modules", false, "Include the full module list of Java 9+ runtimes in JSON output")
+	flags.BoolVar(&config.inspectCacerts, "cacerts", false, "Inspect the cacerts trust store of the runtimes")
+	flags.BoolVar(&config.inspectSecurity, "security", false, "Inspect the java.security configuration of the runtimes")
+	flags.Var(&config.evalArgs, "eval-arg", "JVM option added to the evaluation of each java, can be repeated")
+	flags.DurationVar(&config.evalTimeout, "eval-timeout", defaultEvaluationTimeout, "Time after which a hanging evaluation is killed")
+	flags.IntVar(&config.workers, "eval-workers", 1, "Number of java executables evaluated concurrently")
+	config.logs.register(flags)
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+
+	_ = flags.Parse(args)
+	config.paths = flags.Args()
+
+	if config.help || (config.pathsFrom == "" && len(config.paths) == 0) {
+		flags.Usage()
+		os.Exit(1)
+	}
+	if err := config.logs.setup(os.Stderr); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if config.doPost {
+		config.jsonOutput = true
+	}
+
+	if config.workers < 1 {
+		slog.Error("--eval-workers must be a positive number")
+		os.Exit(1)
+	}
+	if err := validateEvalTimeout(config.evalTimeout); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	for _, arg := range config.evalArgs {
+		if err := validateEvalArg(arg); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	return config
+}
+
+// outputConfig returns the configuration for the shared output handlers
+func (c evalConfig) outputConfig() config {
+	scanPath := "paths-from:" + c.pathsFrom
+	switch {
+	case c.pathsFrom == "-":
+		scanPath = "paths-from:stdin"
+	case c.pathsFrom == "" && len(c.paths) == 1:
+		scanPath = c.paths[0]
+	case c.pathsFrom == "":
+		scanPath = "paths"
+	}
+	return config{
+		startPath:       scanPath,
+		evaluate:        true,
+		jsonOutput:      c.jsonOutput,
+		doPost:          c.doPost,
+		postURL:         c.postURL,
+		filter:          runtimeFilter{requireLicense: c.requireLicense},
+		listModules:     c.listModules,
+		inspectCacerts:  c.inspectCacerts,
+		inspectSecurity: c.inspectSecurity,
+		allProperties:   len(c.paths) > 0 && c.pathsFrom == "",
+	}
+}
+
+// readPathList reads paths from a file or stdin ("-")
+func readPathList(source string) ([]string, error) {
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parsePathList(data), nil
+}
+
+// parsePathList splits NUL delimited input (find -print0) or lines, empty entries are ignored
+func parsePathList(data []byte) []string {
+	separator := []byte("\n")
+	if bytes.IndexByte(data, 0) != -1 {
+		separator = []byte{0}
+	}
+
+	var paths []string
+	for _, entry := range bytes.Split(data, separator) {
+		path := strings.TrimRight(string(entry), "\r\n")
+		if strings.TrimSpace(path) != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
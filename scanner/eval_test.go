@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestParsePathList(t *testing.T) {
+	want := []string{"/usr/lib/jvm/jdk-17/bin/java", "/opt/my java/bin/java"}
+
+	if got := parsePathList([]byte("/usr/lib/jvm/jdk-17/bin/java\r\n\n/opt/my java/bin/java\n")); !reflect.DeepEqual(got, want) {
+		t.Errorf("newline delimited: got %q", got)
+	}
+	if got := parsePathList([]byte("/usr/lib/jvm/jdk-17/bin/java\x00/opt/my java/bin/java\x00")); !reflect.DeepEqual(got, want) {
+		t.Errorf("NUL delimited: got %q", got)
+	}
+	if got := parsePathList(nil); got != nil {
+		t.Errorf("empty input: got %q", got)
+	}
+}
+
+func TestEvaluateAll(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as java")
+	}
+
+	var executables []string
+	for major := 11; major <= 21; major++ {
+		dir := filepath.Join(t.TempDir(), fmt.Sprintf("jdk-%d", major), "bin")
+		java := writeFakeJava(t, dir)
+		script := fmt.Sprintf("#!/bin/sh\necho 'java.version = %d.0.1' >&2\n", major)
+		if err := os.WriteFile(java, []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		executables = append(executables, java)
+	}
+
+	finder := NewJavaFinder("", -1, true)
+	for _, workers := range []int{1, 4, 32} {
+		results := evaluateAll(finder, executables, workers)
+		if len(results) != len(executables) {
+			t.Fatalf("%d workers: expected %d results, got %d", workers, len(executables), len(results))
+		}
+		for i, result := range results {
+			if result.Path != executables[i] || result.Properties == nil || result.Properties.Major != 11+i {
+				t.Errorf("%d workers: unexpected result %d: %+v", workers, i, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// validateExclude checks the syntax of an exclude pattern
+func validateExclude(pattern string) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid exclude pattern '%s': %v", pattern, err)
+	}
+	return nil
+}
+
+// matchesExclude reports whether a directory matches one of the exclude patterns. Patterns containing a path
+// separator match the full path, e.g. /var/lib/docker or /home/*/.cache, other patterns the directory name,
+// e.g. node_modules.
+func matchesExclude(dir string, patterns []string) bool {
+	for _, pattern := range patterns {
+		name := filepath.Base(dir)
+		if strings.ContainsRune(pattern, filepath.Separator) || strings.Contains(pattern, "/") {
+			name = dir
+			pattern = filepath.FromSlash(pattern)
+		}
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether a directory below the start path matches an exclude pattern
+func (f *JavaFinder) excluded(dir string) bool {
+	return len(f.excludes) > 0 && dir != f.startPath && matchesExclude(dir, f.excludes)
+}
+
+// inExcluded reports whether path is located in an excluded directory below the start path
+func (f *JavaFinder) inExcluded(path string) bool {
+	if len(f.excludes) == 0 {
+		return false
+	}
+	for dir := filepath.Dir(path); isBelow(dir, f.startPath) && dir != f.startPath; dir = filepath.Dir(dir) {
+		if matchesExclude(dir, f.excludes) {
+			return true
+		}
+	}
+	return false
+}
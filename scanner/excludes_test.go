@@ -0,0 +1,51 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchesExclude(t *testing.T) {
+	patterns := []string{"node_modules", "/home/*/.cache", ".m2"}
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{"/srv/app/node_modules", true},
+		{"/home/dev/.cache", true},
+		{"/home/dev/.cache/jdks", false},
+		{"/root/.m2", true},
+		{"/opt/jdk-17", false},
+	}
+	for _, tt := range tests {
+		if got := matchesExclude(filepath.FromSlash(tt.dir), patterns); got != tt.want {
+			t.Errorf("matchesExclude(%s) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+	if err := validateExclude("[a-"); err == nil {
+		t.Errorf("expected an error for an invalid pattern")
+	}
+}
+
+func TestWalkSkipsExcludes(t *testing.T) {
+	root := t.TempDir()
+	writeFakeJava(t, filepath.Join(root, "opt", "jdk-17", "bin"))
+	writeFakeJava(t, filepath.Join(root, "srv", "node_modules", "jre", "bin"))
+	writeFakeJava(t, filepath.Join(root, "backup", "jdk-11", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.excludes = []string{"node_modules", filepath.Join(root, "back*")}
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Path != filepath.Join(root, "opt", "jdk-17", "bin", "java") {
+			t.Errorf("threads %d: unexpected results %d", threads, len(results))
+		}
+		if !finder.inExcluded(filepath.Join(root, "srv", "node_modules", "jre", "bin", "java")) {
+			t.Errorf("expected the java executable to be in an excluded directory")
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Finding categories which can be mapped to exit codes
+const (
+	exitPolicyViolation   = "policy-violation"
+	exitLicenseRequired   = "license-required"
+	exitBaselineDeviation = "baseline-deviation"
+	exitScanErrors        = "scan-errors"
+	exitOracle            = "oracle"
+	exitExecFailed        = "exec-failed"
+	exitAnyFound          = "any-found"
+)
+
+// exitFailOn is the exit code of the categories given to --fail-on, the code of policy violations
+const exitFailOn = 2
+
+// defaultExitCodes are the exit codes of the finding categories without exit_codes in the configuration file,
+// categories without code do not change the exit code
+var defaultExitCodes = map[string]int{exitPolicyViolation: 2}
+
+// exitCategories are the finding categories
+var exitCategories = []string{exitPolicyViolation, exitLicenseRequired, exitBaselineDeviation, exitScanErrors,
+	exitOracle, exitExecFailed, exitAnyFound}
+
+// resolveExitCodes returns the exit codes of the finding categories, the configured codes replace the defaults.
+// The categories of failOn exit with exitFailOn unless a code is configured for them.
+func resolveExitCodes(configured map[string]int, failOn []string) (map[string]int, error) {
+	codes := make(map[string]int)
+	for category, code := range defaultExitCodes {
+		codes[category] = code
+	}
+	for category, code := range configured {
+		known := false
+		for _, candidate := range exitCategories {
+			known = known || candidate == category
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown exit code category '%s', supported: %s", category, strings.Join(exitCategories, ", "))
+		}
+		// 1 is also the exit code of failed scans, codes above 125 are reserved by shells
+		if code < 0 || code > 125 {
+			return nil, fmt.Errorf("exit code of %s must be between 0 and 125, got %d", category, code)
+		}
+		codes[category] = code
+	}
+	for _, category := range failOn {
+		if !slices.Contains(exitCategories, category) {
+			return nil, fmt.Errorf("unknown --fail-on category '%s', supported: %s", category, strings.Join(exitCategories, ", "))
+		}
+		if codes[category] == 0 {
+			codes[category] = exitFailOn
+		}
+	}
+	return codes, nil
+}
+
+// scanFindings returns the finding categories of a completed scan, the runtime categories apply to the runtimes
+// passing the filters
+func scanFindings(results []*JavaResult, config config) []string {
+	var findings []string
+	if config.policyViolating > 0 {
+		findings = append(findings, exitPolicyViolation)
+	}
+	var licenseRequired, oracle, execFailed bool
+	results = filterResults(results, config)
+	for _, result := range results {
+		runtime := createRuntimeJSON(result, config.evaluate)
+		licenseRequired = licenseRequired || (runtime.RequireLicense != nil && *runtime.RequireLicense)
+		oracle = oracle || runtime.IsOracle
+		execFailed = execFailed || runtime.ExecFailed
+	}
+	if licenseRequired {
+		findings = append(findings, exitLicenseRequired)
+	}
+	if config.baselineSummary != nil && (config.baselineSummary.Deviations > 0 || config.baselineSummary.Missing > 0) {
+		findings = append(findings, exitBaselineDeviation)
+	}
+	if len(config.errorLog.classCounts()) > 0 {
+		findings = append(findings, exitScanErrors)
+	}
+	if oracle {
+		findings = append(findings, exitOracle)
+	}
+	if execFailed {
+		findings = append(findings, exitExecFailed)
+	}
+	if len(results) > 0 {
+		findings = append(findings, exitAnyFound)
+	}
+	return findings
+}
+
+// exitCode returns the exit code of a scan with the findings, the highest code of their categories
+func exitCode(codes map[string]int, findings []string) int {
+	code := 0
+	for _, finding := range findings {
+		code = max(code, codes[finding])
+	}
+	return code
+}
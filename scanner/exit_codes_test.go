@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestResolveExitCodes(t *testing.T) {
+	codes, err := resolveExitCodes(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codes[exitPolicyViolation] != 2 || codes[exitScanErrors] != 0 {
+		t.Errorf("unexpected default exit codes %v", codes)
+	}
+
+	codes, err = resolveExitCodes(map[string]int{exitPolicyViolation: 4, exitScanErrors: 1, exitLicenseRequired: 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codes[exitPolicyViolation] != 4 || codes[exitScanErrors] != 1 || codes[exitLicenseRequired] != 3 {
+		t.Errorf("unexpected exit codes %v", codes)
+	}
+	if defaultExitCodes[exitPolicyViolation] != 2 {
+		t.Error("configured exit codes modified the defaults")
+	}
+
+	for _, configured := range []map[string]int{{"warnings": 1}, {exitScanErrors: 255}, {exitScanErrors: -1}} {
+		if _, err := resolveExitCodes(configured, nil); err == nil {
+			t.Errorf("expected an error for %v", configured)
+		}
+	}
+
+	// --fail-on categories exit with 2 unless a code is configured
+	codes, err = resolveExitCodes(map[string]int{exitLicenseRequired: 3}, []string{exitLicenseRequired, exitOracle, exitAnyFound})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codes[exitLicenseRequired] != 3 || codes[exitOracle] != 2 || codes[exitAnyFound] != 2 || codes[exitExecFailed] != 0 {
+		t.Errorf("unexpected exit codes %v", codes)
+	}
+	if _, err := resolveExitCodes(nil, []string{"java"}); err == nil {
+		t.Error("expected an error for an unknown --fail-on category")
+	}
+}
+
+func TestScanExitCode(t *testing.T) {
+	codes := map[string]int{exitPolicyViolation: 2, exitLicenseRequired: 3, exitScanErrors: 1}
+	log := newErrorLog(10)
+	log.record("/secret", errors.New("input/output error"))
+	config := config{evaluate: true, errorLog: log, policyViolating: 1, baselineSummary: &BaselineSummary{Approved: 3}}
+	oracle := &JavaResult{Path: "/opt/jdk/bin/java", Evaluated: true, Properties: &JavaProperties{
+		Version: "1.8.0_351", Major: 8, Update: 351, Vendor: "Oracle Corporation", RuntimeName: oracleJDKRuntimeName}}
+
+	findings := scanFindings([]*JavaResult{oracle}, config)
+	want := []string{exitPolicyViolation, exitLicenseRequired, exitScanErrors, exitOracle, exitAnyFound}
+	if len(findings) != len(want) {
+		t.Fatalf("unexpected findings %v", findings)
+	}
+	for i := range want {
+		if findings[i] != want[i] {
+			t.Fatalf("unexpected findings %v", findings)
+		}
+	}
+	if code := exitCode(codes, findings); code != 3 {
+		t.Errorf("expected the highest exit code 3, got %d", code)
+	}
+	if code := exitCode(codes, []string{exitBaselineDeviation}); code != 0 {
+		t.Errorf("expected 0 for an unmapped category, got %d", code)
+	}
+	if code := exitCode(codes, nil); code != 0 {
+		t.Errorf("expected 0 without findings, got %d", code)
+	}
+
+	// the runtime categories apply to the runtimes passing the filters
+	broken := &JavaResult{Path: "/opt/broken/bin/java", Error: errors.New("exec format error")}
+	findings = scanFindings([]*JavaResult{oracle, broken}, config)
+	if !slices.Contains(findings, exitExecFailed) {
+		t.Errorf("expected an exec-failed finding, got %v", findings)
+	}
+	filter, err := newRuntimeFilter(filterFlags{vendors: []string{"temurin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.policyViolating, config.errorLog, config.filter = 0, newErrorLog(10), filter
+	findings = scanFindings([]*JavaResult{oracle, broken}, config)
+	if len(findings) != 0 {
+		t.Errorf("expected no findings for filtered runtimes, got %v", findings)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File indexes which can be queried for java executables before the walk
+const (
+	indexSpotlight = "spotlight"
+	indexLocate    = "locate"
+)
+
+// maxLocateAge is the age of a locate database after which it is considered outdated, updatedb runs daily
+const maxLocateAge = 48 * time.Hour
+
+// locateDatabases are the default databases of plocate and mlocate
+var locateDatabases = []string{
+	"/var/lib/plocate/plocate.db",
+	"/var/lib/mlocate/mlocate.db",
+}
+
+// spotlightQueries find java executables and JDK bundles, bundles are indexed more reliably than their content
+var spotlightQueries = []string{
+	`kMDItemFSName == "java"`,
+	`kMDItemFSName == "*.jdk" || kMDItemCFBundleIdentifier == "*jdk*"c`,
+}
+
+// bundleJavaPaths are the java executables of a JDK bundle (JDK 8 bundles also contain a JRE)
+var bundleJavaPaths = []string{
+	filepath.Join("Contents", "Home", "bin", "java"),
+	filepath.Join("Contents", "Home", "jre", "bin", "java"),
+}
+
+// indexNames returns the supported file index names for the usage message
+func indexNames() string {
+	return indexSpotlight + ", " + indexLocate
+}
+
+// validateIndex checks that a file index is supported on this platform
+func validateIndex(name string) error {
+	switch name {
+	case indexSpotlight:
+		if runtime.GOOS != "darwin" {
+			return fmt.Errorf("the %s index is only available on macOS", name)
+		}
+		return nil
+	case indexLocate:
+		if runtime.GOOS != "linux" {
+			return fmt.Errorf("the %s index is only available on Linux", name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown file index '%s', supported: %s", name, indexNames())
+	}
+}
+
+// queryIndex returns the candidate java executables below root known to a file index
+func queryIndex(name, root string) ([]string, error) {
+	switch name {
+	case indexSpotlight:
+		return spotlightCandidates(root)
+	case indexLocate:
+		return locateCandidates(root, time.Now())
+	default:
+		return nil, fmt.Errorf("unknown file index '%s'", name)
+	}
+}
+
+// spotlightCandidates queries Spotlight with mdfind for java executables and JDK bundles below root
+func spotlightCandidates(root string) ([]string, error) {
+	var candidates []string
+	for _, query := range spotlightQueries {
+		out, err := exec.Command("mdfind", "-onlyin", root, query).Output()
+		if err != nil {
+			return nil, fmt.Errorf("querying Spotlight: %v", err)
+		}
+		for _, path := range splitLines(string(out)) {
+			if strings.HasSuffix(path, ".jdk") {
+				for _, java := range bundleJavaPaths {
+					candidates = append(candidates, filepath.Join(path, java))
+				}
+				continue
+			}
+			candidates = append(candidates, path)
+		}
+	}
+	return candidates, nil
+}
+
+// locateCandidates queries the plocate or mlocate database for files named java, the database must have been
+// updated recently, otherwise installations of the last days are missing
+func locateCandidates(root string, now time.Time) ([]string, error) {
+	updated, err := locateDatabaseTime()
+	if err != nil {
+		return nil, err
+	}
+	if age := now.Sub(updated); age > maxLocateAge {
+		return nil, fmt.Errorf("locate database is outdated (last updated %s ago), run updatedb", age.Round(time.Hour))
+	}
+
+	// '\java' matches the base name exactly instead of '*java*'
+	out, err := exec.Command("locate", "-b", `\java`).Output()
+	if err != nil {
+		// locate exits with 1 if nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("querying locate: %v", err)
+	}
+	return filterLocateOutput(string(out), root), nil
+}
+
+// filterLocateOutput keeps the bin/java paths below root of locate output
+func filterLocateOutput(out, root string) []string {
+	var candidates []string
+	for _, path := range splitLines(out) {
+		if filepath.Base(filepath.Dir(path)) == "bin" && isBelow(path, root) {
+			candidates = append(candidates, path)
+		}
+	}
+	return candidates
+}
+
+// locateDatabaseTime returns the modification time of the newest locate database
+func locateDatabaseTime() (time.Time, error) {
+	var updated time.Time
+	for _, db := range locateDatabases {
+		if info, err := os.Stat(db); err == nil && info.ModTime().After(updated) {
+			updated = info.ModTime()
+		}
+	}
+	if updated.IsZero() {
+		return updated, fmt.Errorf("no locate database found")
+	}
+	return updated, nil
+}
+
+// splitLines returns the non-empty lines of command output
+func splitLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// verifyCandidates keeps the existing java executables below the start path within the maximum depth,
+// index entries may be stale or point outside the scanned tree
+func (f *JavaFinder) verifyCandidates(candidates []string) []string {
+	seen := make(map[string]bool)
+	var verified []string
+	for _, path := range candidates {
+		path = filepath.Clean(path)
+		if seen[path] || !isBelow(path, f.startPath) || f.inSkippedMount(path) || f.inExcluded(path) {
+			continue
+		}
+		seen[path] = true
+		if f.maxDepth >= 0 && f.getPathDepth(path) > f.maxDepth {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || !isJavaFile(path, info) {
+			continue
+		}
+		verified = append(verified, path)
+	}
+	sort.Strings(verified)
+	return verified
+}
+
+// findKnown evaluates the java executables in well-known locations, those of the targeted detectors with the
+// quick profile and those known to the file index, and remembers them, so the following walk does not evaluate
+// them again. An index error is returned together with the results of the well-known locations.
+func (f *JavaFinder) findKnown() ([]*JavaResult, error) {
+	candidates := f.wellKnownCandidates()
+	if f.quick {
+		candidates = append(candidates, quickCandidates(f.homes)...)
+	}
+	var indexErr error
+	if f.index != "" {
+		var indexed []string
+		indexed, indexErr = queryIndex(f.index, f.startPath)
+		candidates = append(candidates, indexed...)
+	}
+
+	if f.indexed == nil {
+		f.indexed = make(map[string]bool)
+	}
+	var results []*JavaResult
+	for _, path := range f.verifyCandidates(candidates) {
+		f.indexed[path] = true
+		result := f.evaluateJava(path)
+		f.foundJava(path)
+		results = append(results, &result)
+	}
+	return results, indexErr
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestVerifyCandidates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	root := t.TempDir()
+	jdk := writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+	deep := writeFakeJava(t, filepath.Join(root, "a", "b", "c", "jdk", "bin"))
+	outside := writeFakeJava(t, filepath.Join(t.TempDir(), "bin"))
+
+	finder := NewJavaFinder(root, 3, false)
+	verified := finder.verifyCandidates([]string{
+		jdk,
+		jdk + "/",
+		filepath.Join(root, "removed", "bin", "java"),
+		filepath.Dir(jdk),
+		deep,
+		outside,
+	})
+	if len(verified) != 1 || verified[0] != jdk {
+		t.Errorf("unexpected verified candidates %v", verified)
+	}
+}
+
+func TestFindSkipsIndexed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	root := t.TempDir()
+	indexed := writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+	walked := writeFakeJava(t, filepath.Join(root, "jdk-21", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.indexed = map[string]bool{indexed: true}
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Path != walked {
+			t.Errorf("threads %d: expected only %s, got %d results", threads, walked, len(results))
+		}
+	}
+}
+
+func TestValidateIndex(t *testing.T) {
+	if err := validateIndex("unknown"); err == nil {
+		t.Error("expected error for unknown index")
+	}
+	if err := validateIndex(indexSpotlight); (err == nil) != (runtime.GOOS == "darwin") {
+		t.Errorf("unexpected result %v for spotlight on %s", err, runtime.GOOS)
+	}
+	if err := validateIndex(indexLocate); (err == nil) != (runtime.GOOS == "linux") {
+		t.Errorf("unexpected result %v for locate on %s", err, runtime.GOOS)
+	}
+}
+
+func TestWellKnownCandidates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX symlinks")
+	}
+
+	jvm := filepath.Join(t.TempDir(), "usr", "lib", "jvm")
+	writeFakeJava(t, filepath.Join(jvm, "java-17-openjdk-amd64", "bin"))
+	if err := os.Symlink(filepath.Join(jvm, "java-17-openjdk-amd64"), filepath.Join(jvm, "default-java")); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates := wellKnownCandidatesIn([]string{jvm, filepath.Join(jvm, "missing")}, "/")
+	expected := []string{
+		filepath.Join(jvm, "java-17-openjdk-amd64", "bin", "java"),
+		filepath.Join(jvm, "java-17-openjdk-amd64", "jre", "bin", "java"),
+	}
+	if !reflect.DeepEqual(candidates, expected) {
+		t.Errorf("unexpected candidates %v", candidates)
+	}
+	if candidates := wellKnownCandidatesIn([]string{jvm}, filepath.Join(jvm, "other")); len(candidates) != 0 {
+		t.Errorf("expected no candidates outside the start path, got %v", candidates)
+	}
+}
+
+func TestFilterLocateOutput(t *testing.T) {
+	out := "/usr/lib/jvm/java-17-openjdk-amd64/bin/java\n" +
+		"/usr/share/doc/java\n" +
+		"/home/alice/.sdkman/candidates/java\n" +
+		"/opt/jdk-21/bin/java\n"
+	candidates := filterLocateOutput(out, "/usr")
+	if len(candidates) != 1 || candidates[0] != "/usr/lib/jvm/java-17-openjdk-amd64/bin/java" {
+		t.Errorf("unexpected candidates %v", candidates)
+	}
+	if candidates := filterLocateOutput(out, "/"); len(candidates) != 2 {
+		t.Errorf("expected 2 candidates, got %v", candidates)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// runtimeFilter selects the runtimes reported by all output formats. A runtime is reported if it passes every
+// configured criterion.
+type runtimeFilter struct {
+	// requireLicense keeps only the runtimes requiring a commercial license
+	requireLicense bool
+	// vendors match the vendor or the distribution of a runtime, case insensitive; one of them has to match
+	vendors []*regexp.Regexp
+	// versions are constraints on the version of a runtime, all of them have to match
+	versions []versionConstraint
+	// execFailed keeps only the runtimes whose evaluation could not run java
+	execFailed bool
+}
+
+// filterFlags are the filter flags of a scan
+type filterFlags struct {
+	requireLicense bool
+	vendors        []string
+	minMajor       int
+	maxMajor       int
+	versionRange   string
+	execFailed     bool
+}
+
+// requireEvaluation reports whether a filter flag is set that uses the results of the evaluation
+func (f filterFlags) requireEvaluation() bool {
+	return len(f.vendors) > 0 || f.minMajor > 0 || f.maxMajor > 0 || f.versionRange != "" || f.execFailed
+}
+
+// newRuntimeFilter compiles the criteria of the filter flags
+func newRuntimeFilter(flags filterFlags) (runtimeFilter, error) {
+	filter := runtimeFilter{requireLicense: flags.requireLicense, execFailed: flags.execFailed}
+	for _, vendor := range flags.vendors {
+		pattern, err := regexp.Compile("(?i)" + vendor)
+		if err != nil {
+			return filter, fmt.Errorf("invalid vendor filter '%s': %v", vendor, err)
+		}
+		filter.vendors = append(filter.vendors, pattern)
+	}
+
+	if flags.minMajor < 0 || flags.maxMajor < 0 {
+		return filter, fmt.Errorf("--min-major and --max-major must be positive major versions")
+	}
+	if flags.minMajor > 0 {
+		filter.versions = append(filter.versions, versionConstraint{op: ">=", parts: []int{flags.minMajor}})
+	}
+	if flags.maxMajor > 0 {
+		filter.versions = append(filter.versions, versionConstraint{op: "<=", parts: []int{flags.maxMajor}})
+	}
+	constraints, err := parseVersionRange(flags.versionRange)
+	if err != nil {
+		return filter, err
+	}
+	filter.versions = append(filter.versions, constraints...)
+	return filter, nil
+}
+
+// active reports whether the filter has criteria
+func (f runtimeFilter) active() bool {
+	return f.requireLicense || len(f.vendors) > 0 || len(f.versions) > 0 || f.execFailed
+}
+
+// match reports whether a runtime passes the filter
+func (f runtimeFilter) match(runtime JavaRuntimeJSON) bool {
+	if f.requireLicense && (runtime.RequireLicense == nil || !*runtime.RequireLicense) {
+		return false
+	}
+	if f.execFailed && !runtime.ExecFailed {
+		return false
+	}
+	if len(f.vendors) > 0 && !f.matchVendor(runtime) {
+		return false
+	}
+	if len(f.versions) > 0 {
+		version := javaVersionParts(runtime.JavaVersion)
+		if version == nil {
+			return false
+		}
+		for _, constraint := range f.versions {
+			if !constraint.match(version) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchVendor reports whether the vendor or distribution of a runtime matches one of the vendor patterns
+func (f runtimeFilter) matchVendor(runtime JavaRuntimeJSON) bool {
+	for _, pattern := range f.vendors {
+		if (runtime.JavaVendor != "" && pattern.MatchString(runtime.JavaVendor)) ||
+			(runtime.Distribution != "" && pattern.MatchString(runtime.Distribution)) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterResults returns the results passing the filter of the configuration. Every output format reports the
+// filtered results, the counts of the meta section count them.
+func filterResults(results []*JavaResult, config config) []*JavaResult {
+	if !config.filter.active() {
+		return results
+	}
+	filtered := make([]*JavaResult, 0, len(results))
+	for _, result := range results {
+		if config.filter.match(createRuntimeJSON(result, config.evaluate)) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// versionConstraint compares the version of a runtime with a version, on the components of that version: =17 matches
+// every 17 release, <17.0.10 the 17 releases before 17.0.10
+type versionConstraint struct {
+	op    string
+	parts []int
+}
+
+// versionOperators are the operators of version constraints, longer operators first
+var versionOperators = []string{">=", "<=", "==", "!=", ">", "<", "="}
+
+// parseVersionRange parses constraints separated by spaces or commas, e.g. ">=8 <17". A version without operator
+// matches its releases.
+func parseVersionRange(input string) ([]versionConstraint, error) {
+	var constraints []versionConstraint
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		constraint := versionConstraint{op: "="}
+		for _, op := range versionOperators {
+			if strings.HasPrefix(field, op) {
+				constraint.op = op
+				if op == "==" {
+					constraint.op = "="
+				}
+				field = strings.TrimPrefix(field, op)
+				break
+			}
+		}
+		if constraint.parts = javaVersionParts(field); constraint.parts == nil {
+			return nil, fmt.Errorf("invalid version range '%s': '%s' is not a version", input, field)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, nil
+}
+
+// match reports whether a version satisfies the constraint
+func (c versionConstraint) match(version []int) bool {
+	cmp := 0
+	for i, part := range c.parts {
+		v := 0
+		if i < len(version) {
+			v = version[i]
+		}
+		if v != part {
+			cmp = 1
+			if v < part {
+				cmp = -1
+			}
+			break
+		}
+	}
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// javaVersionParts returns the numeric components of a Java version with the major version first, 1.8.0_392 and
+// 8u392 become 8, 0, 392 and 17.0.13+11 becomes 17, 0, 13. It returns nil if version does not start with a number.
+func javaVersionParts(version string) []int {
+	version = strings.TrimSpace(version)
+	if major, update, ok := strings.Cut(version, "u"); ok && major != "" && strings.Trim(major, "0123456789") == "" {
+		version = major + ".0." + update
+	}
+	if strings.HasPrefix(version, "1.") && len(version) > 2 {
+		version = version[2:]
+	}
+	version = strings.ReplaceAll(version, "_", ".")
+	if end := strings.IndexFunc(version, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); end >= 0 {
+		version = version[:end]
+	}
+
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		part, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterResults(t *testing.T) {
+	results := []*JavaResult{
+		{Path: "/opt/oracle/bin/java", Properties: &JavaProperties{Vendor: "Oracle Corporation", RuntimeName: "Java(TM) SE Runtime Environment", Version: "17.0.13", Major: 17, Update: 13}},
+		{Path: "/opt/temurin/bin/java", Properties: &JavaProperties{Vendor: "Eclipse Adoptium", RuntimeName: "OpenJDK Runtime Environment", Version: "21.0.5", Major: 21, Update: 5}},
+		{Path: "/opt/broken/bin/java"},
+	}
+	paths := func(results []*JavaResult) string {
+		var paths []string
+		for _, result := range results {
+			paths = append(paths, result.Path)
+		}
+		return strings.Join(paths, ",")
+	}
+
+	for vendors, want := range map[string]string{
+		"":               "/opt/oracle/bin/java,/opt/temurin/bin/java,/opt/broken/bin/java",
+		"ORACLE":         "/opt/oracle/bin/java",
+		"temurin":        "/opt/temurin/bin/java",
+		"oracle,temurin": "/opt/oracle/bin/java,/opt/temurin/bin/java",
+		"^eclipse":       "/opt/temurin/bin/java",
+		"zulu":           "",
+	} {
+		var list []string
+		if vendors != "" {
+			list = strings.Split(vendors, ",")
+		}
+		filter, err := newRuntimeFilter(filterFlags{vendors: list})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := paths(filterResults(results, config{evaluate: true, filter: filter})); got != want {
+			t.Errorf("vendors %q: got %s, want %s", vendors, got, want)
+		}
+	}
+
+	if _, err := newRuntimeFilter(filterFlags{vendors: []string{"oracle("}}); err == nil {
+		t.Errorf("expected an invalid pattern to be rejected")
+	}
+}
+
+func TestRequireLicenseFilter(t *testing.T) {
+	results := []*JavaResult{
+		{Path: "/opt/oracle/bin/java", Properties: &JavaProperties{Vendor: "Oracle Corporation", RuntimeName: "Java(TM) SE Runtime Environment", Version: "17.0.13", Major: 17, Update: 13, VersionDate: "2024-10-15"}},
+		{Path: "/opt/temurin/bin/java", Properties: &JavaProperties{Vendor: "Eclipse Adoptium", RuntimeName: "OpenJDK Runtime Environment", Version: "21.0.5", Major: 21, Update: 5}},
+		{Path: "/opt/broken/bin/java", Error: fmt.Errorf("exec format error")},
+	}
+	filter, err := newRuntimeFilter(filterFlags{requireLicense: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := config{evaluate: true, filter: filter}
+	filtered := filterResults(results, c)
+	if len(filtered) != 1 || filtered[0].Path != "/opt/oracle/bin/java" {
+		t.Fatalf("expected the Oracle runtime, got %v", filtered)
+	}
+
+	// the meta section counts the reported runtimes
+	if meta := createMetaInfo("/opt", filtered, 0, time.Now()); meta.CountResult != 1 {
+		t.Errorf("expected count_result 1, got %d", meta.CountResult)
+	}
+}
+
+func TestExecFailedFilter(t *testing.T) {
+	results := []*JavaResult{
+		{Path: "/opt/oracle/bin/java", Properties: &JavaProperties{Vendor: "Oracle Corporation", Version: "17.0.13", Major: 17, Update: 13}},
+		{Path: "/opt/broken/bin/java", Error: fmt.Errorf("exec format error")},
+		{Path: "/opt/crashing/bin/java", ReturnCode: 1},
+	}
+	filter, err := newRuntimeFilter(filterFlags{execFailed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered := filterResults(results, config{evaluate: true, filter: filter})
+	if len(filtered) != 2 || filtered[0].Path != "/opt/broken/bin/java" || filtered[1].Path != "/opt/crashing/bin/java" {
+		t.Errorf("expected the failed runtimes, got %v", filtered)
+	}
+}
+
+func TestJavaVersionParts(t *testing.T) {
+	for version, want := range map[string]string{
+		"1.8.0_392":   "[8 0 392]",
+		"8u392":       "[8 0 392]",
+		"17.0.13":     "[17 0 13]",
+		"17.0.13+11":  "[17 0 13]",
+		"21-ea":       "[21]",
+		"11.0.25.0.1": "[11 0 25 0 1]",
+		"":            "[]",
+		"unknown":     "[]",
+	} {
+		if got := fmt.Sprint(javaVersionParts(version)); got != want {
+			t.Errorf("%q: got %s, want %s", version, got, want)
+		}
+	}
+}
+
+func TestVersionFilter(t *testing.T) {
+	runtimes := []JavaRuntimeJSON{
+		{JavaExecutable: "8", JavaVersion: "1.8.0_392"},
+		{JavaExecutable: "11", JavaVersion: "11.0.25"},
+		{JavaExecutable: "17.0.9", JavaVersion: "17.0.9"},
+		{JavaExecutable: "17.0.13", JavaVersion: "17.0.13"},
+		{JavaExecutable: "21", JavaVersion: "21.0.5"},
+		{JavaExecutable: "unknown"},
+	}
+	for _, test := range []struct {
+		flags filterFlags
+		want  string
+	}{
+		{filterFlags{minMajor: 11}, "11,17.0.9,17.0.13,21"},
+		{filterFlags{maxMajor: 11}, "8,11"},
+		{filterFlags{minMajor: 11, maxMajor: 17}, "11,17.0.9,17.0.13"},
+		{filterFlags{versionRange: ">=8 <17"}, "8,11"},
+		{filterFlags{versionRange: "17"}, "17.0.9,17.0.13"},
+		{filterFlags{versionRange: "==17.0.13"}, "17.0.13"},
+		{filterFlags{versionRange: "17, <17.0.10"}, "17.0.9"},
+		{filterFlags{versionRange: "!=17"}, "8,11,21"},
+		{filterFlags{versionRange: ">1.8.0_392 <=11"}, "11"},
+		{filterFlags{versionRange: "<8u400"}, "8"},
+	} {
+		filter, err := newRuntimeFilter(test.flags)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var matched []string
+		for _, runtime := range runtimes {
+			if filter.match(runtime) {
+				matched = append(matched, runtime.JavaExecutable)
+			}
+		}
+		if got := strings.Join(matched, ","); got != test.want {
+			t.Errorf("%+v: got %s, want %s", test.flags, got, test.want)
+		}
+	}
+
+	for _, flags := range []filterFlags{{versionRange: ">=x"}, {versionRange: "<"}, {minMajor: -1}} {
+		if _, err := newRuntimeFilter(flags); err == nil {
+			t.Errorf("%+v: expected an error", flags)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// outputFormat renders a report in a structured output format
+type outputFormat struct {
+	contentType string
+	// hostIdentifiers requests the collection of the host identifiers into the meta section
+	hostIdentifiers bool
+	// binary formats are written to standard output as they are, without a trailing newline
+	binary bool
+	render func(output *JSONOutput, options renderOptions) ([]byte, error)
+}
+
+// renderOptions are the options of the output formats set by flags
+type renderOptions struct {
+	// wide adds the distribution, edition, release date, architecture and license details to the columns of -format
+	// table
+	wide bool
+	// template is the parsed --template of -format template, executed for every runtime
+	template *template.Template
+}
+
+// outputFormats are the structured output formats selectable with -format
+var outputFormats = map[string]outputFormat{
+	"json":              {contentType: "application/json", render: renderJSON},
+	"ndjson":            {contentType: "application/x-ndjson", render: renderNDJSON},
+	"html":              {contentType: "text/html", render: renderHTML},
+	"servicenow":        {contentType: "text/csv", hostIdentifiers: true, render: renderServiceNowCSV},
+	"flexera":           {contentType: "text/csv", hostIdentifiers: true, render: renderFlexeraCSV},
+	"ansible-facts":     {contentType: "application/json", render: renderAnsibleFacts},
+	"ansible-inventory": {contentType: "application/json", render: renderAnsibleInventory},
+	"parquet":           {contentType: "application/vnd.apache.parquet", binary: true, render: renderParquet},
+	"sarif":             {contentType: "application/sarif+json", render: renderSARIF},
+	"table":             {contentType: "text/plain", render: renderTable},
+	"template":          {contentType: "text/plain", render: renderTemplate},
+}
+
+// formatNames returns the names of the output formats for usage messages
+func formatNames() string {
+	names := []string{"text"}
+	for name := range outputFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return strings.Join(names, ", ")
+}
+
+func renderJSON(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	return json.MarshalIndent(output, "", "  ")
+}
+
+// serviceNowColumns are the columns of the ServiceNow import set CSV. Each row is a software installation,
+// the host columns correlate it with the computer configuration item in a transform map.
+var serviceNowColumns = []string{
+	"computer_name", "fqdn", "serial_number", "ip_address", "mac_address",
+	"display_name", "version", "publisher", "install_location", "executable",
+	"distribution", "require_license", "license_reason", "scan_ts",
+}
+
+// renderServiceNowCSV renders the runtimes as software installations for a ServiceNow import set
+func renderServiceNowCSV(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(serviceNowColumns); err != nil {
+		return nil, err
+	}
+
+	host := output.Meta.Host
+	if host == nil {
+		host = &HostIdentifiers{}
+	}
+	for _, runtime := range output.Runtimes {
+		requireLicense := ""
+		if runtime.RequireLicense != nil {
+			requireLicense = strconv.FormatBool(*runtime.RequireLicense)
+		}
+		row := []string{
+			output.Meta.ComputerName, host.FQDN, host.SerialNumber, host.IPAddress, host.MACAddress,
+			softwareDisplayName(runtime), runtime.JavaVersion, runtime.JavaVendor, runtime.JavaHome, runtime.JavaExecutable,
+			runtime.Distribution, requireLicense, runtime.LicenseReason, output.Meta.ScanTimestamp,
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("writing CSV: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// softwareDisplayName returns the product name of a runtime as shown in software inventories
+func softwareDisplayName(runtime JavaRuntimeJSON) string {
+	name := runtime.JavaRuntime
+	if name == "" {
+		name = "Java Runtime"
+	}
+	if runtime.JavaVersion != "" {
+		name += " " + runtime.JavaVersion
+	}
+	return name
+}
+
+// flexeraColumns are the columns of the Flexera One evidence CSV, one file evidence per installation
+var flexeraColumns = []string{
+	"ComputerName", "FQDN", "SerialNumber", "Publisher", "Product", "Edition", "Version",
+	"InstallPath", "FilePath", "EvidenceSource", "EvidenceDate", "LicenseRequired",
+}
+
+// renderFlexeraCSV renders the runtimes as normalized installation evidence for Flexera One
+func renderFlexeraCSV(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(flexeraColumns); err != nil {
+		return nil, err
+	}
+
+	host := output.Meta.Host
+	if host == nil {
+		host = &HostIdentifiers{}
+	}
+	for _, runtime := range output.Runtimes {
+		licenseRequired := ""
+		if runtime.RequireLicense != nil {
+			licenseRequired = strconv.FormatBool(*runtime.RequireLicense)
+		}
+		row := []string{
+			output.Meta.ComputerName, host.FQDN, host.SerialNumber, runtime.JavaVendor, javaProduct(runtime),
+			runtime.Edition, runtime.JavaVersion, runtime.JavaHome, runtime.JavaExecutable, evidenceSource(runtime),
+			output.Meta.ScanTimestamp, licenseRequired,
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("writing CSV: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// javaProduct returns the product name used by software recognition libraries
+func javaProduct(runtime JavaRuntimeJSON) string {
+	switch {
+	case runtime.Distribution == distOracle:
+		return "Java SE"
+	case runtime.Distribution == distGraalVM && runtime.IsOracle:
+		return "GraalVM"
+	case runtime.JavaVendor != "":
+		return "OpenJDK"
+	}
+	return "Java"
+}
+
+// evidenceSource describes where the version information of a runtime comes from
+func evidenceSource(runtime JavaRuntimeJSON) string {
+	switch {
+	case runtime.VersionSource != "":
+		return runtime.VersionSource
+	case runtime.JavaVersion != "":
+		return "java-properties"
+	}
+	return "file"
+}
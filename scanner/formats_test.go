@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func testOutput() *JSONOutput {
+	requireLicense := true
+	return &JSONOutput{
+		Meta: MetaInfo{
+			ScanTimestamp: "2025-02-04T15:12:01Z",
+			ComputerName:  "build-01",
+			Host:          &HostIdentifiers{FQDN: "build-01.example.com", SerialNumber: "ABC123"},
+		},
+		Runtimes: []JavaRuntimeJSON{
+			{
+				JavaExecutable: "/opt/jdk-17/bin/java",
+				JavaHome:       "/opt/jdk-17",
+				JavaRuntime:    "Java(TM) SE Runtime Environment",
+				JavaVendor:     "Oracle Corporation",
+				JavaVersion:    "17.0.13",
+				Distribution:   distOracle,
+				RequireLicense: &requireLicense,
+				LicenseReason:  "Released 2024-10-15, after the NFTC free use window ended on 2024-09-30",
+			},
+			{JavaExecutable: "/opt/unknown/bin/java"},
+		},
+	}
+}
+
+func TestRenderServiceNowCSV(t *testing.T) {
+	data, err := renderServiceNowCSV(testOutput(), renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d records", len(records))
+	}
+
+	row := make(map[string]string)
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	if row["fqdn"] != "build-01.example.com" || row["serial_number"] != "ABC123" || row["computer_name"] != "build-01" {
+		t.Errorf("unexpected host columns %v", row)
+	}
+	if row["display_name"] != "Java(TM) SE Runtime Environment 17.0.13" || row["install_location"] != "/opt/jdk-17" {
+		t.Errorf("unexpected software columns %v", row)
+	}
+	if row["require_license"] != "true" {
+		t.Errorf("expected require_license true, got %q", row["require_license"])
+	}
+	if records[2][5] != "Java Runtime" {
+		t.Errorf("expected generic display name for unevaluated runtime, got %q", records[2][5])
+	}
+}
+
+func TestRenderFlexeraCSV(t *testing.T) {
+	output := testOutput()
+	output.Runtimes[0].Edition = "jdk"
+
+	data, err := renderFlexeraCSV(output, renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	want := []string{"build-01", "build-01.example.com", "ABC123", "Oracle Corporation", "Java SE", "jdk", "17.0.13",
+		"/opt/jdk-17", "/opt/jdk-17/bin/java", "java-properties", "2025-02-04T15:12:01Z", "true"}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("unexpected evidence row\n got: %v\nwant: %v", records[1], want)
+	}
+	if records[2][4] != "Java" || records[2][9] != "file" {
+		t.Errorf("unexpected evidence for unevaluated runtime: %v", records[2])
+	}
+}
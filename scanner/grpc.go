@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"jfind/collectorpb"
+)
+
+// grpcTimeout limits the submission of a report over gRPC
+const grpcTimeout = 2 * time.Minute
+
+// grpcMaxMessageSize is the size limit of a scan message for the client and jfind serve, the default of 4 MiB is
+// exceeded by the reports of hosts with thousands of runtimes
+const grpcMaxMessageSize = 64 << 20
+
+// isGRPCURL reports whether a collector URL selects the gRPC transport, grpc://host:port in plain text or
+// grpcs://host:port with TLS
+func isGRPCURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "grpc" || u.Scheme == "grpcs")
+}
+
+// tlsFiles are the PEM files of a mutual TLS connection: the certificate and key presented to the peer and the CA
+// verifying the peer
+type tlsFiles struct {
+	cert string
+	key  string
+	ca   string
+}
+
+// certPool reads the CA certificates of the peer
+func (t tlsFiles) certPool() (*x509.CertPool, error) {
+	data, err := os.ReadFile(t.ca)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificates: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in %s", t.ca)
+	}
+	return pool, nil
+}
+
+// clientConfig returns the TLS configuration of a client, without a CA the system roots verify the server
+func (t tlsFiles) clientConfig() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if t.cert != "" || t.key != "" {
+		cert, err := tls.LoadX509KeyPair(t.cert, t.key)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if t.ca != "" {
+		pool, err := t.certPool()
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// serverConfig returns the TLS configuration of a server, with a CA clients must present a certificate signed by it
+func (t tlsFiles) serverConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.cert, t.key)
+	if err != nil {
+		return nil, fmt.Errorf("loading server certificate: %v", err)
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+	if t.ca != "" {
+		pool, err := t.certPool()
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// scanMessage converts a report to the protobuf message of the collector schema. The complete JSON report is sent
+// in report_json, repeating the meta section and the runtimes as typed fields would double the size of the message.
+func scanMessage(output *JSONOutput) (*collectorpb.Scan, error) {
+	report, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	return &collectorpb.Scan{ReportJson: report}, nil
+}
+
+// reportFromMessage returns the report of a protobuf scan message, the complete JSON report if the client sent it,
+// otherwise the typed fields of clients of other platforms
+func reportFromMessage(scan *collectorpb.Scan) (*JSONOutput, error) {
+	var output JSONOutput
+	if len(scan.GetReportJson()) > 0 {
+		if err := json.Unmarshal(scan.GetReportJson(), &output); err != nil {
+			return nil, fmt.Errorf("parsing report: %v", err)
+		}
+		return &output, nil
+	}
+
+	m := scan.GetMeta()
+	output.Meta = MetaInfo{
+		ScanTimestamp:       m.GetScanTs(),
+		ComputerName:        m.GetComputerName(),
+		UserName:            m.GetUserName(),
+		ScanDuration:        m.GetScanDuration(),
+		HasOracleJDK:        m.GetHasOracleJdk(),
+		CountResult:         int(m.GetCountResult()),
+		CountRequireLicense: int(m.GetCountRequireLicense()),
+		ScannedDirs:         int(m.GetScannedDirs()),
+		ScanPath:            m.GetScanPath(),
+		PlatformInfo:        m.GetPlatformInfo(),
+		HasEntitlement:      m.GetHasEntitlementEvidence(),
+		Privileged:          m.GetPrivileged(),
+		ScanProfile:         m.GetScanProfile(),
+	}
+	output.Runtimes = make([]JavaRuntimeJSON, 0, len(scan.GetRuntimes()))
+	for _, r := range scan.GetRuntimes() {
+		output.Runtimes = append(output.Runtimes, JavaRuntimeJSON{
+			JavaExecutable:   r.GetJavaExecutable(),
+			JavaHome:         r.GetJavaHome(),
+			JavaRuntime:      r.GetJavaRuntime(),
+			JavaVendor:       r.GetJavaVendor(),
+			Distribution:     r.GetDistribution(),
+			Edition:          r.GetEdition(),
+			IsOracle:         r.GetIsOracle(),
+			JavaVersion:      r.GetJavaVersion(),
+			VersionMajor:     int(r.GetJavaVersionMajor()),
+			VersionUpdate:    int(r.GetJavaVersionUpdate()),
+			JavaVersionDate:  r.GetJavaVersionDate(),
+			ExecFailed:       r.GetExecFailed(),
+			VersionSource:    r.GetVersionSource(),
+			RequireLicense:   r.RequireLicense,
+			LicenseReason:    r.GetLicenseReason(),
+			LicenseFreeUntil: r.GetLicenseFreeUntil(),
+			BinaryArch:       r.GetBinaryArch(),
+			Symlinks:         r.GetSymlinks(),
+			Aliases:          r.GetAliases(),
+		})
+	}
+	return &output, nil
+}
+
+// sendGRPC submits a report to the collector at a grpc:// or grpcs:// URL and prints its response like the HTTP
+// collector does
+func sendGRPC(output *JSONOutput, rawURL string, files tlsFiles) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid URL %s: %v", rawURL, err)
+	}
+	creds := insecure.NewCredentials()
+	if u.Scheme == "grpcs" {
+		config, err := files.clientConfig()
+		if err != nil {
+			return err
+		}
+		creds = credentials.NewTLS(config)
+	}
+	conn, err := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(grpcMaxMessageSize)))
+	if err != nil {
+		return fmt.Errorf("failed to connect to server at %s: %v", u.Host, err)
+	}
+	defer conn.Close()
+
+	scan, err := scanMessage(output)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), grpcTimeout)
+	defer cancel()
+	resp, err := collectorpb.NewCollectorClient(conn).SubmitScan(ctx, &collectorpb.SubmitScanRequest{Scan: scan})
+	if err != nil {
+		return fmt.Errorf("failed to submit scan to %s: %v", u.Host, err)
+	}
+	fmt.Printf("{\"result\": \"ok\", \"scan_id\": %d}\n", resp.GetScanId())
+	return nil
+}
+
+// collectorServer is the gRPC collector of jfind serve, it stores the submitted scans in a SQLite database
+type collectorServer struct {
+	collectorpb.UnimplementedCollectorServer
+	database string
+	// mu serializes the writes to the database
+	mu sync.Mutex
+}
+
+// SubmitScan stores a scan report and returns its scan ID
+func (s *collectorServer) SubmitScan(_ context.Context, req *collectorpb.SubmitScanRequest) (*collectorpb.SubmitScanResponse, error) {
+	if req.GetScan().GetMeta() == nil && len(req.GetScan().GetReportJson()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "scan without meta section or report")
+	}
+	output, err := reportFromMessage(req.GetScan())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scanID, err := writeSQLite(s.database, output)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	slog.Info("Scan stored", "scan_id", scanID, "computer", output.Meta.ComputerName, "runtimes", len(output.Runtimes))
+	return &collectorpb.SubmitScanResponse{ScanId: scanID}, nil
+}
+
+// serveConfig holds the options of the serve command
+type serveConfig struct {
+	listen   string
+	database string
+	tls      tlsFiles
+	logs     logFlags
+	help     bool
+}
+
+// runServe runs a gRPC collector storing the submitted scans in a SQLite database
+func runServe(args []string) int {
+	var config serveConfig
+
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s serve [options]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Run a gRPC collector storing the scans submitted with -post -url grpc(s)://host:port in a SQLite database.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&config.listen, "listen", ":9443", "Address to listen on")
+	flags.StringVar(&config.database, "db", "jfind.db", "SQLite database the scans are stored in")
+	flags.StringVar(&config.tls.cert, "tls-cert", "", "Server certificate (PEM), enables TLS")
+	flags.StringVar(&config.tls.key, "tls-key", "", "Key of the server certificate (PEM)")
+	flags.StringVar(&config.tls.ca, "tls-ca", "", "CA certificates (PEM) of the client certificates, enables mutual TLS")
+	config.logs.register(flags)
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+	_ = flags.Parse(args)
+
+	if config.help {
+		flags.Usage()
+		return 0
+	}
+	if err := config.logs.setup(os.Stderr); err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	if config.tls.ca != "" && config.tls.cert == "" {
+		slog.Error("-tls-ca requires -tls-cert and -tls-key")
+		return 1
+	}
+	if !sqliteSupported {
+		slog.Error("jfind serve stores the scans in SQLite, which requires a build with cgo enabled, this jfind was built without cgo")
+		return 1
+	}
+
+	options := []grpc.ServerOption{grpc.MaxRecvMsgSize(grpcMaxMessageSize)}
+	if config.tls.cert != "" {
+		tlsConfig, err := config.tls.serverConfig()
+		if err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	listener, err := net.Listen("tcp", config.listen)
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+
+	server := grpc.NewServer(options...)
+	collectorpb.RegisterCollectorServer(server, &collectorServer{database: config.database})
+	slog.Info("Collector listening", "address", listener.Addr().String(), "database", config.database)
+	if err := server.Serve(listener); err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	return 0
+}
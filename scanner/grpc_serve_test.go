@@ -0,0 +1,52 @@
+//go:build cgo
+
+package main
+
+import (
+	"database/sql"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"jfind/collectorpb"
+)
+
+func TestSubmitScanGRPC(t *testing.T) {
+	database := filepath.Join(t.TempDir(), "scans.db")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	collectorpb.RegisterCollectorServer(server, &collectorServer{database: database})
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	output := &JSONOutput{
+		Meta:     MetaInfo{ScanTimestamp: "2026-10-16T08:00:00Z", ComputerName: "build01", CountResult: 1},
+		Runtimes: []JavaRuntimeJSON{{JavaExecutable: "/opt/jdk-17/bin/java", JavaVersion: "17.0.13", VersionMajor: 17}},
+		Errors:   []PathError{{Path: "/opt/secret", Class: errorClassPermission, Error: "permission denied"}},
+	}
+	if err := sendGRPC(output, "grpc://"+listener.Addr().String(), tlsFiles{}); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite3", database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var version string
+	var errors int
+	if err := db.QueryRow(`SELECT java_version FROM runtimes WHERE scan_id = 1`).Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM errors WHERE scan_id = 1`).Scan(&errors); err != nil {
+		t.Fatal(err)
+	}
+	if version != "17.0.13" || errors != 1 {
+		t.Errorf("expected the runtime and error of the complete report, got %q and %d errors", version, errors)
+	}
+}
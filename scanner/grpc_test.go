@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"jfind/collectorpb"
+)
+
+func TestScanMessage(t *testing.T) {
+	requireLicense := true
+	output := &JSONOutput{
+		Meta: MetaInfo{ScanTimestamp: "2026-10-16T08:00:00Z", ComputerName: "build01", CountResult: 2, CountRequireLicense: 1},
+		Runtimes: []JavaRuntimeJSON{
+			{JavaExecutable: "/opt/jdk1.8.0_351/bin/java", JavaVersion: "1.8.0_351", VersionMajor: 8, VersionUpdate: 351,
+				IsOracle: true, RequireLicense: &requireLicense, BinaryArch: []string{"x86_64"}},
+			{JavaExecutable: "/opt/broken/bin/java", ExecFailed: true},
+		},
+		Errors: []PathError{{Path: "/opt/secret", Class: errorClassPermission, Error: "permission denied"}},
+	}
+	scan, err := scanMessage(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the report is sent once, as JSON
+	if scan.Meta != nil || scan.Runtimes != nil || len(scan.ReportJson) == 0 {
+		t.Errorf("expected only the JSON report in the message, got %+v", scan)
+	}
+	decoded, err := reportFromMessage(scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Runtimes, output.Runtimes) || len(decoded.Errors) != 1 {
+		t.Errorf("unexpected report %+v", decoded)
+	}
+
+	// clients of other platforms may send only the fields of the schema
+	typed := &collectorpb.Scan{
+		Meta: &collectorpb.ScanMeta{ComputerName: "build01", CountRequireLicense: 1},
+		Runtimes: []*collectorpb.Runtime{
+			{JavaExecutable: "/opt/jdk1.8.0_351/bin/java", JavaVersion: "1.8.0_351", JavaVersionMajor: 8, JavaVersionUpdate: 351,
+				IsOracle: true, RequireLicense: &requireLicense, BinaryArch: []string{"x86_64"}},
+			{JavaExecutable: "/opt/broken/bin/java", ExecFailed: true},
+		},
+	}
+	decoded, err = reportFromMessage(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Runtimes, output.Runtimes) || decoded.Meta.ComputerName != "build01" || decoded.Meta.CountRequireLicense != 1 {
+		t.Errorf("unexpected report %+v", decoded)
+	}
+	if decoded.Runtimes[0].RequireLicense == nil || decoded.Runtimes[1].RequireLicense != nil {
+		t.Error("expected the license requirement only for the evaluated runtime")
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// ioregSerialPattern extracts the serial number from 'ioreg -c IOPlatformExpertDevice' output
+var ioregSerialPattern = regexp.MustCompile(`"IOPlatformSerialNumber"\s*=\s*"([^"]+)"`)
+
+// HostIdentifiers are the attributes CMDBs use to correlate a report with a configuration item
+type HostIdentifiers struct {
+	FQDN         string `json:"fqdn,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	IPAddress    string `json:"ip_address,omitempty"`
+	MACAddress   string `json:"mac_address,omitempty"`
+}
+
+// collectHostIdentifiers gathers the host identifiers, attributes which cannot be determined are left empty
+func collectHostIdentifiers() *HostIdentifiers {
+	ids := &HostIdentifiers{
+		FQDN:         getFQDN(),
+		SerialNumber: getSerialNumber(),
+	}
+	ids.IPAddress, ids.MACAddress = primaryAddress()
+	return ids
+}
+
+// getFQDN returns the fully qualified host name as resolved by DNS, or the plain host name
+func getFQDN() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	if cname, err := net.LookupCNAME(hostname); err == nil && cname != "" {
+		return strings.TrimSuffix(cname, ".")
+	}
+	return hostname
+}
+
+// getSerialNumber returns the hardware serial number, on Linux it is only readable by root
+func getSerialNumber() string {
+	var serial string
+	switch runtime.GOOS {
+	case "linux":
+		if data, err := os.ReadFile("/sys/class/dmi/id/product_serial"); err == nil {
+			serial = string(data)
+		}
+	case "darwin":
+		if out, err := exec.Command("ioreg", "-c", "IOPlatformExpertDevice", "-d", "2").Output(); err == nil {
+			if m := ioregSerialPattern.FindStringSubmatch(string(out)); m != nil {
+				serial = m[1]
+			}
+		}
+	case "windows":
+		if out, err := exec.Command("powershell", "-Command", "(Get-CimInstance Win32_BIOS).SerialNumber").Output(); err == nil {
+			serial = string(out)
+		}
+	}
+	return strings.TrimSpace(serial)
+}
+
+// primaryAddress returns the first IPv4 address and the MAC address of an active non-loopback interface
+func primaryAddress() (string, string) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return "", ""
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				return ipNet.IP.String(), iface.HardwareAddr.String()
+			}
+		}
+	}
+	return "", ""
+}
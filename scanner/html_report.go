@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// htmlReport is a self-contained HTML page with the summary and the runtimes of a report, for audit mails without a
+// server component. The styles are inline, the page loads nothing.
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"licensed": func(runtime JavaRuntimeJSON) bool { return runtime.RequireLicense != nil && *runtime.RequireLicense },
+	"join":     strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>jfind report {{.Meta.ComputerName}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.5em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+td.path { font-family: monospace; }
+tr.risk td { background: #fde2e1; }
+.badge { display: inline-block; padding: 0.1em 0.5em; border-radius: 0.8em; font-size: 0.85em; }
+.badge.risk { background: #c62828; color: #fff; }
+.badge.ok { background: #2e7d32; color: #fff; }
+.warning { background: #fff3cd; border: 1px solid #e0c36a; padding: 0.5em 1em; margin-bottom: 1em; }
+details { margin-bottom: 0.5em; }
+summary { cursor: pointer; font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>Java runtimes on {{.Meta.ComputerName}}</h1>
+{{if .Meta.Truncated}}<p class="warning">The scan ended early, the report is incomplete (last scanned path: {{.Meta.LastScannedPath}}).</p>
+{{end}}
+<h2>Summary</h2>
+<table>
+<tr><th>Computer</th><td>{{.Meta.ComputerName}}</td></tr>
+<tr><th>Scanned</th><td>{{.Meta.ScanTimestamp}} by {{.Meta.UserName}}{{if not .Meta.Privileged}} (without administrative privileges){{end}}</td></tr>
+<tr><th>Scan path</th><td class="path">{{.Meta.ScanPath}}</td></tr>
+<tr><th>Platform</th><td>{{.Meta.PlatformInfo}}</td></tr>
+<tr><th>Runtimes</th><td>{{len .Runtimes}}</td></tr>
+<tr><th>Requiring a license</th><td>{{if .Meta.CountRequireLicense}}<span class="badge risk">{{.Meta.CountRequireLicense}}</span>{{else}}<span class="badge ok">0</span>{{end}}</td></tr>
+<tr><th>Oracle JDK found</th><td>{{if .Meta.HasOracleJDK}}yes{{else}}no{{end}}</td></tr>
+<tr><th>Entitlement evidence</th><td>{{if .Meta.HasEntitlement}}yes{{else}}no{{end}}</td></tr>
+</table>
+<h2>Runtimes</h2>
+<table>
+<tr><th>Executable</th><th>Version</th><th>Vendor</th><th>Distribution</th><th>License</th></tr>
+{{range .Runtimes}}<tr{{if licensed .}} class="risk"{{end}}>
+<td class="path">{{.JavaExecutable}}</td><td>{{.JavaVersion}}</td><td>{{.JavaVendor}}</td><td>{{.Distribution}}</td>
+<td>{{if licensed .}}<span class="badge risk">required</span> {{.LicenseReason}}{{else if .RequireLicense}}not required{{else if .ExecFailed}}unknown, execution failed{{else}}unknown{{end}}</td>
+</tr>
+{{end}}</table>
+<h2>Details</h2>
+{{range .Runtimes}}<details>
+<summary>{{.JavaExecutable}}</summary>
+<table>
+{{if .JavaHome}}<tr><th>Java home</th><td class="path">{{.JavaHome}}</td></tr>
+{{end}}{{if .JavaRuntime}}<tr><th>Runtime</th><td>{{.JavaRuntime}}</td></tr>
+{{end}}{{if .JavaVMName}}<tr><th>VM</th><td>{{.JavaVMName}}</td></tr>
+{{end}}{{if .Edition}}<tr><th>Edition</th><td>{{.Edition}}</td></tr>
+{{end}}{{if .JavaVersionDate}}<tr><th>Release date</th><td>{{.JavaVersionDate}}</td></tr>
+{{end}}{{if .LicenseFreeUntil}}<tr><th>Free use until</th><td>{{.LicenseFreeUntil}}</td></tr>
+{{end}}{{if .LicenseReason}}<tr><th>License</th><td>{{.LicenseReason}}</td></tr>
+{{end}}{{if .ExecError}}<tr><th>Execution error</th><td>{{.ExecError}}</td></tr>
+{{end}}{{if .Symlinks}}<tr><th>Symbolic links</th><td class="path">{{join .Symlinks ", "}}</td></tr>
+{{end}}{{if .Aliases}}<tr><th>Aliases</th><td class="path">{{join .Aliases ", "}}</td></tr>
+{{end}}{{if .InstalledProgram}}<tr><th>Installed program</th><td>{{.InstalledProgram}}</td></tr>
+{{end}}{{if .ProfileUser}}<tr><th>Profile of</th><td>{{.ProfileUser}}</td></tr>
+{{end}}{{if .DuplicateOf}}<tr><th>Duplicate of</th><td class="path">{{.DuplicateOf}}</td></tr>
+{{end}}{{if .DeploymentRisks}}<tr><th>Deployment risks</th><td>{{join .DeploymentRisks ", "}}</td></tr>
+{{end}}{{if .CommercialFeatures}}<tr><th>Commercial features</th><td>{{join .CommercialFeatures ", "}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}{{if .Entitlements}}<h2>Entitlement Evidence</h2>
+<table>
+<tr><th>Type</th><th>Path</th><th>Detail</th></tr>
+{{range .Entitlements}}<tr><td>{{.Type}}</td><td class="path">{{.Path}}</td><td>{{.Detail}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// renderHTML renders a report as a self-contained HTML page
+func renderHTML(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlReport.Execute(&buf, output); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// reportConfig holds the options of the report command
+type reportConfig struct {
+	outputPath string
+	format     string
+	template   string
+	html       bool
+	help       bool
+	options    renderOptions
+}
+
+// runReport renders a JSON report in another output format, by default as an HTML page
+func runReport(args []string) int {
+	var config reportConfig
+
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s report [-html | -format <format>] [-o <file>] <report.json|->\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Render a JSON report of a scan in another output format.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&config.outputPath, "o", "", "File to write the report to, gzip compressed if the name ends with .gz (default: standard output)")
+	flags.StringVar(&config.format, "format", "html", "Output format: "+strings.TrimPrefix(formatNames(), "text, "))
+	flags.StringVar(&config.template, "template", "", "Go template of -format template, executed for every runtime")
+	flags.BoolVar(&config.html, "html", false, "Render an HTML page, short for -format html")
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+	_ = flags.Parse(args)
+
+	if config.help || flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+	if config.html {
+		config.format = "html"
+	}
+	if config.template != "" {
+		config.format = "template"
+		var err error
+		if config.options.template, err = parseOutputTemplate(config.template); err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+	}
+	format, ok := outputFormats[config.format]
+	if !ok {
+		slog.Error("unknown output format", "format", config.format, "supported", strings.TrimPrefix(formatNames(), "text, "))
+		return 1
+	}
+
+	var data []byte
+	var err error
+	if flags.Arg(0) == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(flags.Arg(0))
+	}
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	var report JSONOutput
+	if err := json.Unmarshal(data, &report); err != nil {
+		slog.Error("parsing report", "error", err)
+		return 1
+	}
+
+	rendered, err := format.render(&report, config.options)
+	if err != nil {
+		slog.Error("rendering the report", "format", config.format, "error", err)
+		return 1
+	}
+	if config.outputPath == "" {
+		if _, err := os.Stdout.Write(rendered); err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+		return 0
+	}
+	if err := writeFileAtomic(config.outputPath, rendered); err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	slog.Info("Report written", "runtimes", len(report.Runtimes), "path", config.outputPath)
+	return 0
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	output := testOutput()
+	output.Meta.CountRequireLicense = 1
+	output.Runtimes[1].JavaExecutable = "/opt/<script>alert(1)</script>/bin/java"
+
+	data, err := renderHTML(output, renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		"<title>jfind report build-01</title>",
+		`<tr class="risk">`,
+		`<span class="badge risk">required</span> Released 2024-10-15`,
+		"<summary>/opt/jdk-17/bin/java</summary>",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected %q in the HTML report", want)
+		}
+	}
+	if strings.Contains(page, "<script>") {
+		t.Errorf("expected paths to be escaped")
+	}
+	// the page is self-contained
+	if strings.Contains(page, "src=") || strings.Contains(page, "href=") {
+		t.Errorf("expected no external resources")
+	}
+}
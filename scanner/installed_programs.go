@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// uninstallKeys are the registry keys listing the installed programs of 'Apps & features'
+var uninstallKeys = []string{
+	`HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`HKLM\SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`HKCU\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+}
+
+// javaProgramPattern matches display names of Java installers (Oracle Java 8 Update X, JDK MSIs, vendor installers)
+var javaProgramPattern = regexp.MustCompile(`(?i)\b(java|jdk|jre|openjdk|temurin|zulu|corretto|semeru|liberica|graalvm)\b`)
+
+// regValuePattern matches a value line of 'reg query' output: name, type and data separated by four spaces
+var regValuePattern = regexp.MustCompile(`^ {4}(.+?) {4}(REG_\w+)(?: {4}(.*))?$`)
+
+// Installer technologies of installed programs
+const (
+	installerMSI = "msi"
+	installerEXE = "exe"
+	installerPkg = "pkg"
+)
+
+// productCodePattern matches the product code GUID naming the uninstall key of an MSI installation
+var productCodePattern = regexp.MustCompile(`^\{[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}\}$`)
+
+// InstalledProgram is a Java related entry of the Windows installed programs or a macOS package receipt
+type InstalledProgram struct {
+	Name            string `json:"name"`
+	Version         string `json:"version,omitempty"`
+	Publisher       string `json:"publisher,omitempty"`
+	InstallLocation string `json:"install_location,omitempty"`
+	RegistryKey     string `json:"registry_key,omitempty"`
+	PackageID       string `json:"package_id,omitempty"`
+	InstallTime     string `json:"install_time,omitempty"`
+	// Installer is the installer technology: msi, exe (other Windows installers) or pkg (macOS)
+	Installer   string `json:"installer,omitempty"`
+	ProductCode string `json:"product_code,omitempty"`
+	// LocationMissing is set when the installation directory no longer exists
+	LocationMissing bool `json:"install_location_missing,omitempty"`
+}
+
+// checkLocation flags the program if its installation directory no longer exists
+func (p *InstalledProgram) checkLocation() {
+	if p.InstallLocation == "" {
+		return
+	}
+	if _, err := os.Stat(p.InstallLocation); os.IsNotExist(err) {
+		p.LocationMissing = true
+	}
+}
+
+// registryKey is a key of 'reg query /s' output with its values
+type registryKey struct {
+	path   string
+	values map[string]string
+}
+
+// parseRegQuery parses the output of 'reg query /s' into keys and their values
+func parseRegQuery(out string) []registryKey {
+	var keys []registryKey
+	for _, line := range strings.Split(strings.ReplaceAll(out, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "HKEY_") {
+			keys = append(keys, registryKey{path: strings.TrimSpace(line), values: map[string]string{}})
+			continue
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if m := regValuePattern.FindStringSubmatch(line); m != nil {
+			keys[len(keys)-1].values[m[1]] = strings.TrimSpace(m[3])
+		}
+	}
+	return keys
+}
+
+// expandRegistryValue expands the %NAME% references of a REG_EXPAND_SZ value like ExpandEnvironmentStrings,
+// references to undefined variables are kept. $ is a regular character of Windows paths.
+func expandRegistryValue(value string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(value, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(value[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+		b.WriteString(value[:start])
+		if expanded, ok := os.LookupEnv(value[start+1 : end]); ok && end > start+1 {
+			b.WriteString(expanded)
+			value = value[end+1:]
+			continue
+		}
+		// the closing % may open the next reference
+		b.WriteString(value[start:end])
+		value = value[end:]
+	}
+	b.WriteString(value)
+	return b.String()
+}
+
+// javaPrograms returns the Java related installed programs of parsed uninstall keys
+func javaPrograms(keys []registryKey) []InstalledProgram {
+	var programs []InstalledProgram
+	for _, key := range keys {
+		name := key.values["DisplayName"]
+		if name == "" || !javaProgramPattern.MatchString(name) {
+			continue
+		}
+		program := InstalledProgram{
+			Name:            name,
+			Version:         key.values["DisplayVersion"],
+			Publisher:       key.values["Publisher"],
+			InstallLocation: strings.Trim(expandRegistryValue(key.values["InstallLocation"]), `"`),
+			RegistryKey:     key.path,
+			Installer:       installerEXE,
+		}
+		if code := key.path[strings.LastIndex(key.path, `\`)+1:]; key.values["WindowsInstaller"] == "0x1" && productCodePattern.MatchString(code) {
+			program.Installer = installerMSI
+			program.ProductCode = code
+			program.completeFromInstaller(msiProductInfo)
+		}
+		program.checkLocation()
+		programs = append(programs, program)
+	}
+	return programs
+}
+
+// completeFromInstaller fills the fields the uninstall key of an MSI installation lacks from the product
+// registration of Windows Installer, many JDK MSIs do not write their installation directory to the uninstall key
+func (p *InstalledProgram) completeFromInstaller(productInfo func(productCode, property string) string) {
+	for property, field := range map[string]*string{
+		"InstalledProductName": &p.Name,
+		"VersionString":        &p.Version,
+		"Publisher":            &p.Publisher,
+		"InstallLocation":      &p.InstallLocation,
+	} {
+		if *field == "" {
+			*field = productInfo(p.ProductCode, property)
+		}
+	}
+}
+
+// detectInstalledPrograms enumerates the Java related installed programs (Windows) or package receipts (macOS),
+// it returns false on platforms without an installer registry
+func detectInstalledPrograms() ([]InstalledProgram, bool) {
+	switch runtime.GOOS {
+	case "windows":
+		return registryPrograms(), true
+	case "darwin":
+		return packageReceipts(), true
+	default:
+		return nil, false
+	}
+}
+
+// registryPrograms enumerates the Java related installed programs using reg.exe
+func registryPrograms() []InstalledProgram {
+	var programs []InstalledProgram
+	for _, key := range uninstallKeys {
+		out, err := exec.Command("reg", "query", key, "/s").Output()
+		if err != nil {
+			continue
+		}
+		programs = append(programs, javaPrograms(parseRegQuery(string(out)))...)
+	}
+	return programs
+}
+
+// correlateInstalledPrograms links runtimes to the installed program whose installation directory contains them,
+// runtimes without an entry were copied or extracted instead of installed
+func correlateInstalledPrograms(programs []InstalledProgram, results []*JavaResult) {
+	for _, result := range results {
+		home := result.JavaHome
+		if home == "" {
+			home = filepath.Dir(filepath.Dir(result.Path))
+		}
+		home = canonicalPath(home)
+
+		result.Unregistered = true
+		for _, program := range programs {
+			if program.InstallLocation == "" || program.LocationMissing {
+				continue
+			}
+			location := strings.TrimRight(canonicalPath(program.InstallLocation), `\/`)
+			if home == location || strings.HasPrefix(home, location+string(filepath.Separator)) {
+				result.InstalledProgram = program.Name
+				result.Program = &program
+				result.Unregistered = false
+				break
+			}
+		}
+	}
+}
+
+// printStalePrograms prints the installed programs whose installation directory no longer exists
+func printStalePrograms(programs []InstalledProgram) {
+	var stale []InstalledProgram
+	for _, program := range programs {
+		if program.LocationMissing {
+			stale = append(stale, program)
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+	fmt.Printf("Installed programs with missing installation directory:\n")
+	for _, program := range stale {
+		fmt.Printf("- %s: %s\n", program.Name, program.InstallLocation)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+// msiProductInfo returns no product information, Windows Installer is only available on Windows
+func msiProductInfo(productCode, property string) string {
+	return ""
+}
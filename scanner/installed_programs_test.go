@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const regQueryOutput = "\r\n" +
+	"HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Uninstall\\{26A24AE4-039D-4CA4-87B4-2F64180351F0}\r\n" +
+	"    AuthorizedCDFPrefix    REG_SZ    \r\n" +
+	"    DisplayName    REG_SZ    Java 8 Update 351 (64-bit)\r\n" +
+	"    DisplayVersion    REG_SZ    8.0.3510.10\r\n" +
+	"    Publisher    REG_SZ    Oracle Corporation\r\n" +
+	"    InstallLocation    REG_SZ    %s\r\n" +
+	"    WindowsInstaller    REG_DWORD    0x1\r\n" +
+	"\r\n" +
+	"HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Uninstall\\7-Zip\r\n" +
+	"    DisplayName    REG_SZ    7-Zip 23.01 (x64)\r\n" +
+	"\r\n" +
+	"HKEY_LOCAL_MACHINE\\SOFTWARE\\Microsoft\\Windows\\CurrentVersion\\Uninstall\\{5F0F7A41-0E8B-4F7B-9B8E-1D0D2C2F3A11}\r\n" +
+	"    DisplayName    REG_SZ    Eclipse Temurin JDK with Hotspot 17.0.9+9 (x64)\r\n" +
+	"    InstallLocation    REG_SZ    %s\r\n"
+
+func TestJavaPrograms(t *testing.T) {
+	dir := t.TempDir()
+	installed := filepath.Join(dir, "jre1.8.0_351")
+	if err := os.Mkdir(installed, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	removed := filepath.Join(dir, "jdk-17.0.9.9-hotspot")
+
+	out := regQueryOutput
+	out = strings.Replace(out, "%s", installed+string(filepath.Separator), 1)
+	out = strings.Replace(out, "%s", removed, 1)
+	programs := javaPrograms(parseRegQuery(out))
+	if len(programs) != 2 {
+		t.Fatalf("expected 2 Java programs, got %+v", programs)
+	}
+	if programs[0].Name != "Java 8 Update 351 (64-bit)" || programs[0].Version != "8.0.3510.10" ||
+		programs[0].Publisher != "Oracle Corporation" || programs[0].LocationMissing {
+		t.Errorf("unexpected program %+v", programs[0])
+	}
+	if programs[0].Installer != installerMSI || programs[0].ProductCode != "{26A24AE4-039D-4CA4-87B4-2F64180351F0}" {
+		t.Errorf("expected MSI installation, got %+v", programs[0])
+	}
+	if programs[1].Installer != installerEXE || programs[1].ProductCode != "" {
+		t.Errorf("expected other installer, got %+v", programs[1])
+	}
+	if !programs[1].LocationMissing {
+		t.Errorf("expected missing installation directory for %+v", programs[1])
+	}
+
+	results := []*JavaResult{
+		{Path: filepath.Join(installed, "bin", "java"), JavaHome: installed},
+		{Path: filepath.Join(dir, "extracted", "bin", "java"), JavaHome: filepath.Join(dir, "extracted")},
+	}
+	correlateInstalledPrograms(programs, results)
+	if results[0].InstalledProgram != programs[0].Name || results[0].Unregistered ||
+		results[0].Program == nil || results[0].Program.ProductCode != programs[0].ProductCode {
+		t.Errorf("expected registered runtime, got %+v", results[0])
+	}
+	if results[1].InstalledProgram != "" || !results[1].Unregistered {
+		t.Errorf("expected unregistered runtime, got %+v", results[1])
+	}
+}
+
+func TestCompleteFromInstaller(t *testing.T) {
+	program := InstalledProgram{Name: "Java SE Development Kit 8 Update 202 (64-bit)", Installer: installerMSI,
+		ProductCode: "{64A3A4F4-B792-11D6-A78A-00B0D0180202}"}
+	registered := map[string]string{
+		"InstalledProductName": "Java SE Development Kit 8 Update 202",
+		"VersionString":        "8.0.2020.8",
+		"Publisher":            "Oracle Corporation",
+		"InstallLocation":      `C:\Program Files\Java\jdk1.8.0_202\`,
+	}
+	program.completeFromInstaller(func(productCode, property string) string {
+		if productCode != program.ProductCode {
+			t.Errorf("unexpected product code %s", productCode)
+		}
+		return registered[property]
+	})
+	// values of the uninstall key are kept
+	if program.Name != "Java SE Development Kit 8 Update 202 (64-bit)" || program.Version != "8.0.2020.8" ||
+		program.Publisher != "Oracle Corporation" || program.InstallLocation != `C:\Program Files\Java\jdk1.8.0_202\` {
+		t.Errorf("unexpected program %+v", program)
+	}
+}
+
+func TestExpandRegistryValue(t *testing.T) {
+	t.Setenv("JFIND_PROGRAM_FILES", `C:\Program Files`)
+	tests := map[string]string{
+		`%JFIND_PROGRAM_FILES%\Java\jdk-17`: `C:\Program Files\Java\jdk-17`,
+		`C:\Apps\$JAVA_HOME\jre`:            `C:\Apps\$JAVA_HOME\jre`,
+		`%JFIND_UNDEFINED%\jre`:             `%JFIND_UNDEFINED%\jre`,
+		`100% %JFIND_PROGRAM_FILES%`:        `100% C:\Program Files`,
+		`%%JFIND_PROGRAM_FILES%\Java`:       `%C:\Program Files\Java`,
+		`C:\Java\jdk-17`:                    `C:\Java\jdk-17`,
+	}
+	for value, want := range tests {
+		if got := expandRegistryValue(value); got != want {
+			t.Errorf("expandRegistryValue(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package main
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procMsiGetProductInfo = windows.NewLazySystemDLL("msi.dll").NewProc("MsiGetProductInfoW")
+
+// msiProductInfo returns a property of an installed MSI product from the Windows Installer registration,
+// empty if the product or property is unknown
+func msiProductInfo(productCode, property string) string {
+	product, err := windows.UTF16PtrFromString(productCode)
+	if err != nil {
+		return ""
+	}
+	attribute, err := windows.UTF16PtrFromString(property)
+	if err != nil {
+		return ""
+	}
+	if procMsiGetProductInfo.Find() != nil {
+		return ""
+	}
+
+	size := uint32(260)
+	for {
+		buf := make([]uint16, size+1)
+		n := size + 1
+		ret, _, _ := procMsiGetProductInfo.Call(uintptr(unsafe.Pointer(product)), uintptr(unsafe.Pointer(attribute)),
+			uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&n)))
+		switch windows.Errno(ret) {
+		case windows.ERROR_SUCCESS:
+			return windows.UTF16ToString(buf[:n])
+		case windows.ERROR_MORE_DATA:
+			size = n
+		default:
+			return ""
+		}
+	}
+}
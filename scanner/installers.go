@@ -0,0 +1,178 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// installerExtensions are the file name extensions of JDK installers and archives
+var installerExtensions = []string{".exe", ".msi", ".dmg", ".pkg", ".tar.gz", ".tgz", ".zip", ".rpm", ".deb"}
+
+// installerPatterns recognize the download file names of JDK installers by their product and version groups,
+// the product defaults to jdk
+var installerPatterns = []struct {
+	vendor  string
+	pattern *regexp.Regexp
+}{
+	{"Oracle Corporation", regexp.MustCompile(`^(?P<product>jdk|jre)-(?P<version>\d+u\d+)-(?:windows|linux|macosx|solaris)`)},
+	{"Oracle Corporation", regexp.MustCompile(`^(?P<product>jdk|jre)-(?P<version>\d+(?:\.\d+)*)_(?:windows|linux|macos|macosx)-`)},
+	{"Oracle Corporation", regexp.MustCompile(`^graalvm-jdk-(?P<version>\d+(?:\.\d+)*)_`)},
+	{"GraalVM Community", regexp.MustCompile(`^graalvm-community-jdk-(?P<version>\d+(?:\.\d+)*)_`)},
+	{"Eclipse Adoptium", regexp.MustCompile(`^OpenJDK\d+U-(?P<product>jdk|jre)_\w+?_(?:windows|linux|mac|alpine-linux|aix)_hotspot_(?P<version>[\w.]+?)\.(?:tar\.gz|\w+)$`)},
+	{"Azul Systems, Inc.", regexp.MustCompile(`^zulu[\d.]+-ca-(?:fx-)?(?P<product>jdk|jre)(?P<version>\d+(?:\.\d+)*)-`)},
+	{"Amazon.com Inc.", regexp.MustCompile(`^amazon-corretto-(?P<version>\d+(?:\.\d+)*)-.*?(?:-(?P<product>jdk|jre))?\.`)},
+	{"Microsoft", regexp.MustCompile(`^microsoft-jdk-(?P<version>\d+(?:\.\d+)*)-`)},
+	{"BellSoft", regexp.MustCompile(`^bellsoft-(?P<product>jdk|jre)(?P<version>\d+(?:[.u]\d+)*)`)},
+}
+
+// InstallerArtifact is a downloaded but not extracted or installed JDK installer
+type InstallerArtifact struct {
+	Path           string `json:"path"`
+	Vendor         string `json:"vendor"`
+	Product        string `json:"product"`
+	Version        string `json:"version"`
+	Size           int64  `json:"size_bytes"`
+	SHA256         string `json:"sha256,omitempty"`
+	RequireLicense *bool  `json:"require_license,omitempty"`
+}
+
+// hasInstallerExtension reports whether a file name has the extension of an installer or archive
+func hasInstallerExtension(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range installerExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseInstallerName recognizes a JDK installer by its file name and returns vendor, product and version
+func parseInstallerName(name string) (InstallerArtifact, bool) {
+	if !hasInstallerExtension(name) {
+		return InstallerArtifact{}, false
+	}
+	for _, p := range installerPatterns {
+		m := p.pattern.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		artifact := InstallerArtifact{Vendor: p.vendor, Product: "jdk", Version: m[p.pattern.SubexpIndex("version")]}
+		if i := p.pattern.SubexpIndex("product"); i > 0 && m[i] != "" {
+			artifact.Product = m[i]
+		}
+		return artifact, true
+	}
+	return InstallerArtifact{}, false
+}
+
+// licenseRequirement applies the license rules to an Oracle JDK installer, the version is taken from the file name
+func (a *InstallerArtifact) licenseRequirement() {
+	if a.Vendor != "Oracle Corporation" {
+		return
+	}
+	version := a.Version
+	// 8u202 is the file name form of 1.8.0_202
+	if major, update, ok := strings.Cut(version, "u"); ok {
+		version = "1." + major + ".0_" + update
+	}
+	runtime := JavaRuntimeJSON{IsOracle: true, JavaRuntime: oracleJDKRuntimeName}
+	runtime.VersionMajor, runtime.VersionUpdate = parseJavaVersion(version)
+	runtime.checkLicenseRequirement()
+	a.RequireLicense = runtime.RequireLicense
+}
+
+// inspectInstaller returns the installer artifact of a file, or false if it is not a JDK installer
+func inspectInstaller(path string) (InstallerArtifact, bool) {
+	artifact, ok := parseInstallerName(filepath.Base(path))
+	if !ok {
+		return artifact, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return artifact, false
+	}
+	artifact.Path = path
+	artifact.Size = info.Size()
+	artifact.SHA256, _ = fileSHA256(path)
+	artifact.licenseRequirement()
+	return artifact, true
+}
+
+// checkInstaller records the file if it is a JDK installer, it is called by the walkers
+func (f *JavaFinder) checkInstaller(path string) {
+	artifact, ok := inspectInstaller(path)
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	f.installers = append(f.installers, artifact)
+	f.mu.Unlock()
+}
+
+// installerDirs returns the download and temporary directories installers are usually left in
+func installerDirs(homes []string) []string {
+	dirs := []string{os.TempDir()}
+	for _, home := range homes {
+		dirs = append(dirs, filepath.Join(home, "Downloads"))
+	}
+	return dirs
+}
+
+// findInstallersIn searches dir up to two levels deep for JDK installers
+func findInstallersIn(dir string) []InstallerArtifact {
+	var artifacts []InstallerArtifact
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if pathDepth(dir, path) > 2 {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if artifact, ok := inspectInstaller(path); ok {
+			artifacts = append(artifacts, artifact)
+		}
+		return nil
+	})
+	return artifacts
+}
+
+// mergeInstallers combines installers found by several searches, sorted by path without duplicates
+func mergeInstallers(lists ...[]InstallerArtifact) []InstallerArtifact {
+	seen := make(map[string]bool)
+	var merged []InstallerArtifact
+	for _, list := range lists {
+		for _, artifact := range list {
+			if !seen[artifact.Path] {
+				seen[artifact.Path] = true
+				merged = append(merged, artifact)
+			}
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Path < merged[j].Path })
+	return merged
+}
+
+// printInstallers prints the installers found in text output mode
+func printInstallers(installers []InstallerArtifact) {
+	if len(installers) == 0 {
+		return
+	}
+	printf("JDK installers:\n")
+	for _, a := range installers {
+		license := ""
+		if a.RequireLicense != nil && *a.RequireLicense {
+			license = ", requires a commercial license"
+		}
+		printf("- %s: %s %s %s (%s%s)\n", a.Path, a.Vendor, a.Product, a.Version, humanize.Bytes(uint64(a.Size)), license)
+	}
+}
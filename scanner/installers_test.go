@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseInstallerName(t *testing.T) {
+	tests := []struct {
+		name, vendor, product, version string
+	}{
+		{"jdk-8u202-windows-x64.exe", "Oracle Corporation", "jdk", "8u202"},
+		{"jre-8u351-linux-x64.tar.gz", "Oracle Corporation", "jre", "8u351"},
+		{"jdk-17.0.2_windows-x64_bin.msi", "Oracle Corporation", "jdk", "17.0.2"},
+		{"jdk-21_macos-aarch64_bin.dmg", "Oracle Corporation", "jdk", "21"},
+		{"graalvm-jdk-21.0.1_linux-x64_bin.tar.gz", "Oracle Corporation", "jdk", "21.0.1"},
+		{"OpenJDK17U-jdk_x64_windows_hotspot_17.0.9_9.msi", "Eclipse Adoptium", "jdk", "17.0.9_9"},
+		{"OpenJDK8U-jre_x64_linux_hotspot_8u392b08.tar.gz", "Eclipse Adoptium", "jre", "8u392b08"},
+		{"zulu17.46.19-ca-jdk17.0.9-win_x64.msi", "Azul Systems, Inc.", "jdk", "17.0.9"},
+		{"amazon-corretto-17.0.9.8.1-windows-x64-jdk.msi", "Amazon.com Inc.", "jdk", "17.0.9.8.1"},
+		{"amazon-corretto-21.0.1.12.1-linux-x64.tar.gz", "Amazon.com Inc.", "jdk", "21.0.1.12.1"},
+		{"microsoft-jdk-17.0.9-windows-x64.msi", "Microsoft", "jdk", "17.0.9"},
+		{"bellsoft-jre8u392+9-windows-amd64.msi", "BellSoft", "jre", "8u392"},
+	}
+	for _, tt := range tests {
+		artifact, ok := parseInstallerName(tt.name)
+		if !ok || artifact.Vendor != tt.vendor || artifact.Product != tt.product || artifact.Version != tt.version {
+			t.Errorf("parseInstallerName(%s) = %+v, %v", tt.name, artifact, ok)
+		}
+	}
+
+	for _, name := range []string{"jdk-8u202-windows-x64.exe.txt", "notes-jdk-17.md", "setup.exe", "jdk-17.0.2_windows-x64_bin"} {
+		if _, ok := parseInstallerName(name); ok {
+			t.Errorf("unexpected installer %s", name)
+		}
+	}
+}
+
+func TestInstallerLicenseRequirement(t *testing.T) {
+	tests := map[string]bool{
+		"jdk-8u202-windows-x64.exe":       false,
+		"jdk-8u211-windows-x64.exe":       true,
+		"jdk-11.0.2_linux-x64_bin.tar.gz": true,
+	}
+	for name, expected := range tests {
+		artifact, _ := parseInstallerName(name)
+		artifact.licenseRequirement()
+		if artifact.RequireLicense == nil || *artifact.RequireLicense != expected {
+			t.Errorf("%s: expected require_license %v, got %v", name, expected, artifact.RequireLicense)
+		}
+	}
+
+	artifact, _ := parseInstallerName("zulu17.46.19-ca-jdk17.0.9-win_x64.msi")
+	if artifact.licenseRequirement(); artifact.RequireLicense != nil {
+		t.Error("license rules only apply to Oracle installers")
+	}
+}
+
+func TestFindInstallers(t *testing.T) {
+	root := t.TempDir()
+	shallow := filepath.Join(root, "jdk-8u211-windows-x64.exe")
+	nested := filepath.Join(root, "software", "java", "OpenJDK17U-jdk_x64_linux_hotspot_17.0.9_9.tar.gz")
+	deep := filepath.Join(root, "a", "b", "c", "jdk-17.0.2_windows-x64_bin.msi")
+	for _, path := range []string{shallow, nested, deep} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("installer"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := findInstallersIn(root)
+	if len(found) != 2 || found[0].Path != shallow || found[1].Path != nested {
+		t.Fatalf("unexpected installers %+v", found)
+	}
+	if found[0].Size != 9 || found[0].SHA256 == "" {
+		t.Errorf("unexpected size or hash %+v", found[0])
+	}
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.findInstallers = true
+		if _, err := finder.Find(); err != nil {
+			t.Fatal(err)
+		}
+		if installers := mergeInstallers(finder.installers, found); len(installers) != 3 {
+			t.Errorf("threads %d: expected 3 installers, got %+v", threads, installers)
+		}
+	}
+}
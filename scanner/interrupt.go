@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// exitInterrupted is the exit code of a scan ended by Ctrl-C or SIGTERM, the shell convention for SIGINT
+const exitInterrupted = 130
+
+// interruptHandler handles Ctrl-C and SIGTERM during a scan. The first signal cancels walkCtx: the walk stops, the
+// java executables found so far are evaluated and reported. A second signal cancels ctx, which kills the running
+// evaluations and aborts the scan. A signal after the walk runs the cleanups and exits.
+type interruptHandler struct {
+	ctx         context.Context
+	walkCtx     context.Context
+	abort       context.CancelFunc
+	stopWalk    context.CancelFunc
+	signals     chan os.Signal
+	released    chan struct{}
+	interrupted atomic.Bool
+
+	mu       sync.Mutex
+	cleanups []func()
+}
+
+// newInterruptHandler starts handling the signals of the scan
+func newInterruptHandler() *interruptHandler {
+	h := &interruptHandler{signals: make(chan os.Signal, 2), released: make(chan struct{})}
+	h.ctx, h.abort = context.WithCancel(context.Background())
+	h.walkCtx, h.stopWalk = context.WithCancel(h.ctx)
+	signal.Notify(h.signals, os.Interrupt, syscall.SIGTERM)
+	go h.run()
+	return h
+}
+
+func (h *interruptHandler) run() {
+	select {
+	case <-h.signals:
+	case <-h.ctx.Done():
+		h.exitOnSignal()
+		return
+	}
+	h.interrupted.Store(true)
+	h.stopWalk()
+	if interactiveLogs() {
+		// ends a progress report updated in place
+		fmt.Fprintln(os.Stderr)
+	}
+	slog.Warn("interrupted, reporting the runtimes found so far (interrupt again to abort)")
+
+	select {
+	case <-h.signals:
+		h.abort()
+	case <-h.ctx.Done():
+	}
+	h.exitOnSignal()
+}
+
+// exitOnSignal runs the cleanups and exits on a signal after the walk, until the handler is released
+func (h *interruptHandler) exitOnSignal() {
+	select {
+	case <-h.signals:
+	case <-h.released:
+		return
+	}
+	h.mu.Lock()
+	cleanups := h.cleanups
+	h.mu.Unlock()
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+	os.Exit(exitInterrupted)
+}
+
+// onExit registers a cleanup run when a signal terminates jfind, e.g. unmounting a share. Cleanups also have to be
+// deferred for a normal return.
+func (h *interruptHandler) onExit(cleanup func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cleanups = append(h.cleanups, cleanup)
+}
+
+// stop ends the handling of the walk once the scan is done, later signals run the cleanups and terminate jfind
+func (h *interruptHandler) stop() {
+	h.abort()
+}
+
+// release ends the handling of signals when the scan returns
+func (h *interruptHandler) release() {
+	signal.Stop(h.signals)
+	h.abort()
+	close(h.released)
+}
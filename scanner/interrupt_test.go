@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestInterruptHandler(t *testing.T) {
+	h := newInterruptHandler()
+	defer h.release()
+
+	// the first interrupt ends the walk only
+	h.signals <- os.Interrupt
+	select {
+	case <-h.walkCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the walk to be stopped")
+	}
+	if !h.interrupted.Load() || h.ctx.Err() != nil {
+		t.Errorf("expected the evaluations to go on after the first interrupt")
+	}
+
+	// the second one aborts the scan
+	h.signals <- os.Interrupt
+	select {
+	case <-h.ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the scan to be aborted")
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// jvmLimitArgs constrain the memory and threads of evaluated JVMs, printing the properties needs little of both.
+// Old runtimes ignore the options they do not know instead of failing to start.
+var jvmLimitArgs = []string{
+	"-XX:+IgnoreUnrecognizedVMOptions",
+	"-Xms8m",
+	"-Xmx64m",
+	"-Xss512k",
+	"-XX:+UseSerialGC",
+	"-XX:TieredStopAtLevel=1",
+	"-XX:ReservedCodeCacheSize=16m",
+	"-XX:MaxMetaspaceSize=64m",
+	"-XX:CompressedClassSpaceSize=32m",
+}
+
+// isolationArgs keep evaluated JVMs from leaving files behind: no hsperfdata_<user> directory for the
+// performance counters and no attach listener files
+var isolationArgs = []string{
+	"-XX:-UsePerfData",
+	"-XX:+DisableAttachMechanism",
+}
+
+// localeArgs make the output of evaluated JVMs independent of the host locale: English messages and
+// UTF-8 encoded output, whatever the runtime or the operating system is configured with
+var localeArgs = []string{
+	"-Duser.language=en",
+	"-Duser.country=US",
+	"-Dfile.encoding=UTF-8",
+	"-Dsun.stdout.encoding=UTF-8",
+	"-Dsun.stderr.encoding=UTF-8",
+	"-Dstdout.encoding=UTF-8",
+	"-Dstderr.encoding=UTF-8",
+}
+
+// decodeOutput converts the output of a JVM to a string. Runtimes that ignore the encoding of localeArgs
+// write in the encoding of the host: UTF-16 with a byte order mark is decoded, other output that is not
+// valid UTF-8 is decoded as Latin-1, so property names and ASCII values can still be parsed.
+func decodeOutput(output []byte) string {
+	if utf8.Valid(output) {
+		return string(output)
+	}
+	if len(output) >= 2 && (output[0] == 0xff && output[1] == 0xfe || output[0] == 0xfe && output[1] == 0xff) {
+		units := make([]uint16, 0, len(output)/2-1)
+		for i := 2; i+1 < len(output); i += 2 {
+			if output[0] == 0xff {
+				units = append(units, uint16(output[i])|uint16(output[i+1])<<8)
+			} else {
+				units = append(units, uint16(output[i])<<8|uint16(output[i+1]))
+			}
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(output))
+	for i, b := range output {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// tempDirVars are the environment variables naming the temporary directory
+var tempDirVars = []string{"TMPDIR", "TMP", "TEMP"}
+
+// evaluationEnv returns the environment with the variables of overrides replaced
+func evaluationEnv(environ []string, overrides map[string]string) []string {
+	env := make([]string, 0, len(environ)+len(overrides))
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if _, ok := overrides[name]; !ok {
+			env = append(env, entry)
+		}
+	}
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, name+"="+overrides[name])
+	}
+	return env
+}
+
+// evaluationArgs make java print its properties, they are passed after all other arguments
+var evaluationArgs = []string{"-XshowSettings:properties", "-version"}
+
+// evalArgPrefixes are the options with a value that may be added to the evaluation, they set properties or size the JVM
+var evalArgPrefixes = []string{"-D", "-Xss", "-Xms", "-Xmx"}
+
+// evalArgOptions are the options without a value that may be added to the evaluation. -Xshare:dump is not listed,
+// it writes the class data archive of the runtime.
+var evalArgOptions = []string{"-Xint", "-Xshare:off", "-Xshare:auto", "-Xshare:on"}
+
+// evalXXOptions are the -XX options that may be added to the evaluation as -XX:+Name, -XX:-Name or -XX:Name=value.
+// They only tune the JVM: options writing files (ErrorFile, LogFile, HeapDumpPath, StartFlightRecording, ...),
+// reading them (Flags, VMOptionsFile, CompileCommandFile, ...) or running commands (OnError, ...) are not listed.
+var evalXXOptions = []string{
+	"ActiveProcessorCount", "CICompilerCount", "DisableAttachMechanism", "IgnoreUnrecognizedVMOptions",
+	"InitialRAMPercentage", "MaxDirectMemorySize", "MaxMetaspaceSize", "MaxRAM", "MaxRAMPercentage",
+	"MinRAMPercentage", "ReservedCodeCacheSize", "TieredCompilation", "TieredStopAtLevel", "UseCompressedClassPointers",
+	"UseCompressedOops", "UseContainerSupport", "UseG1GC", "UseLargePages", "UseParallelGC", "UsePerfData",
+	"UseSerialGC", "UseTransparentHugePages",
+}
+
+// validateEvalArg checks that an extra evaluation argument is a safe JVM option,
+// arguments that run code, like -jar, -javaagent or @argfiles, are rejected
+func validateEvalArg(arg string) error {
+	if name, ok := strings.CutPrefix(arg, "-XX:"); ok {
+		if value, found := strings.CutPrefix(name, "+"); found {
+			name = value
+		} else if value, found := strings.CutPrefix(name, "-"); found {
+			name = value
+		} else {
+			name, _, _ = strings.Cut(name, "=")
+		}
+		if !slices.Contains(evalXXOptions, name) {
+			return fmt.Errorf("evaluation argument '%s' is not allowed, supported -XX options are %s",
+				arg, strings.Join(evalXXOptions, ", "))
+		}
+		return nil
+	}
+	if slices.Contains(evalArgOptions, arg) {
+		return nil
+	}
+	for _, prefix := range evalArgPrefixes {
+		if strings.HasPrefix(arg, prefix) && len(arg) > len(prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("evaluation argument '%s' is not allowed, supported are %s, the listed -XX options and "+
+		"options starting with %s", arg, strings.Join(evalArgOptions, ", "), strings.Join(evalArgPrefixes, ", "))
+}
+
+// evalCommandArgs returns the arguments of an evaluation with the extra arguments before the evaluation arguments
+func evalCommandArgs(extra []string) []string {
+	return append(append([]string{}, extra...), evaluationArgs...)
+}
+
+// processLimits are the operating system limits of an evaluation process
+type processLimits struct {
+	cpuSeconds  uint64
+	memoryBytes uint64
+}
+
+// evaluationLimits apply to every evaluated java, a misbehaving runtime cannot exhaust the scanned host
+var evaluationLimits = processLimits{cpuSeconds: 30, memoryBytes: 1 << 30}
+
+// defaultEvaluationTimeout is the wall clock time after which a hanging evaluation is killed, unless set with
+// --eval-timeout
+const defaultEvaluationTimeout = 15 * time.Second
+
+// validateEvalTimeout checks the --eval-timeout of the scan and the eval subcommand
+func validateEvalTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("--eval-timeout must be a positive duration")
+	}
+	return nil
+}
+
+// pipeWaitDelay is the time to wait for the standard error pipe after java exited,
+// children of java may inherit the pipe and keep it open
+const pipeWaitDelay = time.Second
+
+// retryDelays are the waits before the retries of an evaluation that failed with a transient error
+var retryDelays = []time.Duration{250 * time.Millisecond, time.Second}
+
+// transientOutputs are messages of JVMs that failed to start for lack of resources on a busy host
+var transientOutputs = []string{
+	"Resource temporarily unavailable",
+	"pthread_create failed (EAGAIN)",
+	"Text file busy",
+}
+
+// isTransient reports whether an evaluation failed for a reason that may be gone on the next attempt:
+// the executable is being written or scanned by an anti-virus, or the host is out of processes or threads
+func isTransient(stderr string, err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, transient := range transientStartErrors {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	for _, output := range transientOutputs {
+		if strings.Contains(stderr, output) {
+			return true
+		}
+	}
+	return false
+}
+
+// runJavaRetrying runs java like runJava, evaluations that failed with a transient error are retried after
+// the retryDelays
+func runJavaRetrying(ctx context.Context, javaPath string, args []string, timeout time.Duration) (string, error) {
+	stderr, err := runJava(ctx, javaPath, args, timeout)
+	for _, delay := range retryDelays {
+		if !isTransient(stderr, err) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return stderr, err
+		case <-time.After(delay):
+		}
+		stderr, err = runJava(ctx, javaPath, args, timeout)
+	}
+	return stderr, err
+}
+
+// runJava runs a java executable with the evaluation arguments under the evaluation limits and kills it after
+// timeout, it returns the standard error output and the exit error.
+// java runs in its own process group, which is killed on timeout, on cancellation of ctx and after java exited,
+// so neither java nor any process it started outlives the evaluation.
+func runJava(ctx context.Context, javaPath string, args []string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// files the JVM creates go to a private temporary directory, removed after the process group is gone
+	tmpDir, err := os.MkdirTemp("", "jfind-eval-")
+	if err != nil {
+		return "", fmt.Errorf("creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cmdArgs := append(append([]string{}, jvmLimitArgs...), isolationArgs...)
+	cmdArgs = append(cmdArgs, localeArgs...)
+	cmdArgs = append(cmdArgs, "-Djava.io.tmpdir="+tmpDir)
+	cmd := exec.Command(javaPath, append(cmdArgs, args...)...)
+	overrides := make(map[string]string)
+	for _, name := range tempDirVars {
+		overrides[name] = tmpDir
+	}
+	cmd.Env = evaluationEnv(os.Environ(), overrides)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = pipeWaitDelay
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	// limits are applied right after the start, the JVM has barely initialized at that point. On Windows java starts
+	// suspended and runs once it is in its Job Object.
+	group := newProcessGroup(cmd, evaluationLimits)
+	defer group.close()
+	stop := context.AfterFunc(ctx, group.kill)
+	err = cmd.Wait()
+	stop()
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("timed out after %s", timeout)
+	case ctx.Err() != nil:
+		err = ctx.Err()
+	case errors.Is(err, exec.ErrWaitDelay):
+		// java succeeded, only a child it left behind held the pipe
+		err = nil
+	}
+	return decodeOutput(stderr.Bytes()), err
+}
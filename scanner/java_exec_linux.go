@@ -0,0 +1,17 @@
+//go:build linux
+
+package main
+
+import (
+	"os/exec"
+
+	"golang.org/x/sys/unix"
+)
+
+// limitProcess sets the CPU time and data segment limits of a started process with prlimit(2).
+// RLIMIT_DATA only counts committed memory, the large address space reservations of the JVM are not affected.
+func limitProcess(cmd *exec.Cmd, limits processLimits) {
+	pid := cmd.Process.Pid
+	_ = unix.Prlimit(pid, unix.RLIMIT_CPU, &unix.Rlimit{Cur: limits.cpuSeconds, Max: limits.cpuSeconds}, nil)
+	_ = unix.Prlimit(pid, unix.RLIMIT_DATA, &unix.Rlimit{Cur: limits.memoryBytes, Max: limits.memoryBytes}, nil)
+}
@@ -0,0 +1,9 @@
+//go:build unix && !linux
+
+package main
+
+import "os/exec"
+
+// limitProcess does not limit the process, limits of a running process cannot be changed on this platform,
+// the JVM options of jvmLimitArgs still constrain its memory
+func limitProcess(cmd *exec.Cmd, limits processLimits) {}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunJavaLimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as java")
+	}
+
+	java := filepath.Join(t.TempDir(), "java")
+	// the limits are set right after the start, give them time to apply before reading them
+	script := "#!/bin/sh\necho \"$@\" >&2\nsleep 0.3\ncat /proc/$$/limits >&2 2>/dev/null\nexit 0\n"
+	if err := os.WriteFile(java, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	stderr, err := runJava(context.Background(), java, []string{"-XshowSettings:properties", "-version"}, defaultEvaluationTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, limits, _ := strings.Cut(stderr, "\n")
+	if !strings.HasPrefix(args, "-XX:+IgnoreUnrecognizedVMOptions ") || !strings.Contains(args, "-Xmx64m") ||
+		!strings.HasSuffix(args, "-XshowSettings:properties -version") {
+		t.Errorf("unexpected arguments %q", args)
+	}
+	if runtime.GOOS == "linux" && !strings.Contains(limits, "Max cpu time              30") {
+		t.Errorf("expected CPU time limit, got %s", limits)
+	}
+}
+
+// writeChildScript writes a java script that starts a child, writes its PID to pidFile and runs body
+func writeChildScript(t *testing.T, pidFile, body string) string {
+	java := filepath.Join(t.TempDir(), "java")
+	script := "#!/bin/sh\nsleep 30 &\necho $! > " + pidFile + "\n" + body + "\n"
+	if err := os.WriteFile(java, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return java
+}
+
+// waitGone waits until the process with the PID written to pidFile has terminated
+func waitGone(t *testing.T, pidFile string) {
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := strings.TrimSpace(string(data))
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		stat, err := os.ReadFile("/proc/" + pid + "/stat")
+		// terminated children of java may wait as zombies for the adopting process to reap them
+		if err != nil || strings.Contains(string(stat), ") Z ") {
+			return
+		}
+	}
+	t.Errorf("child process %s of java outlived the evaluation", pid)
+}
+
+func TestRunJavaKillsProcessGroup(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("checks processes in /proc")
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		pidFile := filepath.Join(t.TempDir(), "pid")
+		_, err := runJava(context.Background(), writeChildScript(t, pidFile, "sleep 30"), nil, 200*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "timed out after 200ms") {
+			t.Errorf("expected timeout error, got %v", err)
+		}
+		waitGone(t, pidFile)
+
+		// the hanging runtime is reported as failed with the reason
+		finder := NewJavaFinder("", -1, true)
+		finder.evalTimeout = 200 * time.Millisecond
+		result := finder.evaluateJava(writeChildScript(t, pidFile, "sleep 30"))
+		if reported := createRuntimeJSON(&result, true); !reported.ExecFailed || reported.ExecError != "timed out after 200ms" {
+			t.Errorf("expected a failed runtime with the timeout, got %v %q", reported.ExecFailed, reported.ExecError)
+		}
+		waitGone(t, pidFile)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(200*time.Millisecond, cancel)
+
+		pidFile := filepath.Join(t.TempDir(), "pid")
+		_, err := runJava(ctx, writeChildScript(t, pidFile, "sleep 30"), nil, defaultEvaluationTimeout)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected cancellation, got %v", err)
+		}
+		waitGone(t, pidFile)
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		pidFile := filepath.Join(t.TempDir(), "pid")
+		start := time.Now()
+		stderr, err := runJava(context.Background(), writeChildScript(t, pidFile, "echo done >&2\nexit 0"), nil, defaultEvaluationTimeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stderr != "done\n" {
+			t.Errorf("unexpected output %q", stderr)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("child holding the output pipe delayed the evaluation by %s", elapsed)
+		}
+		waitGone(t, pidFile)
+	})
+}
+
+func TestValidateEvalArg(t *testing.T) {
+	for _, arg := range []string{"-XX:-UsePerfData", "-XX:MaxRAMPercentage=25", "-Djava.awt.headless=true",
+		"-Xshare:off", "-Xint", "-Xss1m"} {
+		if err := validateEvalArg(arg); err != nil {
+			t.Errorf("expected %s to be allowed: %v", arg, err)
+		}
+	}
+	for _, arg := range []string{"-jar", "-javaagent:agent.jar", "@args", "-cp", "-D", "-XX:VMOptionsFile=/tmp/opts",
+		"-XX:OnError=sh -c id", "-version", "-Xintx", "-XX:ErrorFile=/etc/cron.d/x", "-XX:LogFile=/tmp/log",
+		"-XX:HeapDumpPath=/tmp/dump", "-XX:StartFlightRecording=filename=/tmp/rec.jfr", "-XX:CompileCommandFile=/tmp/c",
+		"-XX:+UnlockDiagnosticVMOptions", "-XX:", "-XX:+", "-Xshare:dump"} {
+		if err := validateEvalArg(arg); err == nil {
+			t.Errorf("expected %s to be rejected", arg)
+		}
+	}
+}
+
+func TestEvalCommandArgs(t *testing.T) {
+	args := evalCommandArgs([]string{"-XX:-UsePerfData"})
+	if got := strings.Join(args, " "); got != "-XX:-UsePerfData -XshowSettings:properties -version" {
+		t.Errorf("unexpected arguments %s", got)
+	}
+	if got := evalCommandArgs(nil); len(got) != 2 {
+		t.Errorf("unexpected default arguments %v", got)
+	}
+}
+
+func TestRunJavaPrivateTempDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as java")
+	}
+
+	java := filepath.Join(t.TempDir(), "java")
+	script := "#!/bin/sh\necho \"$@\" >&2\necho \"$TMPDIR\" >&2\ntouch \"$TMPDIR/hsperfdata\"\n"
+	if err := os.WriteFile(java, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	stderr, err := runJava(context.Background(), java, evaluationArgs, defaultEvaluationTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, tmpDir, _ := strings.Cut(strings.TrimSpace(stderr), "\n")
+	if !strings.Contains(args, "-XX:-UsePerfData -XX:+DisableAttachMechanism ") ||
+		!strings.Contains(args, " -Djava.io.tmpdir="+tmpDir+" ") ||
+		!strings.Contains(args, " -Duser.language=en ") || !strings.Contains(args, " -Dfile.encoding=UTF-8 ") {
+		t.Errorf("unexpected arguments %q", args)
+	}
+	if !strings.Contains(filepath.Base(tmpDir), "jfind-eval-") {
+		t.Errorf("expected a private temporary directory, got %q", tmpDir)
+	}
+	if _, err := os.Stat(tmpDir); !os.IsNotExist(err) {
+		t.Errorf("temporary directory %s not removed: %v", tmpDir, err)
+	}
+}
+
+func TestEvaluationEnv(t *testing.T) {
+	env := evaluationEnv([]string{"PATH=/bin", "TMPDIR=/tmp", "HOME=/root"}, map[string]string{"TMPDIR": "/x", "TEMP": "/x"})
+	if got := strings.Join(env, " "); got != "PATH=/bin HOME=/root TEMP=/x TMPDIR=/x" {
+		t.Errorf("unexpected environment %s", got)
+	}
+}
+
+func TestDecodeOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output []byte
+		want   string
+	}{
+		{"utf-8", []byte("user.dir = /home/jürgen\n"), "user.dir = /home/jürgen\n"},
+		{"latin-1", []byte("user.dir = /home/j\xfcrgen\n"), "user.dir = /home/jürgen\n"},
+		{"utf-16le", []byte("\xff\xfej\x00a\x00v\x00a\x00"), "java"},
+		{"utf-16be", []byte("\xfe\xff\x00j\x00a\x00v\x00a"), "java"},
+		// GBK encoded 中文 can not be decoded, but stays valid UTF-8 and parseable
+		{"gbk", []byte("java.version = 17\nuser.dir = \xd6\xd0\xce\xc4\n"), "java.version = 17\nuser.dir = ÖÐÎÄ\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeOutput(tt.output); got != tt.want {
+				t.Errorf("decodeOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	props := ParseJavaProperties(decodeOutput([]byte("    java.version = 17.0.13\n    user.dir = \xd6\xd0\xce\xc4\n")))
+	if props.Version != "17.0.13" || props.Major != 17 {
+		t.Errorf("unexpected properties %+v", props)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	exitErr := exec.Command("sh", "-c", "exit 1").Run()
+	tests := []struct {
+		name   string
+		stderr string
+		err    error
+		want   bool
+	}{
+		{"success", "", nil, false},
+		{"text file busy", "", &os.PathError{Op: "fork/exec", Path: "/opt/jdk/bin/java", Err: syscall.ETXTBSY}, true},
+		{"eagain", "", &os.PathError{Op: "fork/exec", Path: "/opt/jdk/bin/java", Err: syscall.EAGAIN}, true},
+		{"not found", "", &os.PathError{Op: "fork/exec", Path: "/opt/jdk/bin/java", Err: syscall.ENOENT}, false},
+		{"thread limit", "[warning][os,thread] Failed to start thread - pthread_create failed (EAGAIN)", exitErr, true},
+		{"vm error", "Error: Could not create the Java Virtual Machine.", exitErr, false},
+		{"timeout", "Resource temporarily unavailable", errors.New("timed out after 2m0s"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.stderr, tt.err); got != tt.want {
+				t.Errorf("isTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunJavaRetrying(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as java")
+	}
+	defer func(delays []time.Duration) { retryDelays = delays }(retryDelays)
+	retryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+
+	dir := t.TempDir()
+	java := filepath.Join(dir, "java")
+	// fails with a transient error on the first two attempts
+	script := "#!/bin/sh\necho x >> " + filepath.Join(dir, "attempts") + "\n" +
+		"if [ $(wc -l < " + filepath.Join(dir, "attempts") + ") -lt 3 ]; then\n" +
+		"  echo 'Error occurred during initialization of VM: Resource temporarily unavailable' >&2\n  exit 1\nfi\n" +
+		"echo 'java.version = 17.0.13' >&2\n"
+	if err := os.WriteFile(java, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	stderr, err := runJavaRetrying(context.Background(), java, evaluationArgs, defaultEvaluationTimeout)
+	if err != nil || !strings.Contains(stderr, "java.version") {
+		t.Errorf("expected success on the third attempt, got %v: %s", err, stderr)
+	}
+
+	retryDelays = retryDelays[:1]
+	_ = os.Remove(filepath.Join(dir, "attempts"))
+	if _, err := runJavaRetrying(context.Background(), java, evaluationArgs, defaultEvaluationTimeout); err == nil {
+		t.Errorf("expected failure after one retry")
+	}
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// transientStartErrors are start errors of java worth a retry: the executable is being written, or the
+// process limit of the user is reached
+var transientStartErrors = []error{syscall.ETXTBSY, syscall.EAGAIN}
+
+// processGroup is the process group of an evaluated java, led by the java process
+type processGroup struct {
+	pgid int
+}
+
+// setProcessGroup makes the command the leader of a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// newProcessGroup applies the limits to a started process and returns its process group
+func newProcessGroup(cmd *exec.Cmd, limits processLimits) *processGroup {
+	limitProcess(cmd, limits)
+	return &processGroup{pgid: cmd.Process.Pid}
+}
+
+// kill sends SIGKILL to all processes of the group
+func (g *processGroup) kill() {
+	_ = syscall.Kill(-g.pgid, syscall.SIGKILL)
+}
+
+// close kills the processes left in the group
+func (g *processGroup) close() {
+	g.kill()
+}
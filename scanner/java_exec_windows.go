@@ -0,0 +1,116 @@
+//go:build windows
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// transientStartErrors are start errors of java worth a retry: the executable is opened by an anti-virus
+// scanner or an installer. Access denied is not retried, it is a permanent refusal in most cases.
+var transientStartErrors = []error{windows.ERROR_SHARING_VIOLATION, windows.ERROR_LOCK_VIOLATION}
+
+// processGroup is the Job Object of an evaluated java, processes started by java join the job
+type processGroup struct {
+	cmd *exec.Cmd
+	job windows.Handle
+}
+
+// setProcessGroup starts the command in a new process group, console interrupts of jfind do not reach it. The
+// process starts suspended, it is resumed by newProcessGroup once it is in the job.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.CREATE_SUSPENDED}
+}
+
+// newProcessGroup assigns a started process to a Job Object limiting its memory and CPU time and resumes it, the
+// processes it starts cannot escape the job. Without a job only the process itself can be killed.
+func newProcessGroup(cmd *exec.Cmd, limits processLimits) *processGroup {
+	group := assignJob(cmd, limits)
+	if err := resumeProcess(uint32(cmd.Process.Pid)); err != nil {
+		// a process left suspended would only end with the evaluation timeout
+		group.kill()
+	}
+	return group
+}
+
+// assignJob assigns a started process to a new Job Object limiting its memory and CPU time
+func assignJob(cmd *exec.Cmd, limits processLimits) *processGroup {
+	group := &processGroup{cmd: cmd}
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return group
+	}
+
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			// CPU time in 100 ns units
+			PerProcessUserTimeLimit: int64(limits.cpuSeconds) * 10_000_000,
+			LimitFlags: windows.JOB_OBJECT_LIMIT_PROCESS_MEMORY | windows.JOB_OBJECT_LIMIT_PROCESS_TIME |
+				windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+		ProcessMemoryLimit: uintptr(limits.memoryBytes),
+	}
+	_, err = windows.SetInformationJobObject(job, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)))
+	if err == nil {
+		if process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(cmd.Process.Pid)); err == nil {
+			if windows.AssignProcessToJobObject(job, process) == nil {
+				group.job = job
+			}
+			_ = windows.CloseHandle(process)
+		}
+	}
+	if group.job == 0 {
+		_ = windows.CloseHandle(job)
+	}
+	return group
+}
+
+// resumeProcess resumes the threads of a process started suspended
+func resumeProcess(pid uint32) error {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPTHREAD, 0)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(snapshot)
+
+	entry := windows.ThreadEntry32{Size: uint32(unsafe.Sizeof(windows.ThreadEntry32{}))}
+	for err = windows.Thread32First(snapshot, &entry); err == nil; err = windows.Thread32Next(snapshot, &entry) {
+		if entry.OwnerProcessID != pid {
+			continue
+		}
+		thread, err := windows.OpenThread(windows.THREAD_SUSPEND_RESUME, false, entry.ThreadID)
+		if err != nil {
+			return err
+		}
+		_, err = windows.ResumeThread(thread)
+		_ = windows.CloseHandle(thread)
+		if err != nil {
+			return err
+		}
+	}
+	if err != windows.ERROR_NO_MORE_FILES {
+		return err
+	}
+	return nil
+}
+
+// kill terminates all processes of the job
+func (g *processGroup) kill() {
+	if g.job == 0 {
+		_ = g.cmd.Process.Kill()
+		return
+	}
+	_ = windows.TerminateJobObject(g.job, 1)
+}
+
+// close closes the job, which kills the processes left in it
+func (g *processGroup) close() {
+	if g.job != 0 {
+		_ = windows.CloseHandle(g.job)
+	}
+}
@@ -1,16 +1,18 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
-
-	"github.com/dustin/go-humanize"
 )
 
 // JavaFinder represents a finder for Java executables
@@ -18,19 +20,72 @@ type JavaFinder struct {
 	startPath string
 	maxDepth  int
 	evaluate  bool
-	scanned   atomic.Int64
-	found     atomic.Int64
-	ticker    atomic.Bool
-	done      chan struct{}
+	// evalArgs are added to the arguments of evaluated java executables
+	evalArgs []string
+	// evalTimeout is the time after which a hanging evaluation is killed
+	evalTimeout time.Duration
+	// ctx cancels the scan, the walk stops and running evaluations are killed
+	ctx context.Context
+	// walkCtx ends the walk early (--max-duration, interrupt), the java executables found so far are still evaluated
+	// and the result is marked as truncated
+	walkCtx   context.Context
+	truncated bool
+	// inspectCacerts enables the trust store inspection of evaluated runtimes
+	inspectCacerts bool
+	// inspectSecurity enables the java.security inspection of evaluated runtimes
+	inspectSecurity bool
+	// walkers and evaluators set the concurrency of the scan, the walk is sequential if both are at most 1
+	walkers    int
+	evaluators int
+	// quick evaluates only the java executables of the targeted detectors and skips the walk,
+	// the tool caches of homes are included
+	quick bool
+	homes []string
+	// index is queried for java executables before the walk, the walk is skipped with indexOnly
+	index     string
+	indexOnly bool
+	// skipMounts are the mount points of network file systems (or of all file systems with --one-file-system) not to
+	// walk, mapped to their type
+	skipMounts map[string]string
+	// walked holds the priority directories already walked, the following walks skip them
+	walked map[string]bool
+	// state is the walk state of incremental scans, unchanged directories are not read again
+	state *walkState
+	// excludes are the patterns of directories not to walk
+	excludes []string
+	// owners restricts the walk to directories of some accounts, nil walks all directories
+	owners *ownerScope
+	// findInstallers enables the search for JDK installers, found installers are collected in installers
+	findInstallers bool
+	// mu guards installers, suspected and applications, which are collected by the walkers
+	mu           sync.Mutex
+	installers   []InstallerArtifact
+	suspected    []SuspectedInstall
+	applications []JPackageApp
+	// errors records the paths skipped because of errors, it may be shared by the finders of several roots
+	errors *errorLog
+	// onResult is called with every java executable as soon as it is evaluated, concurrently by the evaluators
+	onResult func(result *JavaResult)
+	// indexed holds the paths evaluated before the walk, it is not modified during the walk
+	indexed map[string]bool
+	scanned atomic.Int64
+	found   atomic.Int64
+	failed  atomic.Int64
+	ticker  atomic.Bool
+	// current is the directory walked last and last the java found last, for the progress report
+	current atomic.Pointer[string]
+	last    atomic.Pointer[string]
 }
 
 // NewJavaFinder creates a new JavaFinder instance
 func NewJavaFinder(startPath string, maxDepth int, evaluate bool) *JavaFinder {
 	f := &JavaFinder{
-		startPath: startPath,
-		maxDepth:  maxDepth,
-		evaluate:  evaluate,
-		done:      make(chan struct{}),
+		startPath:   startPath,
+		maxDepth:    maxDepth,
+		evaluate:    evaluate,
+		evalTimeout: defaultEvaluationTimeout,
+		ctx:         context.Background(),
+		walkCtx:     context.Background(),
 	}
 	f.scanned.Store(0)
 	f.found.Store(0)
@@ -40,34 +95,61 @@ func NewJavaFinder(startPath string, maxDepth int, evaluate bool) *JavaFinder {
 
 // getPathDepth returns the depth of a path relative to the start path
 func (f *JavaFinder) getPathDepth(path string) int {
-	if !strings.HasPrefix(path, f.startPath) {
+	return pathDepth(f.startPath, path)
+}
+
+// pathDepth returns the depth of a path relative to root, or -1 if path is not below root. Paths are compared by
+// their components, /opt/javaapps is not below /opt/java.
+func pathDepth(root, path string) int {
+	if path == root {
+		return 0
+	}
+	prefix := root
+	if !strings.HasSuffix(prefix, string(os.PathSeparator)) {
+		prefix += string(os.PathSeparator)
+	}
+	if !strings.HasPrefix(path, prefix) {
 		return -1
 	}
 
-	relPath := strings.TrimPrefix(path, f.startPath)
+	relPath := strings.Trim(path[len(prefix):], string(os.PathSeparator))
 	if relPath == "" {
 		return 0
 	}
-
-	return len(strings.Split(strings.Trim(relPath, string(os.PathSeparator)), string(os.PathSeparator)))
+	return strings.Count(relPath, string(os.PathSeparator)) + 1
 }
 
 // evaluateJava runs java -version and returns the result
-func (f *JavaFinder) evaluateJava(javaPath string) JavaResult {
-	result := JavaResult{
-		Path: javaPath,
+func (f *JavaFinder) evaluateJava(javaPath string) (result JavaResult) {
+	if f.onResult != nil {
+		defer func() { f.onResult(&result) }()
 	}
 
+	// a link is evaluated and reported as its target, with the link path for attribution
+	var links []string
+	if target, ok := symlinkTarget(javaPath); ok {
+		links = []string{javaPath}
+		javaPath = target
+	}
+	result = JavaResult{
+		Path:     javaPath,
+		JavaHome: resolveJavaHome(javaPath),
+		Symlinks: links,
+	}
+	result.Edition = javaEdition(result.JavaHome)
+	result.VersionInfo, _ = readVersionInfo(javaPath)
+	result.Wine = inspectWineRuntime(javaPath, result.JavaHome)
+
 	if !f.evaluate {
 		return result
 	}
 
-	cmd := exec.Command(javaPath, "-XshowSettings:properties", "-version")
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	err := cmd.Run()
-	result.StdErr = stderr.String()
+	// Windows runtimes of a Wine prefix are not run, the version is read from the release file and VERSIONINFO
+	stderr, err := "", errWineHosted
+	if result.Wine == nil {
+		stderr, err = runJavaRetrying(f.ctx, javaPath, evalCommandArgs(f.evalArgs), f.evalTimeout)
+	}
+	result.StdErr = stderr
 	result.Error = err
 	result.ReturnCode = 0
 	if err != nil {
@@ -79,64 +161,82 @@ func (f *JavaFinder) evaluateJava(javaPath string) JavaResult {
 	if err == nil && result.ReturnCode == 0 {
 		result.Properties = ParseJavaProperties(result.StdErr)
 	}
+	result.Release = readReleaseFile(javaHomeOf(javaPath))
+	result.Arch = inspectBinaryArch(javaPath)
+	if f.inspectCacerts {
+		result.TrustStore = inspectTrustStore(javaHomeOf(javaPath), time.Now())
+	}
+	if f.inspectSecurity {
+		result.Security = inspectSecurityConfig(javaHomeOf(javaPath))
+	}
 
 	result.Evaluated = true
 	return result
 }
 
-// startProgressReporting starts a goroutine to report progress periodically
-func (f *JavaFinder) startProgressReporting() {
-	go func() {
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				f.ticker.Store(true)
-				scanned := f.scanned.Load()
-				found := f.found.Load()
-				// no linefeed, so progress report stay on same output line
-				logf("\rScanned %s directories, found %d java executables.", humanize.Comma(scanned), found)
-			case <-f.done:
-				return
-			}
-		}
-	}()
-}
-
-// evaluateFile checks if a file is a Java executable and evaluates it if required
-func (f *JavaFinder) evaluateFile(path string, info os.FileInfo) *JavaResult {
-	if info == nil {
+// evaluateFile checks if a file is a Java executable and evaluates it if required. Only entries named like a java
+// executable are stat'ed for their mode.
+func (f *JavaFinder) evaluateFile(path string, entry fs.DirEntry) *JavaResult {
+	if entry == nil || entry.IsDir() || !isJavaExecutable(entry.Name()) || f.indexed[path] {
 		return nil
 	}
-	if !info.IsDir() && isJavaExecutable(info.Name()) && isExecutable(info) {
-		if f.evaluate {
-			result := f.evaluateJava(path)
-			return &result
-		}
-		return &JavaResult{Path: path}
+	if info, err := entry.Info(); err == nil && isJavaFile(path, info) {
+		result := f.evaluateJava(path)
+		return &result
 	}
 	return nil
 }
 
+// enterDir counts a walked directory
+func (f *JavaFinder) enterDir(dir string) {
+	f.scanned.Add(1)
+	f.current.Store(&dir)
+}
+
+// foundJava counts a found java executable
+func (f *JavaFinder) foundJava(path string) {
+	f.found.Add(1)
+	f.last.Store(&path)
+}
+
+// recordError counts a path of the root skipped because of an error and records it in the error log
+func (f *JavaFinder) recordError(path string, err error) {
+	f.failed.Add(1)
+	f.errors.record(path, err)
+}
+
 // handleDirectory processes a directory during the walk
-func (f *JavaFinder) handleDirectory(path string, info os.FileInfo, err error) error {
+func (f *JavaFinder) handleDirectory(path string, entry fs.DirEntry, err error) error {
+	if err := f.walkCtx.Err(); err != nil {
+		return err
+	}
+	// unreadable paths are recorded and skipped like in readDir, they do not end the walk
 	if err != nil {
 		if os.IsPermission(err) {
 			if f.ticker.Load() {
-				logf("\n")
+				fmt.Fprintln(os.Stderr)
 			}
-			logf("Permission denied: %s\n", path)
-			return filepath.SkipDir
+			slog.Info("Permission denied", "path", path)
 		}
-		return err
+		f.recordError(path, err)
+		return filepath.SkipDir
 	}
 
 	// Skip .git directories
-	if info.IsDir() && info.Name() == ".git" {
+	if entry.IsDir() && entry.Name() == ".git" {
 		return filepath.SkipDir
 	}
+	if entry.IsDir() && (f.walked[path] || f.excluded(path)) {
+		return filepath.SkipDir
+	}
+	if entry.IsDir() && f.skipMount(path) {
+		return filepath.SkipDir
+	}
+	if entry.IsDir() && f.owners != nil {
+		if info, err := entry.Info(); err == nil && f.ownedByOthers(path, info) {
+			return filepath.SkipDir
+		}
+	}
 
 	// Check depth
 	if f.maxDepth >= 0 {
@@ -147,60 +247,236 @@ func (f *JavaFinder) handleDirectory(path string, info os.FileInfo, err error) e
 	}
 
 	// Update progress
-	if info.IsDir() {
-		f.scanned.Add(1)
+	if entry.IsDir() {
+		f.enterDir(path)
 	}
 
 	return nil
 }
 
+// skipMount reports whether dir is a network mount point excluded from the walk
+func (f *JavaFinder) skipMount(dir string) bool {
+	fsType, ok := f.skipMounts[dir]
+	if ok {
+		if f.ticker.Load() {
+			fmt.Fprintln(os.Stderr)
+		}
+		kind := "mount point"
+		if networkFileSystems[fsType] {
+			kind = "network file system"
+		}
+		slog.Info("Skipping "+kind, "path", dir, "type", fsType)
+	}
+	return ok
+}
+
+// inSkippedMount reports whether path is located in a mount excluded from the walk
+func (f *JavaFinder) inSkippedMount(path string) bool {
+	for dir := range f.skipMounts {
+		if isBelow(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
 // Find searches for java executables starting from the specified path
 func (f *JavaFinder) Find() ([]*JavaResult, error) {
 	results := make([]*JavaResult, 0)
 
-	f.startProgressReporting()
-	defer close(f.done)
+	known, err := f.findKnown()
+	if err != nil && f.indexOnly {
+		return nil, err
+	}
+	if err != nil {
+		slog.Warn("continuing with the file system walk", "error", err)
+	}
+	if f.indexOnly || f.quick {
+		return known, nil
+	}
+	results = append(results, known...)
+
+	// likely locations are walked first, then the rest of the tree
+	priority := f.priorityDirs()
+	roots := append(priority, f.startPath)
+	f.walked = make(map[string]bool)
 
-	err := filepath.Walk(f.startPath, func(path string, info os.FileInfo, err error) error {
-		if err := f.handleDirectory(path, info, err); err != nil {
-			return err
-		}
+	// incremental scans need the directory reads of the parallel walk
+	if f.walkers > 1 || f.evaluators > 1 || f.state != nil {
+		walked, err := f.findParallel(roots)
+		return sortedByPath(append(results, walked...)), f.endedEarly(err)
+	}
 
-		if result := f.evaluateFile(path, info); result != nil {
-			f.found.Add(1)
-			results = append(results, result)
+	for _, root := range roots {
+		err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err := f.handleDirectory(path, entry, err); err != nil {
+				return err
+			}
+
+			if result := f.evaluateFile(path, entry); result != nil {
+				f.foundJava(path)
+				results = append(results, result)
+			} else if entry.IsDir() {
+				f.checkSuspected(path)
+				f.checkApplication(path)
+			} else if f.findInstallers {
+				f.checkInstaller(path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			break
 		}
+		f.walked[root] = true
+	}
+
+	if len(known) > 0 || len(priority) > 0 {
+		results = sortedByPath(results)
+	}
+	return results, f.endedEarly(err)
+}
 
+// endedEarly marks the result as truncated if the walk failed with err because walkCtx ended it, the walk did not fail
+func (f *JavaFinder) endedEarly(err error) error {
+	if err != nil && f.walkCtx.Err() != nil && f.ctx.Err() == nil {
+		f.truncated = true
 		return nil
-	})
+	}
+	return err
+}
+
+// lastScannedPath returns the directory walked last
+func (f *JavaFinder) lastScannedPath() string {
+	if path := f.current.Load(); path != nil {
+		return *path
+	}
+	return ""
+}
 
-	return results, err
+// sortedByPath sorts results by the path of the java executable
+func sortedByPath(results []*JavaResult) []*JavaResult {
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	return results
 }
 
 // createRuntimeJSON creates a JavaRuntimeJSON from a JavaResult
 func createRuntimeJSON(result *JavaResult, evaluate bool) JavaRuntimeJSON {
 	runtime := JavaRuntimeJSON{
 		JavaExecutable: result.Path,
+		Symlinks:       result.Symlinks,
+		Aliases:        result.aliases(),
+		JavaHome:       result.JavaHome,
+		Edition:        result.Edition,
 	}
 
 	if evaluate && result.Properties != nil && result.Error == nil && result.ReturnCode == 0 {
 		runtime.JavaVersion = result.Properties.Version
 		runtime.JavaVendor = result.Properties.Vendor
 		runtime.JavaRuntime = result.Properties.RuntimeName
+		runtime.Distribution = normalizeDistribution(result.Properties.Vendor, result.Properties.RuntimeName)
+		runtime.JavaVMName = result.Properties.VMName
+		runtime.VMImplementation = vmImplementation(result.Properties.VMName)
 		runtime.IsOracle = strings.Contains(result.Properties.Vendor, "Oracle")
 		runtime.VersionMajor = result.Properties.Major
 		runtime.VersionUpdate = result.Properties.Update
+		runtime.JavaVersionDate = result.Properties.VersionDate
 		runtime.checkLicenseRequirement()
+		runtime.applyCommercialFeatures(result.CommercialFeatures)
+		runtime.VersionMismatch = versionInfoMismatch(result.VersionInfo, result.Properties)
 	} else if evaluate && (result.Error != nil || result.ReturnCode != 0) {
-		runtime.ExecFailed = true
+		// a runtime of a Wine prefix is not run, it did not fail
+		runtime.ExecFailed = result.Error != errWineHosted
+		runtime.ExecError = execErrorDetail(result)
+		runtime.applyReleaseFallback(result.Release)
+		runtime.applyVersionInfoFallback(result.VersionInfo)
+	}
+	runtime.VersionInfo = result.VersionInfo
+	if result.Wine != nil {
+		wine := *result.Wine
+		runtime.Wine = &wine
+	}
+
+	runtime.TrustStore = result.TrustStore
+	runtime.Kubernetes = result.Workload
+	if result.Arch != nil {
+		runtime.BinaryArch = result.Arch.Arches
+		runtime.IsUniversal = result.Arch.IsUniversal
+		runtime.RequiresRosetta = result.Arch.RequiresRosetta
+	}
+	runtime.Security = result.Security
+	runtime.DefaultForUser = result.DefaultPathEntry != ""
+	runtime.DefaultPathEntry = result.DefaultPathEntry
+	runtime.InstallSize = result.InstallSize
+	runtime.DuplicateOf = result.DuplicateOf
+	runtime.InstalledProgram = result.InstalledProgram
+	runtime.InstallProvenance = result.Program
+	runtime.DeploymentRisks = result.DeploymentRisks
+	if result.Application != nil {
+		application := *result.Application
+		runtime.Application = &application
+	}
+	runtime.Unregistered = result.Unregistered
+	runtime.ProfileUser = result.ProfileUser
+	runtime.BaselineDeviation = result.BaselineDeviation
+	runtime.PolicyViolations = result.PolicyViolations
+	runtime.ProcessIDs = result.ProcessIDs
+
+	if modules := result.Release.Modules(); modules != nil {
+		runtime.Modules = modules
+		runtime.ModuleCount = len(modules)
+		runtime.NotableModules = notableModules(modules)
+		runtime.IsCustomRuntime = isCustomRuntime(modules)
 	}
 
 	return runtime
 }
 
+// execErrorDetail describes why a java executable could not be run, with the first line of its output
+func execErrorDetail(result *JavaResult) string {
+	detail := ""
+	if result.Error != nil {
+		detail = result.Error.Error()
+	}
+	if line, _, _ := strings.Cut(strings.TrimSpace(result.StdErr), "\n"); line != "" {
+		detail += ": " + strings.TrimSpace(line)
+	}
+	return detail
+}
+
 // printResult prints the results of evaluating a Java executable
 func printResult(result *JavaResult, runtime *JavaRuntimeJSON) {
 	fmt.Printf("Java executable: %s\n", result.Path)
+	if len(result.Symlinks) > 0 {
+		fmt.Printf("Symbolic links: %s\n", strings.Join(result.Symlinks, ", "))
+	}
+	if len(result.HardLinks) > 0 {
+		fmt.Printf("Hard links: %s\n", strings.Join(result.HardLinks, ", "))
+	}
+	if w := result.Wine; w != nil {
+		fmt.Printf("Wine prefix: %s (%s)\n", w.Prefix, w.WindowsPath)
+		if w.RegistryKey != "" {
+			fmt.Printf("Wine registry: HKLM\\%s\n", w.RegistryKey)
+		}
+	}
+	if result.ProfileUser != "" {
+		fmt.Printf("User profile: %s\n", result.ProfileUser)
+	}
+	if result.BaselineDeviation != "" {
+		fmt.Printf("Warning: Baseline deviation (%s)\n", result.BaselineDeviation)
+	}
+	for _, violation := range result.PolicyViolations {
+		fmt.Printf("Warning: Policy violation, %s\n", strings.TrimSuffix(violation.Policy+": "+violation.Message, ": "))
+	}
+	if result.DefaultPathEntry != "" {
+		fmt.Printf("Info: Default java of the current user (selected by %s)\n", result.DefaultPathEntry)
+	}
+	if w := result.Workload; w != nil {
+		fmt.Printf("Kubernetes container: %s/%s/%s (%s)\n", w.Namespace, w.Pod, w.Container, w.Image)
+		if w.OwnerKind != "" {
+			fmt.Printf("Kubernetes owner: %s %s\n", w.OwnerKind, w.OwnerName)
+		}
+	}
 
 	if !result.Evaluated {
 		return
@@ -211,13 +487,54 @@ func printResult(result *JavaResult, runtime *JavaRuntimeJSON) {
 		if result.ReturnCode != 0 {
 			fmt.Printf("Exit code: %d\n", result.ReturnCode)
 		}
+		if runtime != nil && runtime.VersionSource == versionSourceRelease {
+			fmt.Printf("Java version (release file): %s\n", runtime.JavaVersion)
+			if runtime.JavaVendor != "" {
+				fmt.Printf("Java vendor (release file): %s\n", runtime.JavaVendor)
+			}
+			if runtime.RequireLicense != nil && *runtime.RequireLicense {
+				fmt.Printf("Warning: This Java runtime requires a commercial license\n")
+			}
+			if runtime.IsOracle && runtime.LicenseReason != "" {
+				fmt.Printf("License rationale: %s\n", runtime.LicenseReason)
+			}
+		}
 		return
 	}
 
+	if result.JavaHome != "" {
+		fmt.Printf("Java home: %s\n", result.JavaHome)
+	}
+	if result.DuplicateOf != "" {
+		fmt.Printf("Info: Identical to the installation in %s\n", result.DuplicateOf)
+	}
+	if result.InstalledProgram != "" {
+		fmt.Printf("Installed program: %s\n", result.InstalledProgram)
+		if p := result.Program; p != nil && p.Installer != "" {
+			fmt.Printf("Installed by: %s %s, publisher %s\n", strings.ToUpper(p.Installer), p.Version, p.Publisher)
+		}
+	} else if result.Unregistered {
+		fmt.Printf("Info: Not registered by an installer (copied or extracted installation)\n")
+	}
+	for _, risk := range result.DeploymentRisks {
+		fmt.Printf("Warning: Deployment setting risk, %s\n", risk)
+	}
+	if app := result.Application; app != nil {
+		fmt.Printf("Bundled with application: %s (jpackage, %s)\n", strings.TrimSpace(app.Name+" "+app.Version), app.Path)
+	}
+	if len(result.ProcessIDs) > 0 {
+		fmt.Printf("Running processes (PID): %s\n", strings.Trim(fmt.Sprint(result.ProcessIDs), "[]"))
+	}
 	if result.Properties != nil {
 		fmt.Printf("Java version: %s\n", result.Properties.Version)
 		fmt.Printf("Java vendor: %s\n", result.Properties.Vendor)
 		fmt.Printf("Java runtime name: %s\n", result.Properties.RuntimeName)
+		if distribution := normalizeDistribution(result.Properties.Vendor, result.Properties.RuntimeName); distribution != "" {
+			fmt.Printf("Java distribution: %s\n", distribution)
+		}
+		if result.Properties.VMName != "" {
+			fmt.Printf("Java VM: %s (%s)\n", result.Properties.VMName, vmImplementation(result.Properties.VMName))
+		}
 		if result.Properties.Major > 0 {
 			fmt.Printf("Java major version: %d\n", result.Properties.Major)
 		}
@@ -227,6 +544,40 @@ func printResult(result *JavaResult, runtime *JavaRuntimeJSON) {
 	}
 
 	if runtime != nil {
+		if info := runtime.VersionInfo; info != nil {
+			fmt.Printf("Version info: %s %s (%s)\n", info.ProductName, info.ProductVersion, info.CompanyName)
+		}
+		if runtime.VersionMismatch != "" {
+			fmt.Printf("Warning: Version info mismatch, %s\n", runtime.VersionMismatch)
+		}
+		if len(runtime.BinaryArch) > 0 {
+			if runtime.IsUniversal {
+				fmt.Printf("Binary architecture: %s (universal)\n", strings.Join(runtime.BinaryArch, ", "))
+			} else {
+				fmt.Printf("Binary architecture: %s\n", strings.Join(runtime.BinaryArch, ", "))
+			}
+			if runtime.RequiresRosetta {
+				fmt.Printf("Info: x86_64 only runtime, runs under Rosetta 2 on this Mac\n")
+			}
+		}
+
+		if runtime.ModuleCount > 0 {
+			fmt.Printf("Java modules: %d\n", runtime.ModuleCount)
+			if len(runtime.NotableModules) > 0 {
+				fmt.Printf("Notable modules: %s\n", strings.Join(runtime.NotableModules, ", "))
+			}
+			if runtime.IsCustomRuntime {
+				fmt.Printf("Info: Custom runtime image (no java.se module)\n")
+			}
+		}
+
+		if runtime.TrustStore != nil {
+			printTrustStore(runtime.TrustStore)
+		}
+		if runtime.Security != nil {
+			printSecurityConfig(runtime.Security)
+		}
+
 		if runtime.IsOracle {
 			fmt.Printf("Info: Oracle JDK/JRE detected\n")
 		}
@@ -235,6 +586,30 @@ func printResult(result *JavaResult, runtime *JavaRuntimeJSON) {
 			fmt.Printf("Warning: This Java runtime requires a commercial license\n")
 		} else {
 			fmt.Printf("This Java runtime does not require a commercial license\n")
+			if runtime.LicenseFreeUntil != "" {
+				fmt.Printf("Info: Free use under NFTC ends for updates released after %s\n", runtime.LicenseFreeUntil)
+			}
+		}
+		if runtime.IsOracle && runtime.LicenseReason != "" {
+			fmt.Printf("License rationale: %s\n", runtime.LicenseReason)
 		}
+
+		if len(runtime.Properties) > 0 {
+			printProperties(runtime.Properties)
+		}
+	}
+}
+
+// printProperties prints all java system properties sorted by name
+func printProperties(props map[string]string) {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Printf("Java properties:\n")
+	for _, key := range keys {
+		fmt.Printf("    %s = %s\n", key, props[key])
 	}
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindEndedEarly(t *testing.T) {
+	root := t.TempDir()
+	writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		// an exhausted time budget ends the walk without error, the result is truncated
+		walkCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.walkCtx = walkCtx
+		if _, err := finder.Find(); err != nil || !finder.truncated {
+			t.Errorf("threads %d: expected a truncated result without error, got %v", threads, err)
+		}
+		if stats := finder.rootStats(0, 0); !stats.Truncated {
+			t.Errorf("threads %d: expected truncated root stats", threads)
+		}
+
+		// a cancelled scan fails
+		finder = NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.ctx, finder.walkCtx = walkCtx, walkCtx
+		if _, err := finder.Find(); err == nil || finder.truncated {
+			t.Errorf("threads %d: expected the cancelled scan to fail, got %v", threads, err)
+		}
+	}
+}
+
+func TestWalkSkipsUnreadablePaths(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// errors other than permission errors are recorded and skipped as well, the walk goes on
+	finder := NewJavaFinder(root, -1, false)
+	ioErr := &fs.PathError{Op: "readdirent", Path: file, Err: fmt.Errorf("input/output error")}
+	if err := finder.handleDirectory(file, nil, ioErr); err != filepath.SkipDir || finder.failed.Load() != 1 {
+		t.Errorf("expected the path to be skipped and recorded, got %v and %d errors", err, finder.failed.Load())
+	}
+	if subdirs, err := finder.readDir(file, func(string) {}); err != nil || subdirs != nil || finder.failed.Load() != 2 {
+		t.Errorf("expected the directory to be skipped and recorded, got %v and %d errors", err, finder.failed.Load())
+	}
+}
+
+// benchmarkTree creates a tree of 20 x 25 directories with 20 files each and a JDK in every fifth directory,
+// roughly the entry density of an application server installation
+func benchmarkTree(b *testing.B) string {
+	b.Helper()
+	root := b.TempDir()
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 25; j++ {
+			dir := filepath.Join(root, fmt.Sprintf("app%d", i), fmt.Sprintf("lib%d", j))
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				b.Fatal(err)
+			}
+			for k := 0; k < 20; k++ {
+				if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("module%d.jar", k)), nil, 0o644); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if j%5 == 0 {
+				if err := os.MkdirAll(filepath.Join(dir, "bin"), 0o755); err != nil {
+					b.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "bin", "java"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	return root
+}
+
+func BenchmarkFind(b *testing.B) {
+	root := benchmarkTree(b)
+	for _, walkers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("walkers=%d", walkers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				finder := NewJavaFinder(root, -1, false)
+				finder.walkers, finder.evaluators = walkers, walkers
+				results, err := finder.Find()
+				if err != nil || len(results) != 100 {
+					b.Fatalf("expected 100 executables, got %d: %v", len(results), err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkWalk compares the traversal of filepath.Walk, which stats every entry, with filepath.WalkDir used by
+// the sequential scan
+func BenchmarkWalk(b *testing.B) {
+	root := benchmarkTree(b)
+	b.Run("Walk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() && isJavaExecutable(info.Name()) {
+					_ = isExecutable(info)
+				}
+				return err
+			})
+		}
+	})
+	b.Run("WalkDir", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+				if err == nil && !entry.IsDir() && isJavaExecutable(entry.Name()) {
+					if info, err := entry.Info(); err == nil {
+						_ = isExecutable(info)
+					}
+				}
+				return err
+			})
+		}
+	})
+}
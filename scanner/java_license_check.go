@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // checkOpenJDK checks if the runtime is OpenJDK
@@ -25,26 +26,32 @@ func (j *JavaRuntimeJSON) checkCommercialFeatures() bool {
 
 // checkVersionSpecificRules checks version-specific license requirements
 func (j *JavaRuntimeJSON) checkVersionSpecificRules() (bool, bool) {
-	switch j.VersionMajor {
-	case 7:
-		return true, j.VersionUpdate > 80
-	case 8:
-		return true, j.VersionUpdate > 202
-	case 11:
-		return true, true
-	case 17:
-		return true, j.VersionUpdate >= 13
+	rule, ok := oracleRuleFor(j.VersionMajor)
+	if !ok {
+		return false, false
 	}
 
-	// For versions 18-20 and 21+
-	if j.VersionMajor >= 18 && j.VersionMajor <= 20 {
-		return true, false
+	switch rule.license {
+	case licenseBCL:
+		return true, j.VersionUpdate > rule.lastFreeUpdate
+	case licenseOTN:
+		return true, true
 	}
-	if j.VersionMajor >= 21 {
+
+	// NFTC: free unless the update was released after the end of the window
+	if rule.freeUntil.IsZero() {
 		return true, false
 	}
+	j.LicenseFreeUntil = rule.freeUntil.Format(time.DateOnly)
+	return true, j.releaseDate().After(rule.freeUntil)
+}
 
-	return false, false
+// releaseDate returns the release date reported by the runtime, or an estimate based on the update number
+func (j *JavaRuntimeJSON) releaseDate() time.Time {
+	if date, err := time.Parse(time.DateOnly, j.JavaVersionDate); err == nil {
+		return date
+	}
+	return estimateUpdateDate(j.VersionMajor, j.VersionUpdate)
 }
 
 // checkLicenseRequirement determines if a commercial license is required for the Java runtime
@@ -54,29 +61,63 @@ func (j *JavaRuntimeJSON) checkLicenseRequirement() {
 	// Non-Oracle JDKs never require a license
 	if !j.IsOracle {
 		*j.RequireLicense = false
+		j.LicenseReason = "Not an Oracle JDK/JRE"
 		return
 	}
 
 	// OpenJDK never requires a license
 	if j.checkOpenJDK() {
 		*j.RequireLicense = false
+		j.LicenseReason = "Oracle OpenJDK build under GPLv2 with Classpath Exception"
 		return
 	}
 
 	// Check for commercial features
 	if j.checkCommercialFeatures() {
 		*j.RequireLicense = true
+		j.LicenseReason = "Runtime name indicates commercial features"
 		return
 	}
 
 	// Check version-specific rules
 	if hasRule, requiresLicense := j.checkVersionSpecificRules(); hasRule {
 		*j.RequireLicense = requiresLicense
+		j.LicenseReason = j.versionRuleReason(requiresLicense)
 		return
 	}
 
 	// Default case: require license for any other Oracle JDK version
 	*j.RequireLicense = true
+	j.LicenseReason = fmt.Sprintf("No free use rule for Oracle JDK %d", j.VersionMajor)
+}
+
+// versionRuleReason explains the verdict of the version specific rules
+func (j *JavaRuntimeJSON) versionRuleReason(requiresLicense bool) string {
+	rule, _ := oracleRuleFor(j.VersionMajor)
+
+	switch rule.license {
+	case licenseBCL:
+		if requiresLicense {
+			return fmt.Sprintf("Oracle JDK %d update %d is later than update %d, the last public update under the BCL",
+				j.VersionMajor, j.VersionUpdate, rule.lastFreeUpdate)
+		}
+		return fmt.Sprintf("Oracle JDK %d update %d is a public update under the BCL (free up to update %d)",
+			j.VersionMajor, j.VersionUpdate, rule.lastFreeUpdate)
+	case licenseOTN:
+		return fmt.Sprintf("Oracle JDK %d is licensed under the OTN license, production use requires a subscription", j.VersionMajor)
+	}
+
+	if rule.freeUntil.IsZero() {
+		return fmt.Sprintf("Oracle JDK %d is licensed under the NFTC", j.VersionMajor)
+	}
+	released := j.releaseDate().Format(time.DateOnly)
+	if j.JavaVersionDate == "" {
+		released += " (estimated)"
+	}
+	if requiresLicense {
+		return fmt.Sprintf("Released %s, after the NFTC free use window ended on %s", released, rule.freeUntil.Format(time.DateOnly))
+	}
+	return fmt.Sprintf("Released %s, within the NFTC free use window ending on %s", released, rule.freeUntil.Format(time.DateOnly))
 }
 
 // Must be aligned with the codified rules
@@ -87,10 +128,15 @@ func showRules() {
 	fmt.Println("- Oracle JDK 7: Free for updates <= 80, requires license for later versions")
 	fmt.Println("- Oracle JDK 8: Free for updates <= 202, requires license for later versions")
 	fmt.Println("- Oracle JDK 11: Always requires a commercial license")
-	fmt.Println("- Oracle JDK 17: Requires commercial license for version 17.0.13 and later")
-	fmt.Println("- Oracle JDK 18-20: No commercial license required")
-	fmt.Println("- Oracle JDK 21+: No commercial license required")
+	for _, major := range ltsReleasesSince(17, time.Now()) {
+		rule, _ := oracleRuleFor(major)
+		fmt.Printf("- Oracle JDK %d (LTS): Free under NFTC for updates released until %s, requires license for later updates (expected from %d.0.%d)\n",
+			major, rule.freeUntil.Format(time.DateOnly), major, firstLicensedUpdate(major))
+	}
+	fmt.Println("- Oracle JDK 18+ (non-LTS): No commercial license required")
 	fmt.Println("\nNotes:")
 	fmt.Println("- Non-Oracle JDKs never require a commercial license")
+	fmt.Println("- NFTC windows end one year after the GA of the next LTS release")
+	fmt.Println("- The release date is taken from java.version.date, or estimated from the update number")
 	fmt.Println("- Any Oracle JDK version not listed above requires a commercial license by default")
 }
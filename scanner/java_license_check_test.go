@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+func TestCheckLicenseRequirementOracle(t *testing.T) {
+	tests := []struct {
+		version     string
+		versionDate string
+		want        bool
+		freeUntil   string
+	}{
+		{"1.7.0_80", "", false, ""},
+		{"1.7.0_81", "", true, ""},
+		{"1.8.0_202", "", false, ""},
+		{"1.8.0_211", "", true, ""},
+		{"11.0.2", "2019-01-15", true, ""},
+		{"17.0.12", "2024-07-16", false, "2024-09-30"},
+		{"17.0.13", "2024-10-15", true, "2024-09-30"},
+		{"17.0.13", "", true, "2024-09-30"},
+		{"20.0.2", "2023-07-18", false, ""},
+		{"21.0.8", "2025-07-15", false, "2026-09-30"},
+		{"21.0.13", "", true, "2026-09-30"},
+		{"25", "2025-09-16", false, "2028-09-30"},
+		{"14.0.2", "", true, ""},
+	}
+
+	for _, tt := range tests {
+		runtime := JavaRuntimeJSON{
+			JavaRuntime:     "Java(TM) SE Runtime Environment",
+			JavaVersion:     tt.version,
+			JavaVersionDate: tt.versionDate,
+			IsOracle:        true,
+		}
+		runtime.VersionMajor, runtime.VersionUpdate = parseJavaVersion(tt.version)
+		runtime.checkLicenseRequirement()
+
+		if *runtime.RequireLicense != tt.want {
+			t.Errorf("%s (%s): expected require license %v, got %v", tt.version, tt.versionDate, tt.want, *runtime.RequireLicense)
+		}
+		if runtime.LicenseFreeUntil != tt.freeUntil {
+			t.Errorf("%s: expected free until %q, got %q", tt.version, tt.freeUntil, runtime.LicenseFreeUntil)
+		}
+	}
+}
+
+func TestCheckLicenseRequirementNonOracle(t *testing.T) {
+	runtime := JavaRuntimeJSON{
+		JavaRuntime:  "OpenJDK Runtime Environment",
+		JavaVendor:   "Eclipse Adoptium",
+		VersionMajor: 11,
+	}
+	runtime.checkLicenseRequirement()
+
+	if *runtime.RequireLicense {
+		t.Error("Expected non-Oracle runtime to not require a license")
+	}
+}
+
+func TestLicenseReason(t *testing.T) {
+	tests := []struct {
+		version     string
+		versionDate string
+		want        string
+	}{
+		{"1.8.0_211", "", "Oracle JDK 8 update 211 is later than update 202, the last public update under the BCL"},
+		{"1.8.0_202", "", "Oracle JDK 8 update 202 is a public update under the BCL (free up to update 202)"},
+		{"11.0.2", "2019-01-15", "Oracle JDK 11 is licensed under the OTN license, production use requires a subscription"},
+		{"17.0.13", "2024-10-15", "Released 2024-10-15, after the NFTC free use window ended on 2024-09-30"},
+		{"17.0.12", "2024-07-16", "Released 2024-07-16, within the NFTC free use window ending on 2024-09-30"},
+	}
+
+	for _, tt := range tests {
+		runtime := JavaRuntimeJSON{
+			JavaRuntime:     "Java(TM) SE Runtime Environment",
+			JavaVersion:     tt.version,
+			JavaVersionDate: tt.versionDate,
+			IsOracle:        true,
+		}
+		runtime.VersionMajor, runtime.VersionUpdate = parseJavaVersion(tt.version)
+		runtime.checkLicenseRequirement()
+
+		if runtime.LicenseReason != tt.want {
+			t.Errorf("%s: expected reason %q, got %q", tt.version, tt.want, runtime.LicenseReason)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -9,8 +10,10 @@ import (
 // JavaProperties represents properties parsed from java -version output
 type JavaProperties struct {
 	Version     string
+	VersionDate string
 	Vendor      string
 	RuntimeName string
+	VMName      string
 	Major       int
 	Update      int
 }
@@ -34,10 +37,14 @@ func ParseJavaProperties(input string) *JavaProperties {
 			switch key {
 			case "java.version":
 				props.Version = value
+			case "java.version.date":
+				props.VersionDate = value
 			case "java.vendor":
 				props.Vendor = value
 			case "java.runtime.name":
 				props.RuntimeName = value
+			case "java.vm.name":
+				props.VMName = value
 			}
 		}
 	}
@@ -50,6 +57,44 @@ func ParseJavaProperties(input string) *JavaProperties {
 	return props
 }
 
+// evaluationProperties are set by the evaluation itself (see runJava), their values are not the ones of the runtime
+var evaluationProperties = []string{"java.io.tmpdir"}
+
+// ParseAllJavaProperties returns all properties of the java -XshowSettings:properties output, except the
+// evaluationProperties. Values of list properties (e.g. java.library.path) continue on further indented lines, they
+// are joined with the path list separator.
+func ParseAllJavaProperties(input string) map[string]string {
+	props := make(map[string]string)
+	key := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		indent := len(raw) - len(strings.TrimLeft(raw, " \t"))
+		if k, value, ok := strings.Cut(line, " = "); ok && indent <= 4 {
+			key = strings.TrimSpace(k)
+			props[key] = strings.TrimSpace(value)
+			continue
+		}
+		if key != "" && indent > 4 {
+			props[key] += string(os.PathListSeparator) + line
+			continue
+		}
+		// "Property settings:" header and the version banner end the property block
+		key = ""
+	}
+
+	for _, name := range evaluationProperties {
+		delete(props, name)
+	}
+	return props
+}
+
 // parseJavaVersion extracts major and update versions from Java version string
 func parseJavaVersion(version string) (major, update int) {
 	// Handle pre-Java 9 versions (1.8.0_202)
@@ -75,3 +120,46 @@ func parseJavaVersion(version string) (major, update int) {
 	}
 	return
 }
+
+// readPropertiesFile reads a Java .properties file into a map.
+// Supports '=' and ':' separators, '#'/'!' comments and backslash line continuations.
+func readPropertiesFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseProperties(string(data)), nil
+}
+
+// parseProperties parses the content of a Java .properties file
+func parseProperties(content string) map[string]string {
+	props := make(map[string]string)
+
+	var logical strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if logical.Len() == 0 && (line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!")) {
+			continue
+		}
+
+		// an odd number of trailing backslashes continues the value on the next line
+		trailing := len(line) - len(strings.TrimRight(line, `\`))
+		if trailing%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+		line = logical.String()
+		logical.Reset()
+
+		idx := strings.IndexAny(line, "=:")
+		if idx == -1 {
+			props[line] = ""
+			continue
+		}
+		props[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+
+	return props
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"os"
 	"strings"
 	"testing"
 )
@@ -9,6 +10,7 @@ func TestParseJavaProperties(t *testing.T) {
 	input := `Property settings:
     java.version = 21.0.5
     java.vendor = Eclipse Adoptium
+    java.vm.name = OpenJDK 64-Bit Server VM
     other.property = some value
 `
 
@@ -21,6 +23,10 @@ func TestParseJavaProperties(t *testing.T) {
 	if props.Vendor != "Eclipse Adoptium" {
 		t.Errorf("Expected vendor Eclipse Adoptium, got %s", props.Vendor)
 	}
+
+	if props.VMName != "OpenJDK 64-Bit Server VM" {
+		t.Errorf("Expected VM name OpenJDK 64-Bit Server VM, got %s", props.VMName)
+	}
 }
 
 func TestParseJavaPropertiesWithOracleAndOpenJDK(t *testing.T) {
@@ -92,3 +98,69 @@ func TestEvaluateJava(t *testing.T) {
 		t.Error("Expected Oracle vendor")
 	}
 }
+
+func TestParseProperties(t *testing.T) {
+	input := `# usage tracker
+com.oracle.usagetracker.logToServer = amc.example.com:8080
+! legacy comment
+com.oracle.usagetracker.logToFile: /var/log/java/usage.log
+flag
+`
+	props := parseProperties(input)
+
+	if props["com.oracle.usagetracker.logToServer"] != "amc.example.com:8080" {
+		t.Errorf("Unexpected logToServer value %q", props["com.oracle.usagetracker.logToServer"])
+	}
+	if props["com.oracle.usagetracker.logToFile"] != "/var/log/java/usage.log" {
+		t.Errorf("Unexpected logToFile value %q", props["com.oracle.usagetracker.logToFile"])
+	}
+	if _, ok := props["flag"]; !ok {
+		t.Error("Expected key without value to be present")
+	}
+	if len(props) != 3 {
+		t.Errorf("Expected 3 properties, got %d", len(props))
+	}
+}
+
+func TestParsePropertiesContinuation(t *testing.T) {
+	input := `jdk.tls.disabledAlgorithms=SSLv3, TLSv1, \
+    TLSv1.1, RC4
+crypto.policy=unlimited
+`
+	props := parseProperties(input)
+
+	if props["jdk.tls.disabledAlgorithms"] != "SSLv3, TLSv1, TLSv1.1, RC4" {
+		t.Errorf("Unexpected continued value %q", props["jdk.tls.disabledAlgorithms"])
+	}
+	if props["crypto.policy"] != "unlimited" {
+		t.Errorf("Unexpected crypto.policy value %q", props["crypto.policy"])
+	}
+}
+
+func TestParseAllJavaProperties(t *testing.T) {
+	input := `Property settings:
+    java.home = /usr/lib/jvm/jdk-17
+    java.io.tmpdir = /tmp/jfind-eval-1234
+    java.library.path = /usr/java/packages/lib
+        /usr/lib64
+    line.separator = \n 
+    user.name = alice
+
+openjdk version "17.0.8" 2023-07-18
+`
+	props := ParseAllJavaProperties(input)
+
+	if len(props) != 4 {
+		t.Errorf("Expected 4 properties, got %d: %v", len(props), props)
+	}
+	if props["java.home"] != "/usr/lib/jvm/jdk-17" {
+		t.Errorf("Unexpected java.home %q", props["java.home"])
+	}
+	want := "/usr/java/packages/lib" + string(os.PathListSeparator) + "/usr/lib64"
+	if props["java.library.path"] != want {
+		t.Errorf("Expected java.library.path %q, got %q", want, props["java.library.path"])
+	}
+	if _, ok := props["java.io.tmpdir"]; ok {
+		t.Errorf("Expected the temporary directory of the evaluation to be dropped")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// notableModulePrefixes are modules worth reporting separately, entries ending with '.' match as prefix
+var notableModulePrefixes = []string{
+	"jdk.management.jfr",
+	"jdk.crypto.cryptoki",
+	"javafx.",
+}
+
+// ReleaseInfo holds the key/value pairs of the release file in a Java installation root
+type ReleaseInfo map[string]string
+
+// ParseReleaseFile parses the content of a release file (KEY="value" lines)
+func ParseReleaseFile(content string) ReleaseInfo {
+	info := make(ReleaseInfo)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if idx := strings.Index(line, "="); idx != -1 {
+			key := strings.TrimSpace(line[:idx])
+			value := strings.Trim(strings.TrimSpace(line[idx+1:]), `"`)
+			info[key] = value
+		}
+	}
+
+	return info
+}
+
+// readReleaseFile reads the release file of a Java installation, returns nil if there is none.
+// For JDK 8 the java executable of the embedded JRE is below jre/, the release file is one level up.
+func readReleaseFile(javaHome string) ReleaseInfo {
+	candidates := []string{filepath.Join(javaHome, "release")}
+	if strings.EqualFold(filepath.Base(javaHome), "jre") {
+		candidates = append(candidates, filepath.Join(filepath.Dir(javaHome), "release"))
+	}
+
+	for _, candidate := range candidates {
+		if data, err := os.ReadFile(candidate); err == nil {
+			return ParseReleaseFile(string(data))
+		}
+	}
+	return nil
+}
+
+// javaHomeOf returns the installation root of a java executable, the parent of its bin directory
+func javaHomeOf(javaPath string) string {
+	dir := filepath.Dir(javaPath)
+	if strings.EqualFold(filepath.Base(dir), "bin") {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
+
+// resolveJavaHome returns the installation root of a java executable as used for JAVA_HOME.
+// Links like /usr/bin/java are resolved first. On macOS the root of a bundle is its Contents/Home directory,
+// which is the parent of bin like on other platforms. The JRE embedded in a JDK 8 (jre/bin/java) resolves
+// to the JDK root.
+func resolveJavaHome(javaPath string) string {
+	if resolved, err := filepath.EvalSymlinks(javaPath); err == nil {
+		javaPath = resolved
+	}
+
+	home := javaHomeOf(javaPath)
+	if strings.EqualFold(filepath.Base(home), "jre") {
+		parent := filepath.Dir(home)
+		for _, javac := range []string{"javac", "javac.exe"} {
+			if _, err := os.Stat(filepath.Join(parent, "bin", javac)); err == nil {
+				return parent
+			}
+		}
+	}
+	return home
+}
+
+// javaEdition returns "jdk" for installations with a compiler and "jre" for runtime only installations
+func javaEdition(javaHome string) string {
+	if javaHome == "" {
+		return ""
+	}
+	for _, javac := range []string{"javac", "javac.exe"} {
+		if _, err := os.Stat(filepath.Join(javaHome, "bin", javac)); err == nil {
+			return "jdk"
+		}
+	}
+	return "jre"
+}
+
+// versionSourceRelease marks runtime details read from the release file instead of the running JVM
+const versionSourceRelease = "release-file"
+
+// applyReleaseFallback fills version and vendor from the release file when the java executable could not be
+// run, e.g. for a foreign architecture, missing shared libraries or runtimes only usable inside a chroot.
+// Oracle JDK builds are marked with BUILD_TYPE="commercial", JDK 8 release files have no IMPLEMENTOR.
+func (j *JavaRuntimeJSON) applyReleaseFallback(release ReleaseInfo) {
+	version := release["JAVA_VERSION"]
+	if version == "" {
+		return
+	}
+
+	j.VersionSource = versionSourceRelease
+	j.JavaVersion = version
+	j.JavaVersionDate = release["JAVA_VERSION_DATE"]
+	j.JavaVendor = release["IMPLEMENTOR"]
+	commercial := strings.EqualFold(release["BUILD_TYPE"], "commercial")
+	if j.JavaVendor == "" && commercial {
+		j.JavaVendor = "Oracle Corporation"
+	}
+	if strings.Contains(j.JavaVendor, "Oracle") {
+		if commercial {
+			j.JavaRuntime = oracleJDKRuntimeName
+		} else {
+			j.JavaRuntime = "OpenJDK Runtime Environment"
+		}
+	}
+
+	j.IsOracle = strings.Contains(j.JavaVendor, "Oracle")
+	j.VersionMajor, j.VersionUpdate = parseJavaVersion(version)
+	j.Distribution = normalizeDistribution(j.JavaVendor, j.JavaRuntime)
+	j.checkLicenseRequirement()
+}
+
+// Modules returns the sorted list of modules of a Java 9+ runtime, nil if not listed
+func (r ReleaseInfo) Modules() []string {
+	modules := strings.Fields(r["MODULES"])
+	if len(modules) == 0 {
+		return nil
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// notableModules returns the modules matching notableModulePrefixes
+func notableModules(modules []string) []string {
+	var notable []string
+	for _, module := range modules {
+		for _, prefix := range notableModulePrefixes {
+			if module == prefix || (strings.HasSuffix(prefix, ".") && strings.HasPrefix(module, prefix)) {
+				notable = append(notable, module)
+				break
+			}
+		}
+	}
+	return notable
+}
+
+// isCustomRuntime reports whether a module list belongs to a custom (jlink) runtime image.
+// Full JDK and JRE images include the java.se aggregator module, stripped images usually do not.
+func isCustomRuntime(modules []string) bool {
+	if len(modules) == 0 {
+		return false
+	}
+	for _, module := range modules {
+		if module == "java.se" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestParseReleaseFile(t *testing.T) {
+	input := `IMPLEMENTOR="Eclipse Adoptium"
+JAVA_VERSION="17.0.8.1"
+JAVA_VERSION_DATE="2023-08-24"
+MODULES="java.base java.logging jdk.crypto.cryptoki javafx.base jdk.management.jfr"
+`
+	release := ParseReleaseFile(input)
+
+	if release["IMPLEMENTOR"] != "Eclipse Adoptium" {
+		t.Errorf("Expected implementor Eclipse Adoptium, got %s", release["IMPLEMENTOR"])
+	}
+
+	modules := release.Modules()
+	if len(modules) != 5 {
+		t.Fatalf("Expected 5 modules, got %d", len(modules))
+	}
+
+	want := []string{"javafx.base", "jdk.crypto.cryptoki", "jdk.management.jfr"}
+	if got := notableModules(modules); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected notable modules %v, got %v", want, got)
+	}
+
+	if !isCustomRuntime(modules) {
+		t.Error("Expected runtime without java.se to be a custom runtime")
+	}
+	if isCustomRuntime(append(modules, "java.se")) {
+		t.Error("Expected runtime with java.se to be a full runtime")
+	}
+}
+
+func TestJavaHomeOf(t *testing.T) {
+	if home := javaHomeOf("/usr/lib/jvm/jdk-17/bin/java"); home != "/usr/lib/jvm/jdk-17" {
+		t.Errorf("Unexpected java home %s", home)
+	}
+}
+
+func TestResolveJavaHome(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses symlinks")
+	}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jdk8 := filepath.Join(root, "jdk1.8.0_202")
+	writeFakeJava(t, filepath.Join(jdk8, "jre", "bin"))
+	javac := filepath.Join(filepath.Dir(writeFakeJava(t, filepath.Join(jdk8, "bin"))), "javac")
+	if err := os.WriteFile(javac, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if home := resolveJavaHome(filepath.Join(jdk8, "jre", "bin", "java")); home != jdk8 {
+		t.Errorf("Expected JDK 8 root %s, got %s", jdk8, home)
+	}
+
+	// standalone JRE 8 keeps its own root
+	jre8 := filepath.Join(root, "jre1.8.0_202", "jre")
+	jreJava := writeFakeJava(t, filepath.Join(jre8, "bin"))
+	if home := resolveJavaHome(jreJava); home != jre8 {
+		t.Errorf("Expected JRE root %s, got %s", jre8, home)
+	}
+
+	bundle := filepath.Join(root, "temurin-21.jdk", "Contents", "Home")
+	bundleJava := writeFakeJava(t, filepath.Join(bundle, "bin"))
+	link := filepath.Join(root, "java")
+	if err := os.Symlink(bundleJava, link); err != nil {
+		t.Fatal(err)
+	}
+	if home := resolveJavaHome(link); home != bundle {
+		t.Errorf("Expected bundle home %s, got %s", bundle, home)
+	}
+}
+
+func TestReleaseFallback(t *testing.T) {
+	result := &JavaResult{
+		Path:       "/opt/jdk1.8.0_401/bin/java",
+		Evaluated:  true,
+		ReturnCode: 126,
+		Error:      errors.New("exit status 126"),
+		StdErr:     "cannot execute binary file: Exec format error\n",
+		Release:    ParseReleaseFile("JAVA_VERSION=\"1.8.0_401\"\nBUILD_TYPE=\"commercial\"\n"),
+	}
+
+	runtime := createRuntimeJSON(result, true)
+	if !runtime.ExecFailed || runtime.ExecError != "exit status 126: cannot execute binary file: Exec format error" {
+		t.Errorf("Unexpected exec failure details %v %q", runtime.ExecFailed, runtime.ExecError)
+	}
+	if runtime.VersionSource != versionSourceRelease || runtime.VersionMajor != 8 || runtime.VersionUpdate != 401 {
+		t.Errorf("Unexpected fallback version %s %d %d", runtime.VersionSource, runtime.VersionMajor, runtime.VersionUpdate)
+	}
+	if !runtime.IsOracle || runtime.RequireLicense == nil || !*runtime.RequireLicense {
+		t.Error("Expected commercial Oracle JDK 8u401 to require a license")
+	}
+
+	result.Release = ParseReleaseFile("IMPLEMENTOR=\"Eclipse Adoptium\"\nJAVA_VERSION=\"21.0.2\"\n")
+	runtime = createRuntimeJSON(result, true)
+	if runtime.Distribution != distTemurin || runtime.RequireLicense == nil || *runtime.RequireLicense {
+		t.Errorf("Unexpected fallback for Temurin: %s", runtime.Distribution)
+	}
+
+	result.Release = nil
+	runtime = createRuntimeJSON(result, true)
+	if runtime.VersionSource != "" || runtime.JavaVersion != "" {
+		t.Error("Expected no fallback without release file")
+	}
+}
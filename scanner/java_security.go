@@ -0,0 +1,198 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultSecurityProviders are the providers shipped with OpenJDK based runtimes,
+// by provider name (JDK 9+) and class name (JDK 8)
+var defaultSecurityProviders = map[string]bool{
+	"SUN": true, "SunRsaSign": true, "SunEC": true, "SunJSSE": true, "SunJCE": true,
+	"SunJGSS": true, "SunSASL": true, "XMLDSig": true, "SunPCSC": true, "JdkLDAP": true,
+	"JdkSASL": true, "SunMSCAPI": true, "SunPKCS11": true, "Apple": true,
+	"sun.security.provider.Sun":               true,
+	"sun.security.rsa.SunRsaSign":             true,
+	"sun.security.ec.SunEC":                   true,
+	"com.sun.net.ssl.internal.ssl.Provider":   true,
+	"com.sun.crypto.provider.SunJCE":          true,
+	"sun.security.jgss.SunProvider":           true,
+	"com.sun.security.sasl.Provider":          true,
+	"org.jcp.xml.dsig.internal.dom.XMLDSigRI": true,
+	"sun.security.smartcardio.SunPCSC":        true,
+	"sun.security.mscapi.SunMSCAPI":           true,
+	"apple.security.AppleProvider":            true,
+	"sun.security.pkcs11.SunPKCS11":           true,
+}
+
+// SecurityConfig summarizes the java.security configuration of a runtime
+type SecurityConfig struct {
+	Path                  string   `json:"path"`
+	CryptoPolicy          string   `json:"crypto_policy,omitempty"`
+	CustomProviders       []string `json:"custom_providers,omitempty"`
+	FIPS                  bool     `json:"fips,omitempty"`
+	TLSDisabledAlgorithms []string `json:"tls_disabled_algorithms,omitempty"`
+	JCEPolicy             string   `json:"jce_policy,omitempty"`
+	JCEPolicyFile         string   `json:"jce_policy_file,omitempty"`
+	JCEPolicySHA256       string   `json:"jce_policy_sha256,omitempty"`
+}
+
+// inspectSecurityConfig locates and summarizes the java.security file of a Java installation, nil if there is none
+func inspectSecurityConfig(javaHome string) *SecurityConfig {
+	candidates := []string{
+		filepath.Join(javaHome, "conf", "security", "java.security"),
+		filepath.Join(javaHome, "lib", "security", "java.security"),
+		filepath.Join(javaHome, "jre", "lib", "security", "java.security"),
+	}
+
+	for _, candidate := range candidates {
+		props, err := readPropertiesFile(candidate)
+		if err != nil {
+			continue
+		}
+		config := newSecurityConfig(props)
+		config.Path = candidate
+		config.inspectJCEPolicy(filepath.Dir(candidate))
+		return config
+	}
+	return nil
+}
+
+// newSecurityConfig evaluates the properties of a java.security file
+func newSecurityConfig(props map[string]string) *SecurityConfig {
+	config := &SecurityConfig{
+		CryptoPolicy: props["crypto.policy"],
+	}
+
+	for _, provider := range securityProviders(props) {
+		// providers can carry a configuration argument, e.g. SunPKCS11 with its config file
+		if !defaultSecurityProviders[strings.Fields(provider)[0]] {
+			config.CustomProviders = append(config.CustomProviders, provider)
+		}
+		if strings.Contains(strings.ToLower(provider), "fips") {
+			config.FIPS = true
+		}
+	}
+
+	// RHEL based runtimes switch to FIPS providers when the system is in FIPS mode
+	if strings.EqualFold(props["security.useSystemPropertiesFile"], "true") && hasPrefixedKey(props, "fips.provider.") {
+		config.FIPS = true
+	}
+
+	for _, algorithm := range strings.Split(props["jdk.tls.disabledAlgorithms"], ",") {
+		if algorithm = strings.TrimSpace(algorithm); algorithm != "" {
+			config.TLSDisabledAlgorithms = append(config.TLSDisabledAlgorithms, algorithm)
+		}
+	}
+
+	return config
+}
+
+// inspectJCEPolicy checks the legacy JCE jurisdiction policy of Java 8 and earlier runtimes.
+// Up to 8u151 the policy is defined by lib/security/local_policy.jar, the Unlimited Strength
+// policy files replace it with a jar granting CryptoAllPermission.
+func (c *SecurityConfig) inspectJCEPolicy(securityDir string) {
+	jarPath := filepath.Join(securityDir, "local_policy.jar")
+	data, err := os.ReadFile(jarPath)
+	if err != nil {
+		return
+	}
+
+	c.JCEPolicyFile = jarPath
+	c.JCEPolicySHA256 = fmt.Sprintf("%x", sha256.Sum256(data))
+
+	policy, err := readJarEntry(data, "default_local.policy")
+	if err != nil {
+		return
+	}
+	if strings.Contains(policy, "CryptoAllPermission") {
+		c.JCEPolicy = "unlimited"
+	} else {
+		c.JCEPolicy = "limited"
+	}
+}
+
+// readJarEntry returns the content of a file in a jar archive
+func readJarEntry(data []byte, name string) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	for _, file := range archive.File {
+		if file.Name != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = rc.Close() }()
+		content, err := io.ReadAll(io.LimitReader(rc, 1<<20))
+		return string(content), err
+	}
+	return "", fmt.Errorf("%s not found", name)
+}
+
+// securityProviders returns the configured providers ordered by preference
+func securityProviders(props map[string]string) []string {
+	type entry struct {
+		order    int
+		provider string
+	}
+
+	var entries []entry
+	for key, value := range props {
+		if !strings.HasPrefix(key, "security.provider.") || value == "" {
+			continue
+		}
+		order, err := strconv.Atoi(strings.TrimPrefix(key, "security.provider."))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{order, value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].order < entries[j].order })
+
+	providers := make([]string, 0, len(entries))
+	for _, e := range entries {
+		providers = append(providers, e.provider)
+	}
+	return providers
+}
+
+func hasPrefixedKey(props map[string]string, prefix string) bool {
+	for key := range props {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// printSecurityConfig prints the security configuration summary in text output mode
+func printSecurityConfig(c *SecurityConfig) {
+	fmt.Printf("Security configuration: %s\n", c.Path)
+	if c.CryptoPolicy != "" {
+		fmt.Printf("Crypto policy: %s\n", c.CryptoPolicy)
+	}
+	if len(c.CustomProviders) > 0 {
+		fmt.Printf("Custom security providers: %s\n", strings.Join(c.CustomProviders, ", "))
+	}
+	if c.FIPS {
+		fmt.Printf("Info: FIPS security provider configured\n")
+	}
+	if len(c.TLSDisabledAlgorithms) > 0 {
+		fmt.Printf("TLS disabled algorithms: %s\n", strings.Join(c.TLSDisabledAlgorithms, ", "))
+	}
+	if c.JCEPolicy != "" {
+		fmt.Printf("JCE policy: %s (%s)\n", c.JCEPolicy, c.JCEPolicyFile)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewSecurityConfig(t *testing.T) {
+	props := parseProperties(`security.provider.1=SUN
+security.provider.2=org.bouncycastle.jcajce.provider.BouncyCastleFipsProvider
+security.provider.3=SunPKCS11 ${java.home}/conf/security/nss.cfg
+security.provider.10=com.example.CustomProvider
+crypto.policy=unlimited
+jdk.tls.disabledAlgorithms=SSLv3, TLSv1, \
+    TLSv1.1
+`)
+	config := newSecurityConfig(props)
+
+	want := []string{"org.bouncycastle.jcajce.provider.BouncyCastleFipsProvider", "com.example.CustomProvider"}
+	if !reflect.DeepEqual(config.CustomProviders, want) {
+		t.Errorf("Expected custom providers %v, got %v", want, config.CustomProviders)
+	}
+	if !config.FIPS {
+		t.Error("Expected FIPS provider to be detected")
+	}
+	if config.CryptoPolicy != "unlimited" {
+		t.Errorf("Expected crypto policy unlimited, got %s", config.CryptoPolicy)
+	}
+	if len(config.TLSDisabledAlgorithms) != 3 {
+		t.Errorf("Expected 3 disabled TLS algorithms, got %v", config.TLSDisabledAlgorithms)
+	}
+}
+
+func TestInspectJCEPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create("default_local.policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("grant {\n    permission javax.crypto.CryptoAllPermission;\n};\n"))
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "local_policy.jar"), buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &SecurityConfig{}
+	config.inspectJCEPolicy(dir)
+
+	if config.JCEPolicy != "unlimited" {
+		t.Errorf("Expected unlimited JCE policy, got %q", config.JCEPolicy)
+	}
+	if len(config.JCEPolicySHA256) != 64 {
+		t.Errorf("Expected SHA-256 fingerprint, got %q", config.JCEPolicySHA256)
+	}
+}
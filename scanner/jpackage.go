@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// JPackageApp is an application packaged with jpackage together with its bundled runtime. Bundled runtimes are
+// updated by rebuilding the application, remediation is up to the application owner.
+type JPackageApp struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Path is the application root: the directory with bin/ and lib/ on Linux, with app\ and runtime\ on Windows,
+	// or the .app bundle on macOS
+	Path string `json:"path"`
+	// Runtime is the Java home of the bundled runtime, usually without a java executable (jlink
+	// --strip-native-commands), version and vendor are read from its release file
+	Runtime        string `json:"runtime"`
+	RuntimeVersion string `json:"runtime_version,omitempty"`
+	RuntimeVendor  string `json:"runtime_vendor,omitempty"`
+	// JavaExecutable is set if the bundled runtime has a java executable found by the scan
+	JavaExecutable string `json:"java_executable,omitempty"`
+	// JPackageVersion is the JDK version of the jpackage tool that built the application
+	JPackageVersion string `json:"jpackage_version,omitempty"`
+}
+
+// jpackageState is the app/.jpackage.xml file written by jpackage 16 and later
+type jpackageState struct {
+	Version      string `xml:"version,attr"`
+	AppVersion   string `xml:"app-version"`
+	MainLauncher string `xml:"main-launcher"`
+}
+
+// checkJPackageRuntime returns the application of a directory named runtime next to a jpackage app directory
+// (lib/runtime and lib/app on Linux, runtime and app on Windows, Contents/runtime and Contents/app on macOS).
+// The app directory holds .jpackage.xml and a launcher configuration <name>.cfg for every launcher.
+func checkJPackageRuntime(dir string) (JPackageApp, bool) {
+	if filepath.Base(dir) != "runtime" {
+		return JPackageApp{}, false
+	}
+	parent := filepath.Dir(dir)
+	appDir := filepath.Join(parent, "app")
+
+	var state jpackageState
+	if data, err := os.ReadFile(filepath.Join(appDir, ".jpackage.xml")); err == nil {
+		_ = xml.Unmarshal(data, &state)
+	}
+	if state.MainLauncher == "" {
+		// applications built before jpackage 16 only have the launcher configurations
+		configs, _ := filepath.Glob(filepath.Join(appDir, "*.cfg"))
+		if len(configs) == 0 {
+			return JPackageApp{}, false
+		}
+		sort.Strings(configs)
+		state.MainLauncher = strings.TrimSuffix(filepath.Base(configs[0]), ".cfg")
+	}
+
+	root := parent
+	if name := filepath.Base(parent); name == "lib" || name == "Contents" {
+		root = filepath.Dir(parent)
+	}
+	home := dir
+	if info, err := os.Stat(filepath.Join(dir, "Contents", "Home")); err == nil && info.IsDir() {
+		home = filepath.Join(dir, "Contents", "Home")
+	}
+
+	app := JPackageApp{
+		Name:            state.MainLauncher,
+		Version:         strings.TrimSpace(state.AppVersion),
+		Path:            root,
+		Runtime:         home,
+		JPackageVersion: state.Version,
+	}
+	if release := readReleaseFile(home); release != nil {
+		app.RuntimeVersion = release["JAVA_VERSION"]
+		app.RuntimeVendor = release["IMPLEMENTOR"]
+	}
+	return app, true
+}
+
+// checkApplication records the directory if it is the runtime of a jpackage application, it is called by the walkers
+func (f *JavaFinder) checkApplication(dir string) {
+	app, ok := checkJPackageRuntime(dir)
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	f.applications = append(f.applications, app)
+	f.mu.Unlock()
+}
+
+// attributeApplications attaches each runtime bundled with a jpackage application to the application
+func attributeApplications(apps []JPackageApp, results []*JavaResult) {
+	for i := range apps {
+		for _, result := range results {
+			if result.JavaHome == apps[i].Runtime || javaHomeOf(result.Path) == apps[i].Runtime {
+				apps[i].JavaExecutable = result.Path
+				app := apps[i]
+				result.Application = &app
+			}
+		}
+	}
+}
+
+// bundlingApplication returns the jpackage application bundling the runtime of a result, the attribution of
+// attributeApplications for a single runtime without the applications found by the walk
+func bundlingApplication(result *JavaResult) (JPackageApp, bool) {
+	for _, home := range []string{result.JavaHome, javaHomeOf(result.Path)} {
+		dir := home
+		// the runtime of a macOS bundle is a bundle itself, its home is Contents/Home
+		if filepath.Base(home) == "Home" && filepath.Base(filepath.Dir(home)) == "Contents" {
+			dir = filepath.Dir(filepath.Dir(home))
+		}
+		if app, ok := checkJPackageRuntime(dir); ok && app.Runtime == home {
+			app.JavaExecutable = result.Path
+			return app, true
+		}
+	}
+	return JPackageApp{}, false
+}
+
+// sortApplications sorts applications by path
+func sortApplications(apps []JPackageApp) []JPackageApp {
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Path < apps[j].Path })
+	return apps
+}
+
+// printApplications prints the jpackage applications in text output mode
+func printApplications(apps []JPackageApp) {
+	if len(apps) == 0 {
+		return
+	}
+	printf("Applications with bundled runtimes (jpackage):\n")
+	for _, app := range apps {
+		runtime := strings.TrimSpace(app.RuntimeVendor + " " + app.RuntimeVersion)
+		if runtime == "" {
+			runtime = "unknown runtime"
+		}
+		printf("- %s: %s (%s)\n", strings.TrimSpace(app.Name+" "+app.Version), app.Path, runtime)
+	}
+}
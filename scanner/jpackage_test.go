@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testJPackageState = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<jpackage-state platform="LINUX" version="17.0.2">
+  <app-version>2.4.1</app-version>
+  <main-launcher>inventory</main-launcher>
+  <main-class>com.example.Inventory</main-class>
+</jpackage-state>
+`
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckJPackageRuntime(t *testing.T) {
+	root := t.TempDir()
+
+	// Linux layout with a stripped runtime
+	linux := filepath.Join(root, "opt", "inventory")
+	writeTestFile(t, filepath.Join(linux, "lib", "app", ".jpackage.xml"), testJPackageState)
+	writeTestFile(t, filepath.Join(linux, "lib", "runtime", "release"), "JAVA_VERSION=\"17.0.2\"\nIMPLEMENTOR=\"Eclipse Adoptium\"\n")
+	app, ok := checkJPackageRuntime(filepath.Join(linux, "lib", "runtime"))
+	if !ok {
+		t.Fatal("expected jpackage application")
+	}
+	want := JPackageApp{Name: "inventory", Version: "2.4.1", Path: linux, Runtime: filepath.Join(linux, "lib", "runtime"),
+		RuntimeVersion: "17.0.2", RuntimeVendor: "Eclipse Adoptium", JPackageVersion: "17.0.2"}
+	if app != want {
+		t.Errorf("unexpected application %+v", app)
+	}
+
+	// macOS bundle built before jpackage 16, only the launcher configuration
+	bundle := filepath.Join(root, "Applications", "Inventory.app")
+	writeTestFile(t, filepath.Join(bundle, "Contents", "app", "Inventory.cfg"), "[Application]\napp.mainclass=com.example.Inventory\n")
+	writeTestFile(t, filepath.Join(bundle, "Contents", "runtime", "Contents", "Home", "release"), "JAVA_VERSION=\"14.0.2\"\n")
+	app, ok = checkJPackageRuntime(filepath.Join(bundle, "Contents", "runtime"))
+	if !ok || app.Name != "Inventory" || app.Path != bundle || app.RuntimeVersion != "14.0.2" ||
+		app.Runtime != filepath.Join(bundle, "Contents", "runtime", "Contents", "Home") {
+		t.Errorf("unexpected application %+v", app)
+	}
+
+	// a runtime directory without an app directory
+	other := filepath.Join(root, "srv", "runtime")
+	if err := os.MkdirAll(other, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := checkJPackageRuntime(other); ok {
+		t.Error("unexpected application without app directory")
+	}
+	if _, ok := checkJPackageRuntime(filepath.Join(linux, "lib", "app")); ok {
+		t.Error("unexpected application for the app directory")
+	}
+}
+
+func TestFindJPackageApplications(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "opt", "inventory")
+	writeTestFile(t, filepath.Join(app, "lib", "app", ".jpackage.xml"), testJPackageState)
+	writeFakeJava(t, filepath.Join(app, "lib", "runtime", "bin"))
+	writeFakeJava(t, filepath.Join(root, "opt", "jdk-17", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 2 || len(finder.applications) != 1 {
+			t.Fatalf("threads %d: expected 2 results and 1 application, got %d and %+v", threads, len(results), finder.applications)
+		}
+
+		apps := finder.applications
+		attributeApplications(apps, results)
+		bundled := filepath.Join(app, "lib", "runtime", "bin", "java")
+		if apps[0].JavaExecutable != bundled {
+			t.Errorf("threads %d: unexpected java executable %s", threads, apps[0].JavaExecutable)
+		}
+		for _, result := range results {
+			if (result.Path == bundled) != (result.Application != nil) {
+				t.Errorf("threads %d: unexpected attribution of %s: %+v", threads, result.Path, result.Application)
+			}
+		}
+	}
+}
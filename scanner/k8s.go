@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	k8sPathMarker = "@@JFIND@@ "
+	k8sRCMarker   = "@@JFIND-RC@@ "
+)
+
+// k8sDiscoveryScript finds and evaluates java executables inside a container, it only requires a POSIX shell and find
+const k8sDiscoveryScript = `find / -xdev \( -path /proc -o -path /sys -o -path /dev \) -prune -o -type f -name java -print 2>/dev/null |
+while IFS= read -r j; do
+  [ -x "$j" ] || continue
+  echo "` + k8sPathMarker + `$j"
+  "$j" -XshowSettings:properties -version 2>&1
+  echo "` + k8sRCMarker + `$?"
+done`
+
+// K8sWorkload attributes a runtime found in a Kubernetes container to its workload
+type K8sWorkload struct {
+	Context   string `json:"context,omitempty"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Image     string `json:"image,omitempty"`
+	OwnerKind string `json:"owner_kind,omitempty"`
+	OwnerName string `json:"owner_name,omitempty"`
+}
+
+type k8sConfig struct {
+	kubeconfig     string
+	context        string
+	namespace      string
+	allNamespaces  bool
+	selector       string
+	execTimeout    time.Duration
+	jsonOutput     bool
+	doPost         bool
+	postURL        string
+	requireLicense bool
+	logs           logFlags
+	help           bool
+}
+
+// kubectl pod list, only the fields used for attribution
+type k8sPodList struct {
+	Items []k8sPod `json:"items"`
+}
+
+type k8sPod struct {
+	Metadata struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		Labels          map[string]string `json:"labels"`
+		OwnerReferences []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Status struct {
+		Phase             string `json:"phase"`
+		ContainerStatuses []struct {
+			Name  string `json:"name"`
+			Image string `json:"image"`
+			State struct {
+				Running *struct{} `json:"running"`
+			} `json:"state"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// runK8s implements the 'k8s' subcommand
+func runK8s(args []string) int {
+	config := parseK8sFlags(args)
+
+	startTime := time.Now()
+	pods, err := config.listPods()
+	if err != nil {
+		slog.Error("listing pods", "error", err)
+		return 1
+	}
+
+	var results []*JavaResult
+	scannedContainers := 0
+	for _, pod := range pods {
+		for _, workload := range config.workloads(pod) {
+			slog.Info("Scanning container", "namespace", workload.Namespace, "pod", workload.Pod, "container", workload.Container)
+			found, err := config.scanContainer(workload)
+			if err != nil {
+				slog.Error("scanning container", "namespace", workload.Namespace, "pod", workload.Pod, "container", workload.Container, "error", err)
+				continue
+			}
+			scannedContainers++
+			results = append(results, found...)
+		}
+	}
+	slog.Info("Scanned containers", "containers", scannedContainers, "found", len(results))
+
+	outputConfig := config.outputConfig()
+	if outputConfig.jsonOutput {
+		if err := handleJSONOutput(results, scannedContainers, outputConfig, startTime); err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+		return 0
+	}
+	handleRegularOutput(results, outputConfig)
+	return 0
+}
+
+func parseK8sFlags(args []string) k8sConfig {
+	var config k8sConfig
+
+	flags := flag.NewFlagSet("k8s", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s k8s [options]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Scan running Kubernetes containers for Java runtimes using kubectl exec.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&config.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file (default: kubectl default)")
+	flags.StringVar(&config.context, "context", "", "Kubeconfig context to use (default: current context)")
+	flags.StringVar(&config.namespace, "namespace", "", "Namespace to scan (default: namespace of the context)")
+	flags.BoolVar(&config.allNamespaces, "all-namespaces", false, "Scan pods in all namespaces")
+	flags.StringVar(&config.selector, "selector", "", "Label selector to filter pods, e.g. app=web")
+	flags.DurationVar(&config.execTimeout, "exec-timeout", 2*time.Minute, "Timeout for scanning a single container")
+	flags.BoolVar(&config.jsonOutput, "json", false, "Output results in JSON format")
+	flags.BoolVar(&config.doPost, "post", false, "Post JSON output to server (implies --json)")
+	flags.StringVar(&config.postURL, "url", defaultPostURL, "URL to post JSON output to (only used with --post)")
+	flags.BoolVar(&config.requireLicense, "require-license", false, "Filter only Java runtimes that require a commercial license")
+	config.logs.register(flags)
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+
+	_ = flags.Parse(args)
+
+	if config.help {
+		flags.Usage()
+		os.Exit(0)
+	}
+	if err := config.logs.setup(os.Stderr); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if config.doPost {
+		config.jsonOutput = true
+	}
+
+	return config
+}
+
+// outputConfig returns the configuration for the shared output handlers
+func (c k8sConfig) outputConfig() config {
+	scope := c.namespace
+	if c.allNamespaces {
+		scope = "*"
+	}
+	if c.context != "" {
+		scope = c.context + "/" + scope
+	}
+	return config{
+		startPath:  "k8s:" + scope,
+		evaluate:   true,
+		jsonOutput: c.jsonOutput,
+		doPost:     c.doPost,
+		postURL:    c.postURL,
+		filter:     runtimeFilter{requireLicense: c.requireLicense},
+	}
+}
+
+// kubectl runs kubectl with the connection flags and returns stdout
+func (c k8sConfig) kubectl(ctx context.Context, args ...string) ([]byte, error) {
+	var base []string
+	if c.kubeconfig != "" {
+		base = append(base, "--kubeconfig", c.kubeconfig)
+	}
+	if c.context != "" {
+		base = append(base, "--context", c.context)
+	}
+
+	cmd := exec.CommandContext(ctx, "kubectl", append(base, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// listPods returns the running pods matching the namespace and selector
+func (c k8sConfig) listPods() ([]k8sPod, error) {
+	args := []string{"get", "pods", "-o", "json"}
+	if c.allNamespaces {
+		args = append(args, "--all-namespaces")
+	} else if c.namespace != "" {
+		args = append(args, "--namespace", c.namespace)
+	}
+	if c.selector != "" {
+		args = append(args, "--selector", c.selector)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	out, err := c.kubectl(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list k8sPodList
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("parsing pod list: %v", err)
+	}
+
+	pods := make([]k8sPod, 0, len(list.Items))
+	for _, pod := range list.Items {
+		if pod.Status.Phase == "Running" {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// workloads returns the running containers of a pod, attributed to the owning workload
+func (c k8sConfig) workloads(pod k8sPod) []K8sWorkload {
+	ownerKind, ownerName := k8sOwner(pod)
+
+	var workloads []K8sWorkload
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running == nil {
+			continue
+		}
+		workloads = append(workloads, K8sWorkload{
+			Context:   c.context,
+			Namespace: pod.Metadata.Namespace,
+			Pod:       pod.Metadata.Name,
+			Container: status.Name,
+			Image:     status.Image,
+			OwnerKind: ownerKind,
+			OwnerName: ownerName,
+		})
+	}
+	return workloads
+}
+
+// k8sOwner returns the workload owning a pod.
+// ReplicaSets created by a Deployment are named <deployment>-<pod-template-hash>,
+// which resolves the Deployment without additional API calls.
+func k8sOwner(pod k8sPod) (string, string) {
+	if len(pod.Metadata.OwnerReferences) == 0 {
+		return "", ""
+	}
+
+	owner := pod.Metadata.OwnerReferences[0]
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Metadata.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return owner.Kind, owner.Name
+}
+
+// scanContainer runs the discovery script in a container and returns the evaluated runtimes
+func (c k8sConfig) scanContainer(workload K8sWorkload) ([]*JavaResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.execTimeout)
+	defer cancel()
+
+	out, err := c.kubectl(ctx, "exec", "--namespace", workload.Namespace, workload.Pod,
+		"--container", workload.Container, "--", "sh", "-c", k8sDiscoveryScript)
+	if err != nil {
+		return nil, err
+	}
+
+	results := parseK8sDiscoveryOutput(string(out))
+	for _, result := range results {
+		w := workload
+		result.Workload = &w
+	}
+	return results, nil
+}
+
+// parseK8sDiscoveryOutput splits the discovery script output into evaluation results
+func parseK8sDiscoveryOutput(output string) []*JavaResult {
+	var results []*JavaResult
+	var current *JavaResult
+	var block strings.Builder
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, k8sPathMarker):
+			path := strings.TrimPrefix(line, k8sPathMarker)
+			current = &JavaResult{Path: path, JavaHome: javaHomeOf(path), Evaluated: true}
+			block.Reset()
+		case strings.HasPrefix(line, k8sRCMarker) && current != nil:
+			current.StdErr = block.String()
+			current.ReturnCode, _ = strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, k8sRCMarker)))
+			if current.ReturnCode == 0 {
+				current.Properties = ParseJavaProperties(current.StdErr)
+			} else {
+				current.Error = fmt.Errorf("exit status %d", current.ReturnCode)
+			}
+			results = append(results, current)
+			current = nil
+		case current != nil:
+			block.WriteString(line)
+			block.WriteString("\n")
+		}
+	}
+	return results
+}
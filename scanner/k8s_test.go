@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestParseK8sDiscoveryOutput(t *testing.T) {
+	output := `@@JFIND@@ /opt/java/openjdk/bin/java
+Property settings:
+    java.version = 17.0.8.1
+    java.vendor = Eclipse Adoptium
+@@JFIND-RC@@ 0
+@@JFIND@@ /broken/bin/java
+Error: could not find libjava.so
+@@JFIND-RC@@ 1
+`
+	results := parseK8sDiscoveryOutput(output)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	if results[0].Path != "/opt/java/openjdk/bin/java" || results[0].Properties == nil || results[0].Properties.Version != "17.0.8.1" {
+		t.Errorf("Unexpected first result %+v", results[0])
+	}
+	if results[1].ReturnCode != 1 || results[1].Error == nil || results[1].Properties != nil {
+		t.Errorf("Expected second result to have failed, got %+v", results[1])
+	}
+}
+
+func TestK8sOwner(t *testing.T) {
+	var pod k8sPod
+	pod.Metadata.Labels = map[string]string{"pod-template-hash": "5d8f9c7b4"}
+	pod.Metadata.OwnerReferences = append(pod.Metadata.OwnerReferences, struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+	}{"ReplicaSet", "web-5d8f9c7b4"})
+
+	if kind, name := k8sOwner(pod); kind != "Deployment" || name != "web" {
+		t.Errorf("Expected Deployment web, got %s %s", kind, name)
+	}
+}
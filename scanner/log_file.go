@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Defaults of the log file rotation, the log of a daily scan rarely fills one file in months
+const (
+	defaultLogMaxSize  = "10MiB"
+	defaultLogMaxFiles = 5
+)
+
+// rotatingFile is a log file rotated by size. When a write would grow the file beyond maxSize it is renamed to
+// path.1, older files move up to path.<maxFiles> and the oldest is removed, so the logs never take more than
+// (maxFiles + 1) * maxSize bytes.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// openRotatingFile opens or creates the log file at path for appending
+func openRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("the maximum size of the log file must be positive")
+	}
+	if maxFiles < 0 {
+		return nil, fmt.Errorf("the number of rotated log files must not be negative")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create the log directory: %v", err)
+	}
+	f := &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the current log file and records its size
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot open the log file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("cannot open the log file: %v", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write appends to the log file, rotating it first if p does not fit. A record larger than maxSize is written to an
+// empty file.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate moves the log files up by one and opens a new log file
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	if f.maxFiles == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return f.open()
+	}
+	for i := f.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(f.backupPath(i), f.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(f.path, f.backupPath(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return f.open()
+}
+
+// backupPath returns the path of the nth rotated log file
+func (f *rotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
+
+// Close closes the log file
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
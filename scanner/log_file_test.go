@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "jfind.log")
+	file, err := openRotatingFile(path, 20, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first line\n", "second line\n", "third line\n", "fourth line\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a record larger than the maximum size is written to an empty file
+	if _, err := file.Write([]byte(strings.Repeat("x", 30) + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"jfind.log":   strings.Repeat("x", 30) + "\n",
+		"jfind.log.1": "fourth line\n",
+		"jfind.log.2": "third line\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(filepath.Dir(path), name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s: got %q (%v), want %q", name, data, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 rotated files")
+	}
+
+	// an existing log file is appended to and counts towards the size
+	file, err = openRotatingFile(path, 35, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write([]byte("appended\n")); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	if data, _ := os.ReadFile(path); string(data) != "appended\n" {
+		t.Errorf("expected the full log file to be replaced without rotated files, got %q", data)
+	}
+
+	if _, err := openRotatingFile(path, 0, 1); err == nil {
+		t.Error("expected an error for a maximum size of 0")
+	}
+}
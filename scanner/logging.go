@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Diagnostics (progress, warnings, errors) are written with log/slog to stderr or a log file, the output of a scan to
+// stdout. The default text format keeps the messages readable on a terminal, the JSON format is one object per line
+// for log collectors.
+
+// logLevel is the level of the default logger, set by --log-level
+var logLevel = new(slog.LevelVar)
+
+// jsonLogs is set with --log-format json, the progress report is logged as records instead of updated in place
+var jsonLogs bool
+
+// logOutput receives the diagnostics, stderr or the --log-file
+var logOutput io.Writer = os.Stderr
+
+func init() {
+	slog.SetDefault(slog.New(newTextLogHandler(os.Stderr, logLevel)))
+}
+
+// logFlags are the logging flags of the scan and the subcommands running unattended
+type logFlags struct {
+	level    string
+	format   string
+	file     string
+	maxSize  string
+	maxFiles int
+}
+
+// register adds the logging flags to a flag set
+func (f *logFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.level, "log-level", "info", "Level of the diagnostics on stderr: debug, info, warn or error")
+	flags.StringVar(&f.format, "log-format", "text", "Format of the diagnostics on stderr: text, or json with one object per line")
+	flags.StringVar(&f.file, "log-file", "", "Write the diagnostics to this file instead of stderr, rotated by size")
+	flags.StringVar(&f.maxSize, "log-max-size", defaultLogMaxSize, "Size at which the --log-file is rotated, e.g. 10MiB")
+	flags.IntVar(&f.maxFiles, "log-max-files", defaultLogMaxFiles, "Number of rotated log files kept besides the --log-file")
+}
+
+// setup installs the default logger of the flags, writing to w unless a log file is set
+func (f logFlags) setup(w io.Writer) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(f.level)); err != nil {
+		return fmt.Errorf("invalid log level '%s', supported: debug, info, warn, error", f.level)
+	}
+	if f.format != "text" && f.format != "json" {
+		return fmt.Errorf("invalid log format '%s', supported: text, json", f.format)
+	}
+	if f.file != "" {
+		maxSize, err := humanize.ParseBytes(f.maxSize)
+		if err != nil {
+			return fmt.Errorf("invalid log file size '%s'", f.maxSize)
+		}
+		file, err := openRotatingFile(f.file, int64(maxSize), f.maxFiles)
+		if err != nil {
+			return err
+		}
+		w = file
+	}
+	var handler slog.Handler = newTextLogHandler(w, logLevel)
+	if f.format == "json" {
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: logLevel})
+	}
+	logLevel.Set(level)
+	jsonLogs = f.format == "json"
+	logOutput = w
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// logEnabled reports whether messages of a level are logged
+func logEnabled(level slog.Level) bool {
+	return slog.Default().Enabled(context.Background(), level)
+}
+
+// interactiveLogs reports whether text logs are written to a terminal, where the progress report is updated in place
+func interactiveLogs() bool {
+	return !jsonLogs && logOutput == io.Writer(os.Stderr) && stderrIsTerminal()
+}
+
+// textLogHandler writes a record per line as the message followed by its attributes as key=value. Warnings and
+// errors are prefixed with "Warning:" and "Error:", the time is omitted.
+type textLogHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	// attrs are the formatted attributes of WithAttrs, group the key prefix of WithGroup
+	attrs string
+	group string
+}
+
+// newTextLogHandler creates a text handler writing the records of level and above to w
+func newTextLogHandler(w io.Writer, level slog.Leveler) *textLogHandler {
+	return &textLogHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *textLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *textLogHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	switch {
+	case record.Level >= slog.LevelError:
+		b.WriteString("Error: ")
+	case record.Level >= slog.LevelWarn:
+		b.WriteString("Warning: ")
+	case record.Level < slog.LevelInfo:
+		b.WriteString("Debug: ")
+	}
+	b.WriteString(record.Message)
+	b.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		appendLogAttr(&b, h.group, attr)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *textLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, attr := range attrs {
+		appendLogAttr(&b, h.group, attr)
+	}
+	handler := *h
+	handler.attrs += b.String()
+	return &handler
+}
+
+func (h *textLogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handler := *h
+	handler.group += name + "."
+	return &handler
+}
+
+// appendLogAttr appends an attribute as " key=value", the attributes of a group with the group name as key prefix
+func appendLogAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			appendLogAttr(b, prefix, member)
+		}
+		return
+	}
+	value := attr.Value.String()
+	if value == "" || strings.IndexFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) >= 0 {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, attr.Key, value)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestTextLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	logger := slog.New(newTextLogHandler(&buf, level))
+
+	logger.Info("Start scanning", "platform", "linux", "path", "/opt")
+	logger.Warn("cannot list processes", "error", errors.New("permission denied"))
+	logger.Error("path does not exist", "path", "")
+	logger.Debug("not written")
+	logger.With("root", "/srv").WithGroup("walk").Info("Skipping mount point", "type", "nfs4")
+	level.Set(slog.LevelDebug)
+	logger.Debug("written", slog.Group("limits", "depth", 3))
+
+	want := "Start scanning platform=linux path=/opt\n" +
+		"Warning: cannot list processes error=\"permission denied\"\n" +
+		"Error: path does not exist path=\"\"\n" +
+		"Skipping mount point root=/srv walk.type=nfs4\n" +
+		"Debug: written limits.depth=3\n"
+	if buf.String() != want {
+		t.Errorf("unexpected log\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestLogFlagsSetup(t *testing.T) {
+	defer func() {
+		_ = logFlags{level: "info", format: "text"}.setup(os.Stderr)
+	}()
+
+	var buf bytes.Buffer
+	if err := (logFlags{level: "warn", format: "json"}).setup(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !jsonLogs || logEnabled(slog.LevelInfo) || !logEnabled(slog.LevelWarn) {
+		t.Errorf("expected JSON logs of warnings and errors")
+	}
+	slog.Info("Start scanning")
+	slog.Warn("cannot save the scan state", "error", "disk full")
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "cannot save the scan state" || record["error"] != "disk full" {
+		t.Errorf("unexpected record %v", record)
+	}
+
+	for _, flags := range []logFlags{{level: "verbose", format: "text"}, {level: "info", format: "xml"}} {
+		if err := flags.setup(&buf); err == nil {
+			t.Errorf("%+v: expected an error", flags)
+		}
+	}
+}
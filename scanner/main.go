@@ -1,32 +1,143 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"text/template"
 	"time"
+
+	"github.com/dustin/go-humanize"
 )
 
 const defaultPostURL = "http://localhost:8000/api/jfind"
 
 type config struct {
-	startPath      string
-	maxDepth       int
-	evaluate       bool
-	jsonOutput     bool
-	doPost         bool
-	postURL        string
-	requireLicense bool
-	showRules      bool
-	help           bool
+	startPath        string
+	paths            stringList
+	maxDepth         int
+	evaluate         bool
+	jsonOutput       bool
+	doPost           bool
+	outputPath       string
+	split            int
+	postURL          string
+	uploadChunk      string
+	uploadChunkSize  int
+	collectorTLS     tlsFiles
+	requireLicense   bool
+	vendors          stringList
+	minMajor         int
+	maxMajor         int
+	versionRange     string
+	onlyExecFailed   bool
+	filter           runtimeFilter
+	listModules      bool
+	inspectCacerts   bool
+	inspectSecurity  bool
+	signKey          string
+	signatureFile    string
+	encryptTo        stringList
+	pseudonymKey     string
+	redact           stringList
+	redactFile       string
+	redactor         redactor
+	duplicates       bool
+	reproducible     bool
+	allProperties    bool
+	format           string
+	template         string
+	outputTemplate   *template.Template
+	wide             bool
+	stream           *ndjsonStream
+	threads          string
+	evalWorkers      int
+	maxDuration      time.Duration
+	truncated        bool
+	interrupted      bool
+	lastScannedPath  string
+	smb              string
+	smbCredentials   string
+	hostEvidence     bool
+	shareTimeout     time.Duration
+	profile          string
+	quick            bool
+	configPath       string
+	excludes         stringList
+	allUsers         bool
+	index            string
+	indexOnly        bool
+	allowMounts      stringList
+	oneFileSystem    bool
+	includeNetworkFS bool
+	evalArgs         stringList
+	evalTimeout      time.Duration
+	incremental      bool
+	fullScan         bool
+	statePath        string
+	walkState        *walkState
+	skipForeign      bool
+	owners           stringList
+	ownerScope       *ownerScope
+	processes        bool
+	requireAdmin     bool
+	errorDetails     bool
+	maxErrors        int
+	errorLog         *errorLog
+	findInstallers   bool
+	installers       []InstallerArtifact
+	suspected        []SuspectedInstall
+	applications     []JPackageApp
+	roots            []scanRoot
+	rootStats        []RootStats
+	baselinePath     string
+	baseline         *Baseline
+	baselineMissing  []BaselineEntry
+	baselineSummary  *BaselineSummary
+	policyPath       string
+	policies         []Policy
+	policyViolating  int
+	exitCodes        map[string]int
+	failOn           stringList
+	progressInterval time.Duration
+	progressTemplate string
+	progressFormat   *template.Template
+	legacy           []LegacyComponent
+	profiles         []UserProfile
+	showRules        bool
+	quiet            bool
+	logs             logFlags
+	help             bool
+}
+
+// subcommands maps subcommand names to their entry points, returning the exit code
+var subcommands = map[string]func(args []string) int{
+	"watch":    runWatch,
+	"k8s":      runK8s,
+	"verify":   runVerify,
+	"eval":     runEval,
+	"rules":    runRules,
+	"doctor":   runDoctor,
+	"baseline": runBaseline,
+	"report":   runReport,
+	"schema":   runSchema,
+	"serve":    runServe,
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
 	config := parseFlags()
 
 	if config.showRules {
@@ -39,41 +150,268 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Convert relative path to absolute
-	absPath, err := filepath.Abs(config.startPath)
-	if err != nil {
-		logf("Error resolving path: %v\n", err)
-		os.Exit(1)
+	os.Exit(runScan(config))
+}
+
+// runScan scans the configured path and writes the report, it returns the exit code
+func runScan(config config) int {
+	privileged := isPrivileged()
+	if config.requireAdmin && !privileged {
+		slog.Error("--require-admin is set, but jfind is not running as root or from an elevated prompt")
+		return 1
 	}
 
-	// Check if path exists
-	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		logf("Error: path '%s' does not exist\n", absPath)
-		os.Exit(1)
+	// the first interrupt stops the walk and the runtimes found so far are reported, a second one kills the running
+	// evaluations before jfind exits
+	interrupts := newInterruptHandler()
+	defer interrupts.release()
+
+	// the entitlement evidence and the installed programs describe the host running jfind, they are reported for
+	// its own file systems only, not for a share, a cluster or the runtimes given to -eval
+	config.hostEvidence = config.smb == ""
+
+	if config.smb != "" {
+		mount, err := mountSMB(config.smb, config.smbCredentials, config.shareTimeout)
+		if err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+		// the share is unmounted on every return and when a signal terminates jfind
+		defer mount.unmount()
+		interrupts.onExit(mount.unmount)
+		config.startPath = mount.scanPath()
+		// report the share instead of the temporary mount point
+		config.redactor = append(redactor{mount.redactionRule()}, config.redactor...)
+	}
+
+	scanRoots := config.roots
+	if len(scanRoots) == 0 {
+		scanRoots = []scanRoot{config.scanRoot(config.startPath)}
+	}
+	var roots []scanRoot
+	var paths []string
+	for _, root := range scanRoots {
+		// Convert relative path to absolute
+		absPath, err := filepath.Abs(root.path)
+		if err != nil {
+			slog.Error("resolving path", "error", err)
+			return 1
+		}
+
+		// Check if path exists, dead network shares fail after the share timeout instead of hanging. Roots of the
+		// configuration file missing on this host are skipped.
+		if _, err := statWithTimeout(absPath, config.shareTimeout); err != nil {
+			level := slog.LevelError
+			if root.optional {
+				level = slog.LevelWarn
+			}
+			if os.IsNotExist(err) {
+				slog.Log(context.Background(), level, "path does not exist", "path", absPath)
+			} else {
+				slog.Log(context.Background(), level, "path is not accessible", "path", absPath, "error", err)
+			}
+			if !root.optional {
+				return 1
+			}
+			continue
+		}
+		root.path = absPath
+		roots = append(roots, root)
+		paths = append(paths, absPath)
+	}
+	if len(roots) == 0 {
+		slog.Error("none of the configured roots is accessible")
+		return 1
+	}
+	if len(config.roots) > 0 {
+		config.startPath = strings.Join(paths, string(os.PathListSeparator))
+	}
+
+	if config.allUsers {
+		profiles, err := hostUserProfiles()
+		if err != nil {
+			slog.Warn("cannot list user profiles", "error", err)
+		}
+		config.profiles = profiles
+		for _, path := range profileRoots(profiles, paths) {
+			roots = append(roots, config.scanRoot(path))
+			paths = append(paths, path)
+		}
+	}
+
+	config.errorLog = newErrorLog(config.maxErrors)
+	if !privileged {
+		slog.Warn("running without administrative privileges, " + estimateCoverage(paths).String())
+	}
+
+	if config.incremental {
+		state, err := openWalkState(config.statePath)
+		if err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+		defer state.Close()
+		state.full = config.fullScan
+		config.walkState = state
+	}
+
+	ctx, walkCtx := interrupts.ctx, interrupts.walkCtx
+	// the walk ends after the time budget, the executables found until then are still evaluated
+	if config.maxDuration > 0 {
+		var cancel context.CancelFunc
+		walkCtx, cancel = context.WithTimeout(walkCtx, config.maxDuration)
+		defer cancel()
+	}
+
+	// NDJSON on standard output is written while the scan goes on
+	if config.format == "ndjson" && config.streamable() {
+		config.stream = newNDJSONStream(os.Stdout, config)
 	}
 
-	logf("Start scanning (platform '%s') from path '%s'\n", runtime.GOOS, absPath)
-	finder := NewJavaFinder(absPath, config.maxDepth, config.evaluate)
 	startTime := time.Now()
-	results, err := finder.Find()
-	if err != nil {
-		logf("Error during search: %v\n", err)
-		os.Exit(1)
+	finders := make([]*JavaFinder, 0, len(roots))
+	for _, root := range roots {
+		slog.Info("Start scanning", "platform", runtime.GOOS, "path", root.path)
+		finder, err := newScanFinder(ctx, walkCtx, config, root)
+		if err != nil {
+			slog.Error(err.Error())
+			return 1
+		}
+		finders = append(finders, finder)
 	}
 
-	if finder.ticker.Load() {
-		// start newline if ticker was shown
-		logf("\n")
+	// the roots are scanned concurrently
+	progress := newProgressReporter(finders, config.progressInterval, config.progressFormat)
+	if logEnabled(slog.LevelInfo) {
+		progress.run()
+	}
+	scans := findRoots(finders)
+	progress.stop()
+	if ctx.Err() != nil {
+		slog.Error("scan aborted")
+		return exitInterrupted
+	}
+	interrupts.stop()
+	results := mergeRootResults(scans)
+	scannedDirs := 0
+	for i, finder := range finders {
+		if err := scans[i].err; err != nil {
+			slog.Error("search failed", "path", finder.startPath, "error", err)
+			return 1
+		}
+		scannedDirs += int(finder.scanned.Load())
+		config.rootStats = append(config.rootStats, finder.rootStats(len(scans[i].results), scans[i].duration))
+		if finder.truncated && !config.truncated {
+			config.truncated = true
+			config.lastScannedPath = finder.lastScannedPath()
+		}
+		config.installers = append(config.installers, finder.installers...)
+		config.suspected = append(config.suspected, finder.suspected...)
+		config.applications = append(config.applications, finder.applications...)
+	}
+	config.suspected = sortSuspected(config.suspected)
+	config.applications = sortApplications(config.applications)
+	config.interrupted = config.truncated && interrupts.interrupted.Load()
+	if config.interrupted {
+		slog.Warn("scan interrupted, the report is incomplete", "last_scanned_path", config.lastScannedPath)
+	} else if config.truncated {
+		slog.Warn("scan stopped after the maximum duration, the report is incomplete", "max_duration", config.maxDuration, "last_scanned_path", config.lastScannedPath)
+	}
+	// the state of a truncated walk lacks the directories not walked
+	if config.walkState != nil && !config.truncated {
+		slog.Info("Reused unchanged directories of the last scan", "directories", config.walkState.reused.Load())
+		if err := config.walkState.save(paths); err != nil {
+			slog.Warn("cannot save the scan state", "error", err)
+		}
+	}
+	if config.ownerScope != nil && config.ownerScope.skipped.Load() > 0 {
+		slog.Info("Skipped directories owned by other users", "directories", config.ownerScope.skipped.Load())
+	}
+	if config.findInstallers {
+		// download and temporary directories outside the scanned tree are searched as well
+		for _, dir := range installerDirs(userHomes(config.profiles)) {
+			if !isBelowAny(dir, paths) {
+				config.installers = append(config.installers, findInstallersIn(dir)...)
+			}
+		}
+		config.installers = mergeInstallers(config.installers)
+	}
+	markDefaultRuntime(results)
+	attributeApplications(config.applications, results)
+	config.legacy = findLegacyComponents(results, userHomes(config.profiles))
+	attachDeploymentRisks(config.legacy, results)
+	attributeProfiles(config.profiles, results)
+	if config.processes {
+		processes, err := listJavaProcesses()
+		if err != nil {
+			slog.Warn("cannot list processes", "error", err)
+		}
+		attachProcesses(processes, results)
+	}
+	if config.baseline != nil {
+		var summary BaselineSummary
+		results, config.baselineMissing, summary = compareBaseline(config.baseline, results, time.Now())
+		config.baselineSummary = &summary
+		slog.Info("Baseline compared", "approved", summary.Approved, "deviations", summary.Deviations, "missing", summary.Missing)
+	}
+	if len(config.policies) > 0 {
+		config.policyViolating = evaluatePolicies(config.policies, results, config.evaluate)
 	}
 
 	if config.jsonOutput {
-		if err := handleJSONOutput(results, finder, config, startTime); err != nil {
-			logf("Error: %v\n", err)
-			os.Exit(1)
+		if err := handleJSONOutput(results, scannedDirs, config, startTime); err != nil {
+			slog.Error(err.Error())
+			return 1
 		}
 	} else {
 		handleRegularOutput(results, config)
 	}
+	if config.interrupted {
+		return exitInterrupted
+	}
+	return exitCode(config.exitCodes, scanFindings(results, config))
+}
+
+// newScanFinder creates the finder for a scan root with the configured inspections and concurrency
+func newScanFinder(ctx, walkCtx context.Context, config config, root scanRoot) (*JavaFinder, error) {
+	finder := NewJavaFinder(root.path, root.maxDepth, config.evaluate)
+	finder.ctx = ctx
+	finder.walkCtx = walkCtx
+	finder.inspectCacerts = config.inspectCacerts
+	finder.inspectSecurity = config.inspectSecurity
+	finder.evalArgs = config.evalArgs
+	finder.evalTimeout = config.evalTimeout
+	finder.owners = config.ownerScope
+	finder.state = config.walkState
+	finder.index = config.index
+	finder.excludes = root.excludes
+	finder.quick = root.profile == profileQuick
+	finder.homes = userHomes(config.profiles)
+	finder.indexOnly = config.indexOnly
+	finder.findInstallers = config.findInstallers
+	finder.errors = config.errorLog
+	if config.stream != nil {
+		finder.onResult = config.stream.emit
+	}
+	if config.oneFileSystem || !config.includeNetworkFS {
+		if mounts, err := listMounts(); err == nil {
+			finder.skipMounts = mountSkips(mounts, root.path, config.allowMounts, config.oneFileSystem)
+		} else if config.oneFileSystem {
+			slog.Warn("--one-file-system is not supported, the mount table cannot be read", "error", err)
+		}
+	}
+	if root.threads != "" {
+		threads, err := resolveThreads(root.threads, root.path)
+		if err != nil {
+			return nil, err
+		}
+		finder.walkers = threads.walkers
+		finder.evaluators = threads.evaluators
+	}
+	if config.evaluate && config.evalWorkers > 0 {
+		finder.evaluators = config.evalWorkers
+	}
+	return finder, nil
 }
 
 func parseFlags() config {
@@ -81,40 +419,351 @@ func parseFlags() config {
 
 	// Set custom usage message
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s -path <search_path> [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s -path <search_path> [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s -smb //server/share[/path] [-smb-credentials <file>] [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s watch [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s k8s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s eval [options] <java_executable>... | --paths-from <file|->\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s rules show | check --vendor <vendor> --version <version>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s doctor [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s verify -key <public_key.pem> <report.json>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s baseline [-o <baseline.json>] <report.json|->\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s report [-html | -format <format>] [-o <file>] <report.json|->\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s schema\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s serve [-listen <address>] [-db <scans.db>] [-tls-cert <cert.pem> -tls-key <key.pem> [-tls-ca <ca.pem>]]\n\n", os.Args[0])
 		fmt.Fprintln(os.Stderr, "Options:")
 		flag.PrintDefaults()
 	}
 
 	// Define flags
-	flag.StringVar(&config.startPath, "path", "", "Starting path for search (required)")
+	flag.Var(&config.paths, "path", "Starting path for search, can be repeated or comma-separated for a merged scan of several paths (required)")
 	flag.IntVar(&config.maxDepth, "depth", -1, "Maximum depth to search (-1 for unlimited)")
 	flag.BoolVar(&config.evaluate, "eval", false, "Retrieve properties with '-XshowSettings:properties) and analyze them")
 	flag.BoolVar(&config.jsonOutput, "json", false, "Output results in JSON format")
+	flag.StringVar(&config.format, "format", "", "Output format: "+formatNames()+" (default: text, or json with --json)")
+	flag.StringVar(&config.template, "template", "", "Go template of -format template, executed for every runtime with the fields of a runtime, e.g. '{{.JavaExecutable}},{{.JavaVersion}}' (implies -format template)")
+	flag.BoolVar(&config.wide, "wide", false, "Add the distribution, edition, release date, architecture and license details to the columns of -format table")
 	flag.BoolVar(&config.doPost, "post", false, "Post JSON output to server (implies --json)")
-	flag.StringVar(&config.postURL, "url", defaultPostURL, "URL to post JSON output to (only used with --post)")
+	flag.StringVar(&config.outputPath, "output", "", "Write the report to this file instead of standard output (implies --json), replaced atomically and gzip compressed if the name ends with .gz; a .db, .sqlite or .sqlite3 file receives the results as SQLite database")
+	flag.StringVar(&config.outputPath, "o", "", "Short for -output")
+	flag.IntVar(&config.split, "split", 0, "Split the report into parts of at most N runtimes, written to numbered files with --output, all sharing the scan_id of the meta split section")
+	flag.StringVar(&config.postURL, "url", defaultPostURL, "URL to post JSON output to, grpc://host:port or grpcs://host:port submits it to a gRPC collector (only used with --post)")
+	flag.StringVar(&config.collectorTLS.cert, "tls-cert", "", "Client certificate (PEM) for mutual TLS with a grpcs:// collector")
+	flag.StringVar(&config.collectorTLS.key, "tls-key", "", "Key of the client certificate (PEM)")
+	flag.StringVar(&config.collectorTLS.ca, "tls-ca", "", "CA certificates (PEM) verifying a grpcs:// collector (default: system roots)")
+	flag.StringVar(&config.uploadChunk, "upload-chunk-size", "", "Post the report as resumable upload in chunks of this size, e.g. 4MiB (only used with --post)")
 	flag.BoolVar(&config.requireLicense, "require-license", false, "Filter only Java runtimes that require a commercial license")
+	flag.IntVar(&config.minMajor, "min-major", 0, "Report only runtimes of this major version or later, e.g. 11 (requires --eval)")
+	flag.IntVar(&config.maxMajor, "max-major", 0, "Report only runtimes of this major version or earlier, e.g. 8 (requires --eval)")
+	flag.StringVar(&config.versionRange, "version-range", "", "Report only runtimes whose version satisfies all constraints, e.g. '>=8 <17', '17' or '<1.8.0_401' (requires --eval)")
+	flag.Var(&config.failOn, "fail-on", "Exit with code 2 if the scan finds runtimes of a category: license-required, oracle, exec-failed or any-found, comma separated or repeated (see Exit Codes)")
+	flag.Var(&config.vendors, "vendor", "Report only runtimes whose vendor or distribution matches this case insensitive substring or regular expression, e.g. oracle or temurin, can be repeated (requires --eval)")
+	flag.BoolVar(&config.onlyExecFailed, "only-exec-failed", false, "Report only runtimes whose java -version could not be executed, e.g. broken installations or binaries of another architecture (requires --eval)")
+	flag.BoolVar(&config.listModules, "modules", false, "Include the full module list of Java 9+ runtimes in JSON output (requires --eval)")
+	flag.BoolVar(&config.inspectCacerts, "cacerts", false, "Inspect the cacerts trust store of evaluated runtimes (requires --eval)")
+	flag.BoolVar(&config.inspectSecurity, "security", false, "Inspect the java.security configuration of evaluated runtimes (requires --eval)")
+	flag.Var(&config.evalArgs, "eval-arg", "JVM option added to the evaluation of each java, e.g. -XX:-UsePerfData or -Djava.awt.headless=true, can be repeated")
+	flag.StringVar(&config.signKey, "sign", "", "Sign the JSON output with this Ed25519 private key (PEM), implies --json")
+	flag.StringVar(&config.signatureFile, "signature", "", "File to write the detached signature to (required with --sign)")
+	flag.Var(&config.encryptTo, "encrypt-to", "Encrypt the JSON output for this age recipient or recipients file, can be repeated")
+	flag.StringVar(&config.pseudonymKey, "pseudonymize", "", "Replace user, computer and home directory names in JSON output with pseudonyms keyed by the secret in this file")
+	flag.Var(&config.redact, "redact", "Redaction rule 'REGEX=>REPLACEMENT' applied to all emitted paths, can be repeated")
+	flag.StringVar(&config.redactFile, "redact-file", "", "File with redaction rules, one per line")
+	flag.StringVar(&config.smb, "smb", "", "SMB share to mount read-only and scan, //server/share[/path] (Linux and macOS)")
+	flag.StringVar(&config.smbCredentials, "smb-credentials", "", "File with username=, password= and optional domain= lines for --smb (default: guest access)")
+	flag.DurationVar(&config.shareTimeout, "share-timeout", 30*time.Second, "Timeout for reaching the scan path or mounting a share")
+	flag.StringVar(&config.profile, "profile", "", "Scan profile: quick runs only the targeted detectors (well-known directories, tool caches, installer registrations, PATH, JAVA_HOME) without walking the file system, or a profile of the configuration file; -path defaults to the file system root")
+	flag.StringVar(&config.configPath, "config", "", "Configuration file (YAML, or TOML with the extension .toml) with default settings of the flags and scan profiles (default: jfind.yaml or jfind.toml in /etc/jfind, then in jfind of the user configuration directory)")
+	flag.Var(&config.excludes, "exclude", "Directory not to walk, a name pattern like node_modules or a path pattern like /home/*/.cache, can be repeated")
+	flag.BoolVar(&config.quick, "quick", false, "Check only the well-known JDK locations of the platform for a near-instant inventory, short for -profile quick")
+	flag.BoolVar(&config.allUsers, "all-users", false, "Also scan the profile directories of all users and attribute findings to the owning account")
+	flag.StringVar(&config.index, "index", "", "Query a file index for java executables before the walk: "+indexNames())
+	flag.BoolVar(&config.indexOnly, "index-only", false, "Report only the executables known to the file index, skip the file system walk (requires --index)")
+	flag.BoolVar(&config.oneFileSystem, "one-file-system", false, "Do not descend into file systems mounted below the scan root (like find -xdev), allowlisted mounts are walked")
+	flag.BoolVar(&config.includeNetworkFS, "include-network-fs", false, "Walk the network file systems (NFS, SMB/CIFS, AFP, autofs, ...) mounted below the scan root, which are skipped by default")
+	flag.Var(&config.allowMounts, "allow-mount", "Network file system to scan although network mounts are skipped, by mount point or device, can be repeated")
+	flag.BoolVar(&config.processes, "processes", false, "Link running java processes to their runtime and detect Oracle JDK 8 commercial feature usage")
+	flag.BoolVar(&config.findInstallers, "installers", false, "Report downloaded JDK installers and archives in the scanned tree, Downloads and temporary directories")
+	flag.DurationVar(&config.maxDuration, "max-duration", 0, "Time budget of the scan, the walk stops when it is exhausted and the runtimes found so far are reported with meta.truncated (default: no limit)")
+	flag.BoolVar(&config.incremental, "incremental", false, "Do not read directories again that are unchanged since the last incremental scan")
+	flag.BoolVar(&config.fullScan, "full", false, "Read every directory like a cold scan and rebuild the state of incremental scans (requires --incremental)")
+	flag.StringVar(&config.statePath, "state", defaultStatePath(), "State database of incremental scans")
+	flag.BoolVar(&config.skipForeign, "skip-foreign", false, "Skip directories owned by other users than the scanning user (directories of root are walked)")
+	flag.Var(&config.owners, "owner", "Walk only directories owned by this user or service account (and root), can be repeated")
+	flag.StringVar(&config.threads, "threads", "", "Concurrency of walking and evaluation: auto or a number (default: sequential scan)")
+	flag.DurationVar(&config.evalTimeout, "eval-timeout", defaultEvaluationTimeout, "Time after which a hanging evaluation is killed, the runtime is reported with exec_failed")
+	flag.IntVar(&config.evalWorkers, "eval-workers", 0, "Number of java executables evaluated concurrently while the walk goes on (default: per --threads, otherwise 1)")
+	flag.StringVar(&config.baselinePath, "baseline", "", "Report only the deviations from the approved runtimes of this baseline file (see jfind baseline)")
+	flag.StringVar(&config.policyPath, "policy", "", "Policy file with deny expressions on the runtimes, violations are reported per runtime and exit with code 2 (see exit_codes of the configuration file)")
+	flag.BoolVar(&config.duplicates, "duplicates", false, "Flag installations identical to another installation and report the disk savings")
+	flag.BoolVar(&config.reproducible, "reproducible", false, "Omit volatile fields and sort the report, so scans of an unchanged host produce identical reports")
+	flag.BoolVar(&config.errorDetails, "error-details", false, "Include the paths skipped because of errors in the JSON output (sampled, see --max-errors)")
+	flag.IntVar(&config.maxErrors, "max-errors", defaultMaxErrors, "Maximum number of error records in the JSON output, larger scans are sampled")
+	flag.DurationVar(&config.progressInterval, "progress-interval", 0, "Interval of the progress report (default: 1s updated in place on a terminal, otherwise 30s as log lines)")
+	flag.StringVar(&config.progressTemplate, "progress-format", "", "Template of the progress report with .Scanned, .Found, .Errors, .Roots, .Elapsed, .Path (directory walked last) and .Last (java found last)")
+	flag.BoolVar(&config.quiet, "q", false, "Quiet, write only the output and warnings or errors, without banner, progress and messages of the scan (short for --log-level warn)")
+	flag.BoolVar(&config.quiet, "quiet", false, "Quiet, write only the output and warnings or errors, without banner, progress and messages of the scan (short for --log-level warn)")
+	config.logs.register(flag.CommandLine)
+	flag.BoolVar(&config.requireAdmin, "require-admin", false, "Abort unless running as root or from an elevated prompt, unprivileged scans are incomplete")
 	flag.BoolVar(&config.showRules, "show-rules", false, "Display license check rules and exit")
 	flag.BoolVar(&config.help, "h", false, "Show help message")
 	flag.BoolVar(&config.help, "help", false, "Show help message")
 
 	flag.Parse()
 
+	// precedence: command line, JFIND_* environment variables, configuration file
+	if err := applyEnv(flag.CommandLine); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	configFile, err := loadConfigFile(config.configPath)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	// the profile selected by the configuration file applies unless one is given on the command line
+	selected := false
+	flag.Visit(func(f *flag.Flag) { selected = selected || f.Name == "profile" || f.Name == "quick" })
+	if !selected {
+		if err := configFile.applySettings(flag.CommandLine, "profile", "quick"); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+	if config.profile, err = quickProfile(config.profile, config.quick); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	if config.profile != "" {
+		if err := configFile.applyProfile(flag.CommandLine, config.profile); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+	// the settings of the configuration file apply to the flags set neither on the command line nor by the profile
+	if err := configFile.applySettings(flag.CommandLine); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	levelSet := false
+	flag.Visit(func(f *flag.Flag) { levelSet = levelSet || f.Name == "log-level" })
+	if config.quiet && !levelSet {
+		config.logs.level = "warn"
+	}
+	if err := config.logs.setup(os.Stderr); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	// several paths are scanned as roots of one scan, without a path the roots of the configuration file are scanned
+	paths := splitPathList(config.paths)
+	if len(paths) == 1 {
+		config.startPath = paths[0]
+	}
+	if len(paths) > 1 {
+		for _, path := range paths {
+			config.roots = append(config.roots, config.scanRoot(path))
+		}
+	}
+	noPath := len(paths) == 0 && config.smb == ""
+	if noPath && configFile != nil && len(configFile.Roots) > 0 {
+		roots, err := configFile.scanRoots(config.scanRoot(""))
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		config.roots = roots
+	} else if noPath && config.profile != "" {
+		config.startPath = defaultScanRoot()
+	}
+
+	var exitCodes map[string]int
+	if configFile != nil {
+		exitCodes = configFile.ExitCodes
+	}
+	var failOn []string
+	for _, categories := range config.failOn {
+		failOn = append(failOn, strings.Split(categories, ",")...)
+	}
+	if config.exitCodes, err = resolveExitCodes(exitCodes, failOn); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
 	// Show help if requested or if path is not provided
-	if config.help || config.startPath == "" {
+	if config.help || (config.startPath == "" && config.smb == "" && len(config.roots) == 0 && !config.showRules) {
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	// If posting is enabled, we need JSON output
-	if config.doPost {
+	// If posting, signing or encryption is enabled, we need JSON output
+	if config.doPost || config.signKey != "" || len(config.encryptTo) > 0 || config.outputPath != "" {
 		config.jsonOutput = true
 	}
 
+	// SQLite output is a database instead of a rendered report, it includes the error records
+	if isSQLiteOutput(config.outputPath) {
+		if !sqliteSupported {
+			slog.Error("SQLite output requires a build with cgo enabled, this jfind was built without cgo")
+			os.Exit(1)
+		}
+		if config.doPost || config.signKey != "" || len(config.encryptTo) > 0 || (config.format != "" && config.format != "json") {
+			slog.Error("SQLite output cannot be combined with --post, --sign, --encrypt-to or --format")
+			os.Exit(1)
+		}
+		config.errorDetails = true
+	}
+
+	if config.split < 0 {
+		slog.Error("--split must be a positive number of runtimes")
+		os.Exit(1)
+	}
+	// the collectors store every posted report as a scan of its own, they do not reassemble the parts
+	if config.split > 0 && config.doPost {
+		slog.Error("--split cannot be combined with --post, the collector stores each part as a separate scan")
+		os.Exit(1)
+	}
+	if config.split > 0 && (config.outputPath == "" || isSQLiteOutput(config.outputPath)) {
+		slog.Error("--split requires --output with a report format")
+		os.Exit(1)
+	}
+	if err := validateEvalTimeout(config.evalTimeout); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	if config.maxDuration < 0 {
+		slog.Error("--max-duration must be a positive duration")
+		os.Exit(1)
+	}
+	if config.evalWorkers < 0 {
+		slog.Error("--eval-workers must be a positive number")
+		os.Exit(1)
+	}
+	if config.uploadChunk != "" {
+		size, err := humanize.ParseBytes(config.uploadChunk)
+		if err != nil || size == 0 || size > math.MaxInt32 {
+			slog.Error("invalid upload chunk size", "size", config.uploadChunk)
+			os.Exit(1)
+		}
+		config.uploadChunkSize = int(size)
+	}
+	if config.doPost && isGRPCURL(config.postURL) && (len(config.encryptTo) > 0 || config.uploadChunk != "" || (config.format != "" && config.format != "json")) {
+		slog.Error("a gRPC collector receives JSON reports, it cannot be combined with --encrypt-to, --upload-chunk-size or --format")
+		os.Exit(1)
+	}
+
+	if config.template != "" {
+		if config.format == "" {
+			config.format = "template"
+		}
+		if config.format != "template" {
+			slog.Error("--template requires --format template")
+			os.Exit(1)
+		}
+		if config.outputTemplate, err = parseOutputTemplate(config.template); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	} else if config.format == "template" {
+		slog.Error("--format template requires --template")
+		os.Exit(1)
+	}
+	filters := filterFlags{requireLicense: config.requireLicense, vendors: config.vendors, minMajor: config.minMajor, maxMajor: config.maxMajor, versionRange: config.versionRange, execFailed: config.onlyExecFailed}
+	if filters.requireEvaluation() && !config.evaluate {
+		slog.Error("--vendor, --min-major, --max-major, --version-range and --only-exec-failed require --eval, the filters use the results of the evaluation")
+		os.Exit(1)
+	}
+	if config.filter, err = newRuntimeFilter(filters); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	if config.wide && config.format != "table" {
+		slog.Error("--wide requires --format table")
+		os.Exit(1)
+	}
+
+	// Formats other than text are structured reports, handled like JSON output
+	if config.format != "" && config.format != "text" {
+		if _, ok := outputFormats[config.format]; !ok {
+			slog.Error("unknown output format", "format", config.format, "supported", formatNames())
+			os.Exit(1)
+		}
+		config.jsonOutput = true
+	}
+
+	for _, pattern := range config.excludes {
+		if err := validateExclude(pattern); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	for _, arg := range config.evalArgs {
+		if err := validateEvalArg(arg); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	ownerScope, err := newOwnerScope(config.skipForeign, config.owners)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	config.ownerScope = ownerScope
+
+	if config.index != "" {
+		if err := validateIndex(config.index); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	} else if config.indexOnly {
+		slog.Error("--index-only requires --index")
+		os.Exit(1)
+	}
+	if config.fullScan && !config.incremental {
+		slog.Error("--full requires --incremental")
+		os.Exit(1)
+	}
+
+	if config.progressFormat, err = parseProgressFormat(config.progressTemplate); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if config.baselinePath != "" {
+		baseline, err := loadBaseline(config.baselinePath)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		config.baseline = baseline
+	}
+
+	if config.policyPath != "" {
+		policies, err := loadPolicies(config.policyPath)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		config.policies = policies
+	}
+
+	redactor, err := loadRedactor(config.redact, config.redactFile)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	config.redactor = redactor
+
+	if config.signKey != "" && config.signatureFile == "" {
+		slog.Error("--sign requires --signature")
+		os.Exit(1)
+	}
+
 	return config
 }
 
-func createMetaInfo(startPath string, results []*JavaResult, finder *JavaFinder, startTime time.Time) MetaInfo {
+func createMetaInfo(startPath string, results []*JavaResult, scannedDirs int, startTime time.Time) MetaInfo {
 	currentUser, _ := user.Current()
 	username := "unknown"
 	if currentUser != nil {
@@ -130,16 +779,57 @@ func createMetaInfo(startPath string, results []*JavaResult, finder *JavaFinder,
 		HasOracleJDK:        false, // Will be updated later
 		CountResult:         len(results),
 		CountRequireLicense: 0, // Will be updated later
-		ScannedDirs:         int(finder.scanned.Load()),
+		ScannedDirs:         scannedDirs,
 		ScanPath:            startPath,
 		PlatformInfo:        getPlatformInfo(),
+		Privileged:          isPrivileged(),
 	}
 }
 
-func handleJSONOutput(results []*JavaResult, finder *JavaFinder, config config, startTime time.Time) error {
+func handleJSONOutput(results []*JavaResult, scannedDirs int, config config, startTime time.Time) error {
+	results = filterResults(results, config)
 	output := JSONOutput{
-		Meta:     createMetaInfo(config.startPath, results, finder, startTime),
-		Runtimes: make([]JavaRuntimeJSON, 0, len(results)),
+		Meta:         createMetaInfo(config.startPath, results, scannedDirs, startTime),
+		Runtimes:     make([]JavaRuntimeJSON, 0, len(results)),
+		Profiles:     config.profiles,
+		Installers:   config.installers,
+		Suspected:    config.suspected,
+		Applications: config.applications,
+		Legacy:       config.legacy,
+	}
+	output.Meta.ErrorCounts = config.errorLog.classCounts()
+	output.Meta.ScanProfile = config.profile
+	output.Meta.Baseline = config.baselineSummary
+	output.BaselineMissing = config.baselineMissing
+	output.Meta.PolicyViolations = config.policyViolating
+	output.Meta.Truncated = config.truncated
+	output.Meta.Interrupted = config.interrupted
+	output.Meta.LastScannedPath = config.lastScannedPath
+	if len(config.rootStats) > 1 {
+		output.Meta.Roots = config.rootStats
+	}
+	if config.errorDetails {
+		output.Errors = config.errorLog.records()
+	}
+
+	if config.duplicates {
+		output.Meta.DuplicateSavings = markDuplicates(results)
+	}
+	if config.hostEvidence {
+		output.Entitlements = detectEntitlementEvidence(results, userHomes(config.profiles))
+		if programs, ok := detectInstalledPrograms(); ok {
+			correlateInstalledPrograms(programs, results)
+			output.Programs = programs
+		}
+	}
+
+	formatName := config.format
+	if formatName == "" || formatName == "text" {
+		formatName = "json"
+	}
+	format := outputFormats[formatName]
+	if format.hostIdentifiers {
+		output.Meta.Host = collectHostIdentifiers()
 	}
 
 	hasOracle := false
@@ -147,12 +837,7 @@ func handleJSONOutput(results []*JavaResult, finder *JavaFinder, config config,
 
 	// Process each result
 	for _, result := range results {
-		runtime := createRuntimeJSON(result, config.evaluate)
-
-		if config.requireLicense && (runtime.RequireLicense == nil || !*runtime.RequireLicense) {
-			continue
-		}
-
+		runtime := reportRuntime(result, config)
 		if runtime.IsOracle {
 			hasOracle = true
 		}
@@ -167,41 +852,256 @@ func handleJSONOutput(results []*JavaResult, finder *JavaFinder, config config,
 	// Update meta information
 	output.Meta.HasOracleJDK = hasOracle
 	output.Meta.CountRequireLicense = countRequireLicense
+	output.Meta.HasEntitlement = hasSubscriptionEvidence(output.Entitlements)
+
+	if err := prepareReport(&output, config); err != nil {
+		return err
+	}
+	if config.stream != nil {
+		return config.stream.finish(&output)
+	}
 
-	// Convert to JSON
-	jsonData, err := json.MarshalIndent(output, "", "  ")
+	if isSQLiteOutput(config.outputPath) {
+		scanID, err := writeSQLite(config.outputPath, &output)
+		if err != nil {
+			return fmt.Errorf("error writing SQLite output: %v", err)
+		}
+		slog.Info("Scan written", "scan_id", scanID, "path", config.outputPath)
+		return nil
+	}
+
+	parts := []*JSONOutput{&output}
+	if config.split > 0 {
+		// the parts of a reproducible report are identical on every run of the same scan
+		scanID := newScanID()
+		if config.reproducible {
+			scanID = reproducibleScanID(&output)
+		}
+		parts = splitOutput(&output, config.split, scanID)
+	}
+	for _, part := range parts {
+		if err := deliverReport(part, formatName, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportRuntime returns the report entry of a result with the configured details
+func reportRuntime(result *JavaResult, config config) JavaRuntimeJSON {
+	runtime := createRuntimeJSON(result, config.evaluate)
+	if !config.listModules {
+		runtime.Modules = nil
+	}
+	if config.allProperties && result.Properties != nil {
+		runtime.Properties = ParseAllJavaProperties(result.StdErr)
+	}
+	return runtime
+}
+
+// prepareReport pseudonymizes, redacts and normalizes a report as configured before it is rendered
+func prepareReport(output *JSONOutput, config config) error {
+	if config.pseudonymKey != "" {
+		p, err := newPseudonymizer(config.pseudonymKey)
+		if err != nil {
+			return err
+		}
+		p.apply(output)
+	}
+	if len(config.redactor) > 0 {
+		output.rewritePaths(config.redactor.path)
+	}
+	if config.reproducible {
+		output.makeReproducible()
+	}
+	return nil
+}
+
+// renderOptions returns the options of the output formats
+func (c config) renderOptions() renderOptions {
+	return renderOptions{wide: c.wide, template: c.outputTemplate}
+}
+
+// deliverReport renders, signs and encrypts a report and writes it to the output file or standard output, or posts
+// it. The files of the parts of a split report are numbered.
+func deliverReport(output *JSONOutput, formatName string, config config) error {
+	format := outputFormats[formatName]
+	outputPath, signatureFile := config.outputPath, config.signatureFile
+	if split := output.Meta.Split; split != nil {
+		if outputPath != "" {
+			outputPath = partPath(outputPath, split.Part)
+		}
+		if signatureFile != "" {
+			signatureFile = partPath(signatureFile, split.Part)
+		}
+	}
+
+	// Convert to the output format
+	jsonData, err := format.render(output, config.renderOptions())
 	if err != nil {
-		return fmt.Errorf("error marshaling JSON: %v", err)
+		return fmt.Errorf("error rendering %s output: %v", formatName, err)
+	}
+
+	if config.signKey != "" {
+		if err := writeSignature(jsonData, config.signKey, signatureFile); err != nil {
+			return fmt.Errorf("error signing JSON: %v", err)
+		}
+	}
+
+	// Encrypt after signing, the signature covers the plain report
+	contentType := format.contentType
+	if len(config.encryptTo) > 0 {
+		if jsonData, err = encryptPayload(jsonData, config.encryptTo); err != nil {
+			return fmt.Errorf("error encrypting JSON: %v", err)
+		}
+		contentType = encryptedContentType
 	}
 
 	// Handle output
-	if config.doPost {
-		if err := sendJSON(jsonData, config.postURL); err != nil {
+	if config.doPost && isGRPCURL(config.postURL) {
+		if err := sendGRPC(output, config.postURL, config.collectorTLS); err != nil {
+			return fmt.Errorf("error submitting JSON: %v", err)
+		}
+	} else if config.doPost && config.uploadChunkSize > 0 {
+		if err := sendResumable(jsonData, config.postURL, contentType, config.uploadChunkSize); err != nil {
+			return fmt.Errorf("error uploading JSON: %v", err)
+		}
+	} else if config.doPost {
+		if err := sendPayload(jsonData, config.postURL, contentType); err != nil {
 			return fmt.Errorf("error sending JSON: %v", err)
 		}
+	} else if outputPath != "" {
+		if err := writeFileAtomic(outputPath, jsonData); err != nil {
+			return fmt.Errorf("error writing output: %v", err)
+		}
+	} else if format.binary && len(config.encryptTo) == 0 {
+		if _, err := os.Stdout.Write(jsonData); err != nil {
+			return fmt.Errorf("error writing output: %v", err)
+		}
 	} else {
-		fmt.Println(string(jsonData))
+		fmt.Println(strings.TrimRight(string(jsonData), "\n"))
 	}
 
 	return nil
 }
 
 func handleRegularOutput(results []*JavaResult, config config) {
+	results = filterResults(results, config)
+	var savings int64
+	if config.duplicates {
+		savings = markDuplicates(results)
+	}
+	var programs []InstalledProgram
+	if config.hostEvidence {
+		if detected, ok := detectInstalledPrograms(); ok {
+			correlateInstalledPrograms(detected, results)
+			programs = detected
+		}
+	}
+
 	for _, result := range results {
 		var runtime *JavaRuntimeJSON
-		if config.evaluate && result.Properties != nil && result.Error == nil && result.ReturnCode == 0 {
-			runtime = &JavaRuntimeJSON{
-				JavaExecutable: result.Path,
-				JavaVersion:    result.Properties.Version,
-				JavaVendor:     result.Properties.Vendor,
-				JavaRuntime:    result.Properties.RuntimeName,
-				IsOracle:       strings.Contains(result.Properties.Vendor, "Oracle"),
-				VersionMajor:   result.Properties.Major,
-				VersionUpdate:  result.Properties.Update,
+		if config.evaluate && result.Evaluated {
+			evaluated := createRuntimeJSON(result, config.evaluate)
+			if config.allProperties && result.Properties != nil {
+				evaluated.Properties = ParseAllJavaProperties(result.StdErr)
+			}
+			runtime = &evaluated
+		}
+		if len(config.redactor) > 0 {
+			redacted := *result
+			redacted.Path = config.redactor.path(result.Path)
+			redacted.JavaHome = config.redactor.path(result.JavaHome)
+			redacted.DuplicateOf = config.redactor.path(result.DuplicateOf)
+			if result.Wine != nil {
+				wine := *result.Wine
+				wine.Prefix = config.redactor.path(wine.Prefix)
+				redacted.Wine = &wine
+			}
+			redacted.Symlinks = make([]string, len(result.Symlinks))
+			for i, link := range result.Symlinks {
+				redacted.Symlinks[i] = config.redactor.path(link)
+			}
+			redacted.HardLinks = make([]string, len(result.HardLinks))
+			for i, link := range result.HardLinks {
+				redacted.HardLinks[i] = config.redactor.path(link)
+			}
+			if result.DefaultPathEntry != "" {
+				redacted.DefaultPathEntry = config.redactor.path(result.DefaultPathEntry)
+			}
+			if result.Application != nil {
+				application := *result.Application
+				application.rewritePaths(config.redactor.path)
+				redacted.Application = &application
+			}
+			if result.Program != nil {
+				program := *result.Program
+				program.InstallLocation = config.redactor.path(program.InstallLocation)
+				redacted.Program = &program
+			}
+			result = &redacted
+			if runtime != nil {
+				runtime.rewritePaths(config.redactor.path)
 			}
-			runtime.checkLicenseRequirement()
 		}
 		printResult(result, runtime)
 		printf("\n")
 	}
+
+	if config.duplicates {
+		printDuplicateSummary(results, savings)
+	}
+
+	var evidence []EntitlementEvidence
+	if config.hostEvidence {
+		evidence = detectEntitlementEvidence(results, userHomes(config.profiles))
+	}
+	for i := range evidence {
+		evidence[i].rewritePaths(config.redactor.path)
+	}
+	printEntitlementEvidence(evidence)
+
+	for i := range programs {
+		programs[i].InstallLocation = config.redactor.path(programs[i].InstallLocation)
+	}
+	printStalePrograms(programs)
+	printUninspectedProfiles(config.profiles)
+
+	installers := make([]InstallerArtifact, len(config.installers))
+	for i, installer := range config.installers {
+		installer.Path = config.redactor.path(installer.Path)
+		installers[i] = installer
+	}
+	printInstallers(installers)
+
+	suspected := make([]SuspectedInstall, len(config.suspected))
+	for i, s := range config.suspected {
+		s.Path = config.redactor.path(s.Path)
+		suspected[i] = s
+	}
+	printSuspected(suspected)
+
+	applications := make([]JPackageApp, len(config.applications))
+	for i, app := range config.applications {
+		app.rewritePaths(config.redactor.path)
+		applications[i] = app
+	}
+	printApplications(applications)
+
+	legacy := make([]LegacyComponent, len(config.legacy))
+	for i, component := range config.legacy {
+		component.rewritePaths(config.redactor.path)
+		legacy[i] = component
+	}
+	printLegacyComponents(legacy)
+
+	missing := make([]BaselineEntry, len(config.baselineMissing))
+	for i, entry := range config.baselineMissing {
+		entry.Path = config.redactor.path(entry.Path)
+		entry.JavaHome = config.redactor.path(entry.JavaHome)
+		missing[i] = entry
+	}
+	printBaselineMissing(missing)
+	printPolicyViolations(config.policyViolating)
+	printErrorSummary(config.errorLog.classCounts())
 }
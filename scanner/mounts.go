@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// networkFileSystems are mount types which are slow or expensive to walk. autofs mount points mount their file
+// system, usually NFS home or project directories, only when the walk enters them, they are not in the mount table
+// beforehand.
+var networkFileSystems = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb3": true, "smbfs": true, "afpfs": true, "autofs": true,
+	"fuse.sshfs": true, "sshfs": true, "afs": true, "ceph": true, "glusterfs": true,
+	"fuse.glusterfs": true, "lustre": true, "webdav": true, "davfs": true, "9p": true,
+}
+
+// mountPoint is a mounted file system
+type mountPoint struct {
+	Device string
+	Path   string
+	FSType string
+}
+
+// listMounts returns the mounted file systems, from /proc/mounts on Linux and mount(8) on macOS
+func listMounts() ([]mountPoint, error) {
+	switch runtime.GOOS {
+	case "linux":
+		data, err := os.ReadFile("/proc/mounts")
+		if err != nil {
+			return nil, err
+		}
+		return parseProcMounts(string(data)), nil
+	case "darwin", "freebsd":
+		out, err := exec.Command("mount").Output()
+		if err != nil {
+			return nil, err
+		}
+		return parseBSDMounts(string(out)), nil
+	}
+	return nil, fmt.Errorf("not supported on %s", runtime.GOOS)
+}
+
+// parseProcMounts parses /proc/mounts lines: device mountpoint fstype options dump pass
+func parseProcMounts(content string) []mountPoint {
+	var mounts []mountPoint
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		// spaces in mount points are escaped as \040
+		mounts = append(mounts, mountPoint{Device: fields[0], Path: strings.ReplaceAll(fields[1], `\040`, " "), FSType: fields[2]})
+	}
+	return mounts
+}
+
+// parseBSDMounts parses mount(8) lines: device on mountpoint (fstype, options...)
+func parseBSDMounts(content string) []mountPoint {
+	var mounts []mountPoint
+	for _, line := range strings.Split(content, "\n") {
+		device, rest, ok := strings.Cut(line, " on ")
+		if !ok {
+			continue
+		}
+		idx := strings.LastIndex(rest, " (")
+		if idx == -1 {
+			continue
+		}
+		fsType, _, _ := strings.Cut(strings.TrimSuffix(rest[idx+2:], ")"), ",")
+		mounts = append(mounts, mountPoint{Device: device, Path: rest[:idx], FSType: strings.TrimSpace(fsType)})
+	}
+	return mounts
+}
+
+// isNetworkMount reports whether a mount is a network file system. An autofs mount point with a local file system
+// mounted on it, e.g. a removable disk or a local directory of an automount map, is not.
+func isNetworkMount(mounts []mountPoint, m mountPoint) bool {
+	if !networkFileSystems[m.FSType] {
+		return false
+	}
+	if m.FSType == "autofs" {
+		for _, other := range mounts {
+			if other.Path == m.Path && !networkFileSystems[other.FSType] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// networkMountsBelow returns the network file systems mounted at or below root
+func networkMountsBelow(mounts []mountPoint, root string) []mountPoint {
+	var result []mountPoint
+	for _, m := range mounts {
+		if isNetworkMount(mounts, m) && pathDepth(filepath.Clean(root), m.Path) >= 0 {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// mountSkips returns the file systems strictly below root which are not allowlisted, mapped to their type: the network
+// file systems, or with oneFileSystem (--one-file-system) every mount. Allowlist entries match the mount point or the
+// device, e.g. /mnt/apps, fs01:/export/apps or //fs01/apps. A scan root on a network file system is never skipped, it
+// was requested explicitly.
+func mountSkips(mounts []mountPoint, root string, allow []string, oneFileSystem bool) map[string]string {
+	allowed := make(map[string]bool)
+	for _, a := range allow {
+		allowed[filepath.Clean(a)] = true
+		allowed[a] = true
+	}
+
+	skips := make(map[string]string)
+	for _, m := range mounts {
+		if !oneFileSystem && !isNetworkMount(mounts, m) {
+			continue
+		}
+		if pathDepth(filepath.Clean(root), m.Path) <= 0 || allowed[m.Path] || allowed[m.Device] {
+			continue
+		}
+		skips[m.Path] = m.FSType
+	}
+	return skips
+}
+
+// mountOf returns the mount containing path, the one with the longest matching mount point. Of the mounts on the
+// same mount point the last one is on top, e.g. the file system mounted by autofs.
+func mountOf(mounts []mountPoint, path string) (mountPoint, bool) {
+	var best mountPoint
+	found := false
+	for _, m := range mounts {
+		if pathDepth(m.Path, path) < 0 {
+			continue
+		}
+		if !found || len(m.Path) >= len(best.Path) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
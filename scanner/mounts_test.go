@@ -0,0 +1,85 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseMounts(t *testing.T) {
+	proc := parseProcMounts(`/dev/sda1 / ext4 rw,relatime 0 0
+server:/export /mnt/my\040data nfs4 rw 0 0
+proc /proc proc rw 0 0
+`)
+	if len(proc) != 3 || proc[1].Path != "/mnt/my data" || proc[1].FSType != "nfs4" {
+		t.Errorf("unexpected /proc/mounts result %v", proc)
+	}
+
+	bsd := parseBSDMounts(`/dev/disk3s1s1 on / (apfs, sealed, local, read-only, journaled)
+//user@server/share on /Volumes/share (smbfs, nodev, nosuid, mounted by user)
+`)
+	if len(bsd) != 2 || bsd[1].Path != "/Volumes/share" || bsd[1].FSType != "smbfs" {
+		t.Errorf("unexpected mount(8) result %v", bsd)
+	}
+
+	network := networkMountsBelow(append(proc, bsd...), "/")
+	if len(network) != 2 {
+		t.Errorf("expected 2 network mounts below /, got %v", network)
+	}
+	if network := networkMountsBelow(proc, "/opt"); len(network) != 0 {
+		t.Errorf("expected no network mounts below /opt, got %v", network)
+	}
+}
+
+func TestMountSkips(t *testing.T) {
+	mounts := []mountPoint{
+		{Device: "/dev/sda1", Path: "/", FSType: "ext4"},
+		{Device: "proc", Path: "/proc", FSType: "proc"},
+		{Device: "/dev/sdb1", Path: "/data", FSType: "xfs"},
+		{Device: "fs01:/export/home", Path: "/home", FSType: "nfs4"},
+		{Device: "fs01:/export/apps", Path: "/mnt/apps", FSType: "nfs4"},
+		{Device: "//fs02/tools", Path: "/mnt/tools", FSType: "cifs"},
+		{Device: "auto.projects", Path: "/projects", FSType: "autofs"},
+		// a local disk mounted by autofs is walked
+		{Device: "auto.media", Path: "/media/usb", FSType: "autofs"},
+		{Device: "/dev/sdc1", Path: "/media/usb", FSType: "ext4"},
+	}
+
+	skips := mountSkips(mounts, "/", []string{"fs01:/export/apps", "/mnt/tools/"}, false)
+	if len(skips) != 2 || skips["/home"] != "nfs4" || skips["/projects"] != "autofs" {
+		t.Errorf("unexpected skips %v", skips)
+	}
+	if skips := mountSkips(mounts, "/home", nil, false); len(skips) != 0 {
+		t.Errorf("expected the scan root not to be skipped, got %v", skips)
+	}
+
+	// --one-file-system skips every mount below the root
+	skips = mountSkips(mounts, "/", []string{"/mnt/tools"}, true)
+	want := map[string]string{"/proc": "proc", "/data": "xfs", "/home": "nfs4", "/mnt/apps": "nfs4", "/projects": "autofs",
+		"/media/usb": "ext4"}
+	if !reflect.DeepEqual(skips, want) {
+		t.Errorf("unexpected skips with --one-file-system %v", skips)
+	}
+	if skips := mountSkips(mounts, "/data", nil, true); len(skips) != 0 {
+		t.Errorf("expected the scan root not to be skipped, got %v", skips)
+	}
+}
+
+func TestWalkSkipsNetworkMounts(t *testing.T) {
+	root := t.TempDir()
+	writeFakeJava(t, filepath.Join(root, "local", "bin"))
+	writeFakeJava(t, filepath.Join(root, "nfs", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.skipMounts = map[string]string{filepath.Join(root, "nfs"): "nfs4"}
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Path != filepath.Join(root, "local", "bin", "java") {
+			t.Errorf("threads %d: unexpected results %d", threads, len(results))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ndjsonSummary is the last line of an NDJSON report: the report without its runtimes, which precede it one per line
+type ndjsonSummary struct {
+	JSONOutput
+	Runtimes []JavaRuntimeJSON `json:"runtimes,omitempty"`
+}
+
+// renderNDJSON renders the runtimes of a report one per line, followed by the summary line with the meta section
+func renderNDJSON(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, runtime := range output.Runtimes {
+		if err := enc.Encode(runtime); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Encode(ndjsonSummary{JSONOutput: *output}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ndjsonStream writes the runtimes of an NDJSON report as soon as they are evaluated, before the scan is done.
+// Runtimes are streamed before the results of the roots and links are merged: a java executable reached through
+// several symlinks, hard links or roots is written once, with the path found first. The findings a complete report
+// attaches to its runtimes are attached to each runtime from the state of the host read before the walk.
+type ndjsonStream struct {
+	mu     sync.Mutex
+	w      io.Writer
+	config config
+	seen   map[string]bool
+	// files are the identities of the written executables
+	files []os.FileInfo
+	// defaultJava is the canonical path of the executable started by 'java', resolved before the walk
+	defaultJava  string
+	defaultEntry string
+	// hostLegacy are the Web Start and plugin components of the host, programs the installed programs correlated
+	// with the runtimes if detected
+	hostLegacy []LegacyComponent
+	programs   []InstalledProgram
+	correlate  bool
+	err        error
+}
+
+// newNDJSONStream creates a stream of the runtimes of a scan with config to w
+func newNDJSONStream(w io.Writer, config config) *ndjsonStream {
+	s := &ndjsonStream{w: w, config: config, seen: make(map[string]bool)}
+	if defaultJava, entry := resolveDefaultJava(); defaultJava != "" {
+		s.defaultJava, s.defaultEntry = canonicalPath(defaultJava), entry
+	}
+	s.hostLegacy = hostLegacyComponents(userHomes(config.profiles))
+	if config.hostEvidence {
+		s.programs, s.correlate = detectInstalledPrograms()
+	}
+	return s
+}
+
+// streamable reports whether the runtimes of a scan can be written before the scan is done. The options below set
+// fields of the runtimes once the whole scan is known, order the runtimes (-reproducible) or write the report
+// elsewhere than standard output.
+func (c config) streamable() bool {
+	return c.outputPath == "" && !c.doPost && c.signKey == "" && len(c.encryptTo) == 0 && c.baseline == nil &&
+		!c.processes && len(c.policies) == 0 && !c.duplicates && !c.allUsers && !c.reproducible
+}
+
+// emit writes the line of an evaluated runtime, it is safe for concurrent use
+func (s *ndjsonStream) emit(result *JavaResult) {
+	result = s.enrich(result)
+	runtime := reportRuntime(result, s.config)
+	if !s.config.filter.match(runtime) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[result.Path] || s.err != nil {
+		return
+	}
+	s.seen[result.Path] = true
+	if info, err := os.Stat(result.Path); err == nil {
+		for _, other := range s.files {
+			if os.SameFile(other, info) {
+				return
+			}
+		}
+		s.files = append(s.files, info)
+	}
+
+	// a runtime is pseudonymized and redacted like the runtimes of a complete report
+	output := JSONOutput{Runtimes: []JavaRuntimeJSON{runtime}}
+	if s.err = prepareReport(&output, s.config); s.err != nil {
+		return
+	}
+	s.err = json.NewEncoder(s.w).Encode(output.Runtimes[0])
+}
+
+// enrich returns a copy of a result with the findings a complete report attaches after the walk: the default
+// runtime, the bundling jpackage application, the deployment risks and the installed program
+func (s *ndjsonStream) enrich(result *JavaResult) *JavaResult {
+	enriched := *result
+	if s.defaultJava != "" && canonicalPath(result.Path) == s.defaultJava {
+		enriched.DefaultPathEntry = s.defaultEntry
+	}
+	if app, ok := bundlingApplication(&enriched); ok {
+		enriched.Application = &app
+	}
+	if enriched.JavaHome != "" {
+		attachDeploymentRisks(mergeLegacyComponents(runtimeLegacyComponents(enriched.JavaHome), s.hostLegacy), []*JavaResult{&enriched})
+	}
+	if s.correlate {
+		correlateInstalledPrograms(s.programs, []*JavaResult{&enriched})
+	}
+	return &enriched
+}
+
+// finish writes the summary line of the report once the scan is done
+func (s *ndjsonStream) finish(output *JSONOutput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return fmt.Errorf("error writing NDJSON output: %v", s.err)
+	}
+	if err := json.NewEncoder(s.w).Encode(ndjsonSummary{JSONOutput: *output}); err != nil {
+		return fmt.Errorf("error writing NDJSON output: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRenderNDJSON(t *testing.T) {
+	data, err := renderNDJSON(testOutput(), renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 runtime lines and the summary line, got %d lines", len(lines))
+	}
+
+	var runtime JavaRuntimeJSON
+	if err := json.Unmarshal([]byte(lines[0]), &runtime); err != nil || runtime.JavaExecutable != "/opt/jdk-17/bin/java" {
+		t.Errorf("unexpected runtime line %s: %v", lines[0], err)
+	}
+	var summary map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := summary["meta"]; !ok {
+		t.Errorf("expected the meta section in the summary line, got %s", lines[2])
+	}
+	if _, ok := summary["runtimes"]; ok {
+		t.Errorf("expected the summary line without runtimes, got %s", lines[2])
+	}
+}
+
+func TestNDJSONStream(t *testing.T) {
+	root := t.TempDir()
+	java := writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		var buf bytes.Buffer
+		rule, err := parseRedactionRule(regexp.QuoteMeta(root) + "=>/redacted")
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream := newNDJSONStream(&buf, config{redactor: redactor{rule}})
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.onResult = stream.emit
+		if _, err := finder.Find(); err != nil {
+			t.Fatal(err)
+		}
+		// the runtime is streamed before the report is done
+		if want := "/redacted/jdk-17/bin/java"; !strings.Contains(buf.String(), want) || strings.Contains(buf.String(), java) {
+			t.Errorf("threads %d: expected the redacted runtime to be streamed, got %s", threads, buf.String())
+		}
+
+		// a runtime found again, e.g. through a symlink, is written once
+		stream.emit(&JavaResult{Path: java})
+		if err := stream.finish(&JSONOutput{Meta: MetaInfo{CountResult: 1}}); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[1], `{"meta":`) {
+			t.Errorf("threads %d: expected a runtime and the summary line, got %q", threads, lines)
+		}
+	}
+
+	// runtimes not requiring a license are not streamed with --require-license
+	var buf bytes.Buffer
+	stream := newNDJSONStream(&buf, config{filter: runtimeFilter{requireLicense: true}})
+	stream.emit(&JavaResult{Path: java})
+	if buf.Len() != 0 {
+		t.Errorf("expected the runtime to be filtered, got %s", buf.String())
+	}
+}
+
+func TestNDJSONStreamLinks(t *testing.T) {
+	root := t.TempDir()
+	java := writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+	link := filepath.Join(root, "java")
+	if err := os.Link(java, link); err != nil {
+		t.Skip("hard links not supported:", err)
+	}
+	t.Setenv("PATH", filepath.Dir(java))
+
+	var buf bytes.Buffer
+	stream := newNDJSONStream(&buf, config{})
+	stream.emit(&JavaResult{Path: java})
+	stream.emit(&JavaResult{Path: link})
+
+	// the hard link is not written again, the runtime is marked as the default like in a complete report
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected the executable to be written once, got %q", lines)
+	}
+	var runtime JavaRuntimeJSON
+	if err := json.Unmarshal([]byte(lines[0]), &runtime); err != nil || !runtime.DefaultForUser {
+		t.Errorf("expected the runtime to be the default, got %s: %v", lines[0], err)
+	}
+}
+
+func TestNDJSONStreamEnrichment(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses the Linux deployment locations")
+	}
+	root := t.TempDir()
+	app := filepath.Join(root, "opt", "inventory")
+	writeTestFile(t, filepath.Join(app, "lib", "app", ".jpackage.xml"), testJPackageState)
+	writeFakeJava(t, filepath.Join(app, "lib", "runtime", "bin"))
+	jre := filepath.Join(root, "opt", "jre1.8.0_202")
+	writeTestFile(t, filepath.Join(jre, "bin", "javaws"), "#!/bin/sh\n")
+	writeFakeJava(t, filepath.Join(jre, "bin"))
+	home := filepath.Join(root, "home", "alice")
+	writeTestFile(t, filepath.Join(home, ".java", "deployment", "deployment.properties"), "deployment.security.level=MEDIUM\n")
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", filepath.Join(jre, "bin"))
+
+	// the streamed runtimes equal those of the report written when the scan is done
+	var buf bytes.Buffer
+	stream := newNDJSONStream(&buf, config{})
+	finder := NewJavaFinder(filepath.Join(root, "opt"), -1, false)
+	finder.onResult = stream.emit
+	results, err := finder.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	markDefaultRuntime(results)
+	attributeApplications(finder.applications, results)
+	attachDeploymentRisks(findLegacyComponents(results, userHomes(nil)), results)
+	output := JSONOutput{}
+	for _, result := range results {
+		output.Runtimes = append(output.Runtimes, reportRuntime(result, config{}))
+	}
+	data, err := renderNDJSON(&output, renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffered := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	buffered = buffered[:len(buffered)-1]
+	streamed := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	sort.Strings(buffered)
+	sort.Strings(streamed)
+	if !reflect.DeepEqual(streamed, buffered) {
+		t.Errorf("streamed runtimes differ from the report:\n%s\n%s", strings.Join(streamed, "\n"), strings.Join(buffered, "\n"))
+	}
+	for _, want := range []string{`"jpackage_application"`, `"deployment_risks"`, `"default_for_user":true`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %s in the streamed runtimes, got %s", want, buf.String())
+		}
+	}
+}
+
+func TestNDJSONStreamable(t *testing.T) {
+	if !(config{}).streamable() {
+		t.Error("expected a scan without options to be streamed")
+	}
+	for name, c := range map[string]config{
+		"processes":    {processes: true},
+		"policies":     {policies: []Policy{{}}},
+		"duplicates":   {duplicates: true},
+		"all-users":    {allUsers: true},
+		"output":       {outputPath: "report.ndjson"},
+		"reproducible": {reproducible: true},
+	} {
+		if c.streamable() {
+			t.Errorf("expected -%s to write the runtimes when the scan is done", name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Types of Oracle entitlement evidence
+const (
+	evidenceUsageTracker      = "usage-tracker"
+	evidenceJMSAgent          = "jms-agent"
+	evidenceAMCAgent          = "amc-agent"
+	evidenceDeploymentRuleSet = "deployment-rule-set"
+	evidenceInstallerConfig   = "enterprise-installer-config"
+)
+
+// EntitlementEvidence is a host level artifact indicating an Oracle Java SE subscription.
+// Runtimes requiring a license on hosts with evidence are likely covered and can be triaged differently.
+type EntitlementEvidence struct {
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// entitlementLocation is a well-known location of entitlement evidence
+type entitlementLocation struct {
+	evidenceType string
+	path         string
+}
+
+// entitlementLocations returns the well-known evidence locations for the current platform
+func entitlementLocations() []entitlementLocation {
+	switch runtime.GOOS {
+	case "windows":
+		programData := os.Getenv("ProgramData")
+		programFiles := os.Getenv("ProgramFiles")
+		systemRoot := os.Getenv("SystemRoot")
+		return []entitlementLocation{
+			{evidenceUsageTracker, filepath.Join(programData, "Oracle", "Java", "usagetracker.properties")},
+			{evidenceInstallerConfig, filepath.Join(programData, "Oracle", "Java", "java.settings.cfg")},
+			{evidenceDeploymentRuleSet, filepath.Join(systemRoot, "Sun", "Java", "Deployment", "DeploymentRuleSet.jar")},
+			{evidenceJMSAgent, `C:\Oracle\mgmt_agent`},
+			{evidenceAMCAgent, filepath.Join(programFiles, "Oracle", "Advanced Management Console")},
+		}
+	case "darwin":
+		return []entitlementLocation{
+			{evidenceUsageTracker, "/Library/Application Support/Oracle/Java/usagetracker.properties"},
+			{evidenceDeploymentRuleSet, "/Library/Application Support/Oracle/Java/Deployment/DeploymentRuleSet.jar"},
+			{evidenceJMSAgent, "/opt/oracle/mgmt_agent"},
+		}
+	default:
+		return []entitlementLocation{
+			{evidenceUsageTracker, "/etc/oracle/java/usagetracker.properties"},
+			{evidenceDeploymentRuleSet, "/etc/.java/deployment/DeploymentRuleSet.jar"},
+			{evidenceJMSAgent, "/opt/oracle/mgmt_agent"},
+			{evidenceAMCAgent, "/opt/oracle/amc"},
+		}
+	}
+}
+
+// detectEntitlementEvidence checks the well-known locations for Oracle entitlement evidence,
+// and the usage tracker artifacts of the discovered runtimes and user homes
+func detectEntitlementEvidence(results []*JavaResult, homes []string) []EntitlementEvidence {
+	var evidence []EntitlementEvidence
+	for _, location := range entitlementLocations() {
+		// skip locations based on undefined environment variables
+		if !filepath.IsAbs(location.path) {
+			continue
+		}
+		if _, err := os.Stat(location.path); err != nil {
+			continue
+		}
+		item := EntitlementEvidence{Type: location.evidenceType, Path: location.path}
+		if location.evidenceType == evidenceUsageTracker {
+			item.Detail = usageTrackerDetail(location.path)
+		}
+		evidence = append(evidence, item)
+	}
+	return append(evidence, usageTrackerArtifacts(evidence, results, homes)...)
+}
+
+// usageTrackerDetail summarizes where a usage tracker configuration sends its data
+func usageTrackerDetail(path string) string {
+	props, err := readPropertiesFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var details []string
+	for _, key := range []string{"com.oracle.usagetracker.logToServer", "com.oracle.usagetracker.logToFile"} {
+		if value := props[key]; value != "" {
+			details = append(details, fmt.Sprintf("%s=%s", strings.TrimPrefix(key, "com.oracle.usagetracker."), value))
+		}
+	}
+	return strings.Join(details, ";")
+}
+
+// printEntitlementEvidence prints the evidence found in text output mode
+func printEntitlementEvidence(evidence []EntitlementEvidence) {
+	if len(evidence) == 0 {
+		return
+	}
+	fmt.Printf("Oracle entitlement evidence:\n")
+	for _, item := range evidence {
+		if item.Detail != "" {
+			fmt.Printf("- %s: %s (%s)\n", item.Type, item.Path, item.Detail)
+		} else {
+			fmt.Printf("- %s: %s\n", item.Type, item.Path)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import "time"
+
+// License types of Oracle JDK public updates
+const (
+	licenseBCL  = "BCL"  // Binary Code License, free up to a final public update
+	licenseOTN  = "OTN"  // Oracle Technology Network License, commercial use requires a subscription
+	licenseNFTC = "NFTC" // No-Fee Terms and Conditions, free for a time window
+)
+
+// oracleRule describes the Oracle license terms of a Java SE feature release
+type oracleRule struct {
+	license        string
+	lastFreeUpdate int       // BCL only: last update which is free to use
+	freeUntil      time.Time // NFTC only: updates released after this date require a license, zero if none do
+}
+
+// oracleRuleFor returns the license rule of an Oracle JDK feature release
+func oracleRuleFor(major int) (oracleRule, bool) {
+	switch {
+	case major == 7:
+		return oracleRule{license: licenseBCL, lastFreeUpdate: 80}, true
+	case major == 8:
+		return oracleRule{license: licenseBCL, lastFreeUpdate: 202}, true
+	case major == 11:
+		return oracleRule{license: licenseOTN}, true
+	case major >= 17 && isLTS(major):
+		return oracleRule{license: licenseNFTC, freeUntil: nftcFreeUntil(major)}, true
+	case major >= 18:
+		// non-LTS releases only receive updates under NFTC until the next feature release
+		return oracleRule{license: licenseNFTC}, true
+	}
+	return oracleRule{}, false
+}
+
+// isLTS reports whether a feature release >= 17 is a long-term support release, LTS ship every two years
+func isLTS(major int) bool {
+	return major >= 17 && (major-17)%4 == 0
+}
+
+// ltsReleasesSince returns all LTS releases from first onwards that are generally available at now
+func ltsReleasesSince(first int, now time.Time) []int {
+	var releases []int
+	for major := first; !oracleGADate(major).After(now); major += 4 {
+		releases = append(releases, major)
+	}
+	return releases
+}
+
+// oracleGADate returns the GA month of a feature release >= 10.
+// Since JDK 10 (March 2018) a feature release ships every six months, in March and September.
+func oracleGADate(major int) time.Time {
+	return time.Date(2018, time.March+time.Month((major-10)*6), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// nftcFreeUntil returns the last day of the NFTC window of an LTS release,
+// which ends one year after the GA of the next LTS release
+func nftcFreeUntil(major int) time.Time {
+	return oracleGADate(major+4).AddDate(1, 1, -1)
+}
+
+// estimateUpdateDate returns the expected release date of an update.
+// Critical patch updates ship quarterly, the first one in the month after GA.
+func estimateUpdateDate(major, update int) time.Time {
+	ga := oracleGADate(major)
+	if update <= 0 {
+		return ga
+	}
+	return time.Date(ga.Year(), ga.Month()+1+time.Month(3*(update-1)), 15, 0, 0, 0, 0, time.UTC)
+}
+
+// firstLicensedUpdate returns the first update expected to be released after the NFTC window
+func firstLicensedUpdate(major int) int {
+	freeUntil := nftcFreeUntil(major)
+	update := 1
+	for !estimateUpdateDate(major, update).After(freeUntil) {
+		update++
+	}
+	return update
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isGzipOutput reports whether a report written to path is gzip compressed
+func isGzipOutput(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".gz")
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same directory, which is renamed to path once
+// it is complete: collectors picking up reports never read a partial file and a failed write keeps the previous
+// report. Data written to a .gz file is gzip compressed.
+// A replaced report keeps its mode, a new one is created with the default mode of the user's files (0666 less the
+// umask).
+func writeFileAtomic(path string, data []byte) error {
+	mode, replaced := os.FileMode(0o666), false
+	if info, err := os.Stat(path); err == nil {
+		mode, replaced = info.Mode().Perm(), true
+	}
+	tmp, err := createTemp(path, mode)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if isGzipOutput(path) {
+		gz := gzip.NewWriter(tmp)
+		gz.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if _, err = gz.Write(data); err == nil {
+			err = gz.Close()
+		}
+	} else {
+		_, err = tmp.Write(data)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	// the umask applies to the mode of the replaced report as well
+	if replaced {
+		if err := os.Chmod(tmp.Name(), mode); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// createTemp creates a new temporary file next to path with mode less the umask, unlike os.CreateTemp which
+// always uses 0600
+func createTemp(path string, mode os.FileMode) (*os.File, error) {
+	for attempt := 0; ; attempt++ {
+		name := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.%d.tmp", filepath.Base(path), rand.Uint32()))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, mode)
+		if os.IsExist(err) && attempt < 100 {
+			continue
+		}
+		return f, err
+	}
+}
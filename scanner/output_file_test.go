@@ -0,0 +1,74 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scan.json")
+	if err := os.WriteFile(path, []byte("previous"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// the umask may have cleared bits of the mode on creation
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte(`{"runtimes":[]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != `{"runtimes":[]}` {
+		t.Errorf("expected the report to replace the previous one, got %q: %v", data, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o640 && runtime.GOOS != "windows" {
+		t.Errorf("expected the mode of the replaced report 0640, got %v: %v", info.Mode(), err)
+	}
+
+	gzPath := filepath.Join(dir, "scan.json.gz")
+	if err := writeFileAtomic(gzPath, []byte(`{"runtimes":[]}`)); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(gzPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	// a new report gets the mode of other new files, 0666 less the umask
+	reference := filepath.Join(t.TempDir(), "reference")
+	if err := os.WriteFile(reference, nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.Stat(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != want.Mode().Perm() {
+		t.Errorf("expected the mode of a new file %v, got %v", want.Mode(), info.Mode())
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("expected a gzip file: %v", err)
+	}
+	if data, err := io.ReadAll(gz); err != nil || string(data) != `{"runtimes":[]}` || gz.Name != "scan.json" {
+		t.Errorf("unexpected compressed content %q (%s): %v", data, gz.Name, err)
+	}
+
+	// no temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 2 {
+		t.Errorf("expected only the reports in %s, got %d entries: %v", dir, len(entries), err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "scan.json"), nil); err == nil {
+		t.Errorf("expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"sync/atomic"
+)
+
+// ownerScope restricts the walk to directories owned by the allowed accounts. Directories owned by the system
+// (root, SYSTEM, Administrators, TrustedInstaller) are always walked, they hold the trees of all accounts.
+type ownerScope struct {
+	// allowed holds the user IDs of the allowed accounts, SIDs on Windows
+	allowed map[string]bool
+	// skipped counts the directories skipped because of their owner
+	skipped atomic.Int64
+}
+
+// newOwnerScope returns the scope allowing the scanning user with self and the named accounts,
+// nil if the walk is not restricted
+func newOwnerScope(self bool, names []string) (*ownerScope, error) {
+	if !self && len(names) == 0 {
+		return nil, nil
+	}
+
+	s := &ownerScope{allowed: make(map[string]bool)}
+	if self {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine the current user: %v", err)
+		}
+		s.allowed[current.Uid] = true
+	}
+	for _, name := range names {
+		account, err := user.Lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown owner '%s': %v", name, err)
+		}
+		s.allowed[account.Uid] = true
+	}
+	return s, nil
+}
+
+// allows reports whether the directory may be walked and counts the skipped directories
+func (s *ownerScope) allows(path string, info os.FileInfo) bool {
+	if s.permits(path, info) {
+		return true
+	}
+	s.skipped.Add(1)
+	return false
+}
+
+// permits reports whether the directory may be walked, directories whose owner cannot be determined are walked
+func (s *ownerScope) permits(path string, info os.FileInfo) bool {
+	if s == nil {
+		return true
+	}
+	owner, err := fileOwner(path, info)
+	return err != nil || s.allowed[owner] || isSystemOwner(owner)
+}
+
+// ownedByOthers reports whether a directory below the start path is excluded by the owner scope
+func (f *JavaFinder) ownedByOthers(path string, info os.FileInfo) bool {
+	return path != f.startPath && !f.owners.allows(path, info)
+}
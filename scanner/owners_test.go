@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWalkSkipsForeignOwners(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() != 0 {
+		t.Skip("changing the owner of directories requires root")
+	}
+
+	root := t.TempDir()
+	for dir, uid := range map[string]int{"alice": 1000, "bob": 2000, "system": 0} {
+		writeFakeJava(t, filepath.Join(root, dir, "bin"))
+		if err := os.Chown(filepath.Join(root, dir), uid, uid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.owners = &ownerScope{allowed: map[string]bool{"1000": true}}
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 2 || results[0].Path != filepath.Join(root, "alice", "bin", "java") ||
+			results[1].Path != filepath.Join(root, "system", "bin", "java") {
+			t.Errorf("threads %d: unexpected results %d", threads, len(results))
+		}
+		if skipped := finder.owners.skipped.Load(); skipped != 1 {
+			t.Errorf("threads %d: expected 1 skipped directory, got %d", threads, skipped)
+		}
+	}
+}
+
+func TestNewOwnerScope(t *testing.T) {
+	if scope, err := newOwnerScope(false, nil); scope != nil || err != nil {
+		t.Errorf("expected no scope, got %v, %v", scope, err)
+	}
+	if _, err := newOwnerScope(false, []string{"no-such-user-jfind"}); err == nil {
+		t.Errorf("expected error for an unknown owner")
+	}
+	scope, err := newOwnerScope(true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scope.allowed) != 1 {
+		t.Errorf("expected the current user, got %v", scope.allowed)
+	}
+	// the root of the scan is always walked, whoever owns it
+	finder := NewJavaFinder(t.TempDir(), -1, false)
+	finder.owners = &ownerScope{allowed: map[string]bool{}}
+	info, err := os.Stat(finder.startPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finder.ownedByOthers(finder.startPath, info) {
+		t.Errorf("expected the start path to be walked")
+	}
+}
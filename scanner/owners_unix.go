@@ -0,0 +1,24 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// fileOwner returns the user ID of the owner of a file
+func fileOwner(path string, info os.FileInfo) (string, error) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", fmt.Errorf("no owner information for %s", path)
+	}
+	return strconv.FormatUint(uint64(stat.Uid), 10), nil
+}
+
+// isSystemOwner reports whether the owner is root
+func isSystemOwner(owner string) bool {
+	return owner == "0"
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// systemOwners are the SIDs of LocalSystem, the Administrators group and TrustedInstaller
+var systemOwners = map[string]bool{
+	"S-1-5-18":     true,
+	"S-1-5-32-544": true,
+	"S-1-5-80-956008885-3418522649-1831038044-1853292631-2271478464": true,
+}
+
+// fileOwner returns the SID of the owner of a file
+func fileOwner(path string, info os.FileInfo) (string, error) {
+	sd, err := windows.GetNamedSecurityInfo(path, windows.SE_FILE_OBJECT, windows.OWNER_SECURITY_INFORMATION)
+	if err != nil {
+		return "", err
+	}
+	owner, _, err := sd.Owner()
+	if err != nil {
+		return "", err
+	}
+	return owner.String(), nil
+}
+
+// isSystemOwner reports whether the owner is the operating system or the administrators
+func isSystemOwner(owner string) bool {
+	return systemOwners[owner]
+}
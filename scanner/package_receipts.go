@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// javaPackagePublishers maps package ID prefixes of Java installers to their publisher
+var javaPackagePublishers = []struct {
+	prefix    string
+	publisher string
+}{
+	{"com.oracle.jdk", "Oracle Corporation"},
+	{"com.oracle.jre", "Oracle Corporation"},
+	{"com.oracle.java", "Oracle Corporation"},
+	{"net.temurin.", "Eclipse Adoptium"},
+	{"net.adoptopenjdk.", "AdoptOpenJDK"},
+	{"com.azulsystems.zulu.", "Azul Systems, Inc."},
+	{"com.amazon.corretto.", "Amazon.com Inc."},
+	{"com.microsoft.", "Microsoft"},
+}
+
+// bundlePattern matches the bundle directory of a receipt file list
+var bundlePattern = regexp.MustCompile(`^/?(Library/Java/JavaVirtualMachines/[^/]+\.jdk|Library/Internet Plug-Ins/JavaAppletPlugin\.plugin)(/|$)`)
+
+// javaPackagePublisher returns the publisher of a Java installer package ID, or false for other packages
+func javaPackagePublisher(id string) (string, bool) {
+	for _, p := range javaPackagePublishers {
+		if strings.HasPrefix(id, p.prefix) {
+			// com.microsoft. covers all Microsoft packages, only its OpenJDK builds are Java installers
+			if p.prefix == "com.microsoft." && !strings.Contains(id, "openjdk") {
+				return "", false
+			}
+			return p.publisher, true
+		}
+	}
+	return "", false
+}
+
+// parsePkgInfo parses the output of 'pkgutil --pkg-info' into a receipt
+func parsePkgInfo(out string) InstalledProgram {
+	var program InstalledProgram
+	volume := "/"
+	location := ""
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "package-id":
+			program.PackageID = value
+			program.Name = value
+		case "version":
+			program.Version = value
+		case "volume":
+			volume = value
+		case "location":
+			location = value
+		case "install-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				program.InstallTime = time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+			}
+		}
+	}
+	if location != "" && location != "/" {
+		program.InstallLocation = filepath.Join(volume, location)
+	}
+	return program
+}
+
+// receiptBundle returns the bundle directory of a receipt from its file list,
+// for packages installed to the volume root instead of the bundle location
+func receiptBundle(volume, files string) string {
+	for _, line := range strings.Split(files, "\n") {
+		if m := bundlePattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			return filepath.Join(volume, m[1])
+		}
+	}
+	return ""
+}
+
+// packageReceipts enumerates the receipts of Java installer packages using pkgutil,
+// receipts remain registered when the installed files are deleted by hand
+func packageReceipts() []InstalledProgram {
+	out, err := exec.Command("pkgutil", "--pkgs").Output()
+	if err != nil {
+		return nil
+	}
+
+	var programs []InstalledProgram
+	for _, id := range strings.Fields(string(out)) {
+		publisher, ok := javaPackagePublisher(id)
+		if !ok {
+			continue
+		}
+		info, err := exec.Command("pkgutil", "--pkg-info", id).Output()
+		if err != nil {
+			continue
+		}
+		program := parsePkgInfo(string(info))
+		program.Publisher = publisher
+		program.Installer = installerPkg
+		if !bundlePattern.MatchString(strings.TrimPrefix(program.InstallLocation, "/")) {
+			if files, err := exec.Command("pkgutil", "--only-dirs", "--files", id).Output(); err == nil {
+				program.InstallLocation = receiptBundle("/", string(files))
+			}
+		}
+		program.checkLocation()
+		programs = append(programs, program)
+	}
+	return programs
+}
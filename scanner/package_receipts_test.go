@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestJavaPackagePublisher(t *testing.T) {
+	tests := map[string]string{
+		"com.oracle.jdk-17.0.2":         "Oracle Corporation",
+		"com.oracle.jre":                "Oracle Corporation",
+		"net.temurin.21.jdk":            "Eclipse Adoptium",
+		"com.azulsystems.zulu.17":       "Azul Systems, Inc.",
+		"com.microsoft.17.openjdk.jdk":  "Microsoft",
+		"com.microsoft.package.Outlook": "",
+		"com.apple.pkg.Core":            "",
+	}
+	for id, expected := range tests {
+		publisher, ok := javaPackagePublisher(id)
+		if publisher != expected || ok != (expected != "") {
+			t.Errorf("javaPackagePublisher(%s) = %s, %v", id, publisher, ok)
+		}
+	}
+}
+
+func TestParsePkgInfo(t *testing.T) {
+	program := parsePkgInfo("package-id: com.oracle.jdk-17.0.2\nversion: 17.0.2\nvolume: /\n" +
+		"location: Library/Java/JavaVirtualMachines/jdk-17.0.2.jdk\ninstall-time: 1643795012\n")
+	if program.PackageID != "com.oracle.jdk-17.0.2" || program.Version != "17.0.2" ||
+		program.InstallLocation != "/Library/Java/JavaVirtualMachines/jdk-17.0.2.jdk" ||
+		program.InstallTime != "2022-02-02T09:43:32Z" {
+		t.Errorf("unexpected receipt %+v", program)
+	}
+
+	program = parsePkgInfo("package-id: net.temurin.21.jdk\nversion: 21.0.1+12\nvolume: /\nlocation: /\n")
+	if program.InstallLocation != "" {
+		t.Errorf("expected no location for root installs, got %s", program.InstallLocation)
+	}
+}
+
+func TestReceiptBundle(t *testing.T) {
+	files := "Library\nLibrary/Java\nLibrary/Java/JavaVirtualMachines\n" +
+		"Library/Java/JavaVirtualMachines/temurin-21.jdk\nLibrary/Java/JavaVirtualMachines/temurin-21.jdk/Contents\n"
+	if bundle := receiptBundle("/", files); bundle != "/Library/Java/JavaVirtualMachines/temurin-21.jdk" {
+		t.Errorf("unexpected bundle %s", bundle)
+	}
+	if bundle := receiptBundle("/", "usr\nusr/local\n"); bundle != "" {
+		t.Errorf("expected no bundle, got %s", bundle)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// dirQueue is the work queue of the parallel walkers. Directories are taken last in, first out,
+// so the walk goes depth first and the queue stays small.
+type dirQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	dirs   []string
+	active int
+}
+
+func newDirQueue(root string) *dirQueue {
+	q := &dirQueue{dirs: []string{root}}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// next returns the next directory to read, false when all directories are done
+func (q *dirQueue) next() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.dirs) == 0 && q.active > 0 {
+		q.cond.Wait()
+	}
+	if len(q.dirs) == 0 {
+		return "", false
+	}
+	dir := q.dirs[len(q.dirs)-1]
+	q.dirs = q.dirs[:len(q.dirs)-1]
+	q.active++
+	return dir, true
+}
+
+// done adds the subdirectories of a read directory to the queue
+func (q *dirQueue) done(subdirs []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.dirs = append(q.dirs, subdirs...)
+	q.active--
+	q.cond.Broadcast()
+}
+
+// findParallel walks the trees of roots one after the other with several walkers and evaluates the found executables with a pool of
+// evaluators while the walk goes on. Results are sorted by path, as the walk order is not deterministic.
+func (f *JavaFinder) findParallel(roots []string) ([]*JavaResult, error) {
+	candidates := make(chan string, 64)
+	var results []*JavaResult
+	var mu sync.Mutex
+
+	var evaluators sync.WaitGroup
+	for i := 0; i < max(1, f.evaluators); i++ {
+		evaluators.Add(1)
+		go func() {
+			defer evaluators.Done()
+			for path := range candidates {
+				result := f.evaluateJava(path)
+				mu.Lock()
+				results = append(results, &result)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var err error
+	for _, root := range roots {
+		err = f.walkParallel(root, func(path string) {
+			if f.indexed[path] {
+				return
+			}
+			f.foundJava(path)
+			candidates <- path
+		})
+		if err != nil {
+			break
+		}
+		f.walked[root] = true
+	}
+	close(candidates)
+	evaluators.Wait()
+
+	return sortedByPath(results), err
+}
+
+// walkParallel reads directories concurrently and calls found for every java executable.
+// It applies the rules of handleDirectory: .git and directories beyond the maximum depth are skipped,
+// unreadable directories are reported and skipped.
+func (f *JavaFinder) walkParallel(root string, found func(path string)) error {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if isJavaFile(root, info) {
+			found(root)
+		}
+		return nil
+	}
+	f.enterDir(root)
+
+	queue := newDirQueue(root)
+	var workers sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+
+	for i := 0; i < max(1, f.walkers); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				dir, ok := queue.next()
+				if !ok {
+					return
+				}
+				subdirs, err := f.readDir(dir, found)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+				queue.done(subdirs)
+			}
+		}()
+	}
+	workers.Wait()
+	return firstErr
+}
+
+// readDir processes the entries of one directory and returns the subdirectories to walk.
+// With a walk state a directory unchanged since the last walk is not read, its recorded entries are used.
+func (f *JavaFinder) readDir(dir string, found func(path string)) ([]string, error) {
+	if err := f.walkCtx.Err(); err != nil {
+		return nil, err
+	}
+
+	var modTime time.Time
+	if f.state != nil {
+		record, mtime, ok := f.state.unchanged(dir)
+		if ok {
+			for _, name := range record.Java {
+				if path := filepath.Join(dir, name); f.withinDepth(path) {
+					found(path)
+				}
+			}
+			for _, name := range record.Installers {
+				if path := filepath.Join(dir, name); f.findInstallers && f.withinDepth(path) {
+					f.checkInstaller(path)
+				}
+			}
+			return f.walkableSubdirs(dir, record.Subdirs), nil
+		}
+		modTime = mtime
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsPermission(err) {
+			if f.ticker.Load() {
+				fmt.Fprintln(os.Stderr)
+			}
+			slog.Info("Permission denied", "path", dir)
+			f.recordError(dir, err)
+			return nil, nil
+		}
+		// like filepath.Walk, entries read before the error are still processed, the walk goes on
+		f.recordError(dir, err)
+	}
+
+	var record dirRecord
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			record.Subdirs = append(record.Subdirs, entry.Name())
+			continue
+		}
+		if !isJavaExecutable(entry.Name()) {
+			if _, ok := parseInstallerName(entry.Name()); ok {
+				record.Installers = append(record.Installers, entry.Name())
+				if f.findInstallers && f.withinDepth(path) {
+					f.checkInstaller(path)
+				}
+			}
+			continue
+		}
+		if info, err := entry.Info(); err == nil && isJavaFile(path, info) {
+			record.Java = append(record.Java, entry.Name())
+			if f.withinDepth(path) {
+				found(path)
+			}
+		}
+	}
+	if f.state != nil && err == nil && !modTime.IsZero() {
+		record.ModTime = modTime.UnixNano()
+		f.state.record(dir, record)
+	}
+	return f.walkableSubdirs(dir, record.Subdirs), nil
+}
+
+// withinDepth reports whether a path is within the maximum depth of the walk
+func (f *JavaFinder) withinDepth(path string) bool {
+	return f.maxDepth < 0 || f.getPathDepth(path) <= f.maxDepth
+}
+
+// walkableSubdirs applies the rules of handleDirectory to the subdirectories of dir and returns the paths to walk
+func (f *JavaFinder) walkableSubdirs(dir string, names []string) []string {
+	var subdirs []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if !f.withinDepth(path) || name == ".git" || f.walked[path] || f.excluded(path) || f.skipMount(path) {
+			continue
+		}
+		if f.owners != nil {
+			if info, err := os.Lstat(path); err == nil && f.ownedByOthers(path, info) {
+				continue
+			}
+		}
+		f.enterDir(path)
+		f.checkSuspected(path)
+		f.checkApplication(path)
+		subdirs = append(subdirs, path)
+	}
+	return subdirs
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func findPaths(t *testing.T, root string, maxDepth, walkers int) ([]string, int64) {
+	t.Helper()
+	finder := NewJavaFinder(root, maxDepth, false)
+	finder.walkers = walkers
+	finder.evaluators = walkers
+	results, err := finder.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := make([]string, 0, len(results))
+	for _, result := range results {
+		paths = append(paths, result.Path)
+	}
+	return paths, finder.scanned.Load()
+}
+
+func TestFindParallelMatchesSequential(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	root := t.TempDir()
+	for _, dir := range []string{"a/jdk-17/bin", "a/jdk-17/jre/bin", "b/c/d/jdk-21/bin", "e/.git/bin", "f/bin"} {
+		writeFakeJava(t, filepath.Join(root, dir))
+	}
+	// not executable
+	if err := os.Chmod(filepath.Join(root, "f", "bin", "java"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, depth := range []int{-1, 3, 5} {
+		sequential, scannedSeq := findPaths(t, root, depth, 1)
+		parallel, scannedPar := findPaths(t, root, depth, 4)
+		if !reflect.DeepEqual(sequential, parallel) {
+			t.Errorf("depth %d: sequential found %v, parallel found %v", depth, sequential, parallel)
+		}
+		if scannedSeq != scannedPar {
+			t.Errorf("depth %d: sequential scanned %d directories, parallel %d", depth, scannedSeq, scannedPar)
+		}
+	}
+
+	all, _ := findPaths(t, root, -1, 4)
+	if len(all) != 3 {
+		t.Errorf("expected 3 java executables, got %v", all)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetRuntime is a row of Parquet output, a runtime with the scan it was found by. The schema is flat so data
+// lake engines (Athena, Spark, DuckDB) query it without flattening, columns are only ever added.
+type parquetRuntime struct {
+	ScanTimestamp    string  `parquet:"name=scan_ts, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ComputerName     string  `parquet:"name=computer_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UserName         *string `parquet:"name=user_name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	PlatformInfo     *string `parquet:"name=platform_info, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	ScanPath         *string `parquet:"name=scan_path, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	JavaExecutable   string  `parquet:"name=java_executable, type=BYTE_ARRAY, convertedtype=UTF8"`
+	JavaHome         *string `parquet:"name=java_home, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Edition          *string `parquet:"name=edition, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	JavaVersion      *string `parquet:"name=java_version, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	VersionMajor     *int32  `parquet:"name=java_version_major, type=INT32, repetitiontype=OPTIONAL"`
+	VersionUpdate    *int32  `parquet:"name=java_version_update, type=INT32, repetitiontype=OPTIONAL"`
+	JavaVersionDate  *string `parquet:"name=java_version_date, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	JavaVendor       *string `parquet:"name=java_vendor, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	Distribution     *string `parquet:"name=distribution, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	JavaRuntime      *string `parquet:"name=java_runtime, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	VMImplementation *string `parquet:"name=vm_implementation, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	IsOracle         bool    `parquet:"name=is_oracle, type=BOOLEAN"`
+	RequireLicense   *bool   `parquet:"name=require_license, type=BOOLEAN, repetitiontype=OPTIONAL"`
+	LicenseReason    *string `parquet:"name=license_reason, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	LicenseFreeUntil *string `parquet:"name=license_free_until, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	ExecFailed       bool    `parquet:"name=exec_failed, type=BOOLEAN"`
+	VersionSource    *string `parquet:"name=version_source, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	BinaryArch       *string `parquet:"name=binary_arch, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	DefaultForUser   bool    `parquet:"name=default_for_user, type=BOOLEAN"`
+	InstallSize      *int64  `parquet:"name=install_size_bytes, type=INT64, repetitiontype=OPTIONAL"`
+	DuplicateOf      *string `parquet:"name=duplicate_of, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	InstalledProgram *string `parquet:"name=installed_program, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	ProfileUser      *string `parquet:"name=profile_user, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+}
+
+// renderParquet renders the runtimes as a Snappy compressed Parquet file, one row per runtime
+func renderParquet(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	pw, err := writer.NewParquetWriterFromWriter(&buf, new(parquetRuntime), 1)
+	if err != nil {
+		return nil, fmt.Errorf("creating Parquet writer: %v", err)
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	m := output.Meta
+	for _, r := range output.Runtimes {
+		row := parquetRuntime{
+			ScanTimestamp:    m.ScanTimestamp,
+			ComputerName:     m.ComputerName,
+			UserName:         optionalString(m.UserName),
+			PlatformInfo:     optionalString(m.PlatformInfo),
+			ScanPath:         optionalString(m.ScanPath),
+			JavaExecutable:   r.JavaExecutable,
+			JavaHome:         optionalString(r.JavaHome),
+			Edition:          optionalString(r.Edition),
+			JavaVersion:      optionalString(r.JavaVersion),
+			VersionMajor:     optionalInt32(r.VersionMajor),
+			VersionUpdate:    optionalInt32(r.VersionUpdate),
+			JavaVersionDate:  optionalString(r.JavaVersionDate),
+			JavaVendor:       optionalString(r.JavaVendor),
+			Distribution:     optionalString(r.Distribution),
+			JavaRuntime:      optionalString(r.JavaRuntime),
+			VMImplementation: optionalString(r.VMImplementation),
+			IsOracle:         r.IsOracle,
+			RequireLicense:   r.RequireLicense,
+			LicenseReason:    optionalString(r.LicenseReason),
+			LicenseFreeUntil: optionalString(r.LicenseFreeUntil),
+			ExecFailed:       r.ExecFailed,
+			VersionSource:    optionalString(r.VersionSource),
+			BinaryArch:       optionalString(strings.Join(r.BinaryArch, ",")),
+			DefaultForUser:   r.DefaultForUser,
+			DuplicateOf:      optionalString(r.DuplicateOf),
+			InstalledProgram: optionalString(r.InstalledProgram),
+			ProfileUser:      optionalString(r.ProfileUser),
+		}
+		if r.InstallSize > 0 {
+			row.InstallSize = &r.InstallSize
+		}
+		if err := pw.Write(row); err != nil {
+			return nil, fmt.Errorf("writing Parquet row: %v", err)
+		}
+	}
+
+	if err := pw.WriteStop(); err != nil {
+		return nil, fmt.Errorf("writing Parquet file: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// optionalString returns nil for empty strings, written as null
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalInt32 returns nil for zero, for values only known for evaluated runtimes
+func optionalInt32(i int) *int32 {
+	if i == 0 {
+		return nil
+	}
+	v := int32(i)
+	return &v
+}
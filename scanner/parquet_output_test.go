@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func TestRenderParquet(t *testing.T) {
+	requireLicense := true
+	output := &JSONOutput{
+		Meta: MetaInfo{ScanTimestamp: "2026-10-16T08:00:00Z", ComputerName: "build01"},
+		Runtimes: []JavaRuntimeJSON{
+			{JavaExecutable: "/opt/jdk1.8.0_202/bin/java", JavaVersion: "1.8.0_202", VersionMajor: 8, VersionUpdate: 202,
+				IsOracle: true, RequireLicense: &requireLicense, BinaryArch: []string{"x86_64", "arm64"}},
+			{JavaExecutable: "/opt/broken/bin/java", ExecFailed: true},
+		},
+	}
+
+	data, err := renderParquet(output, renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("expected a Parquet file")
+	}
+
+	file, err := buffer.NewBufferFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := reader.NewParquetReader(file, new(parquetRuntime), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.ReadStop()
+	if pr.GetNumRows() != 2 {
+		t.Fatalf("expected 2 rows, got %d", pr.GetNumRows())
+	}
+	rows := make([]parquetRuntime, 2)
+	if err := pr.Read(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	oracle := rows[0]
+	if oracle.ComputerName != "build01" || oracle.JavaVersion == nil || *oracle.JavaVersion != "1.8.0_202" ||
+		oracle.VersionMajor == nil || *oracle.VersionMajor != 8 || oracle.RequireLicense == nil || !*oracle.RequireLicense ||
+		oracle.BinaryArch == nil || *oracle.BinaryArch != "x86_64,arm64" {
+		t.Errorf("unexpected row %+v", oracle)
+	}
+	broken := rows[1]
+	if !broken.ExecFailed || broken.JavaVersion != nil || broken.VersionMajor != nil || broken.RequireLicense != nil {
+		t.Errorf("expected nulls for a failed runtime, got %+v", broken)
+	}
+}
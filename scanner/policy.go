@@ -0,0 +1,522 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is a verdict of the organization on runtimes, e.g. only approved distributions or a minimum version. A
+// runtime violates the policy if its deny expression is true.
+type Policy struct {
+	Name    string `yaml:"name"`
+	Message string `yaml:"message"`
+	Deny    string `yaml:"deny"`
+	expr    policyExpr
+}
+
+// PolicyViolation is a policy violated by a runtime
+type PolicyViolation struct {
+	Policy  string `json:"policy"`
+	Message string `json:"message,omitempty"`
+}
+
+// policyFile is the content of a policy file
+type policyFile struct {
+	Policies []Policy `yaml:"policies"`
+}
+
+// loadPolicies reads and compiles the policies of a policy file
+func loadPolicies(path string) ([]Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy file: %v", err)
+	}
+	var file policyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing policy file %s: %v", path, err)
+	}
+	for i := range file.Policies {
+		policy := &file.Policies[i]
+		if policy.Name == "" || policy.Deny == "" {
+			return nil, fmt.Errorf("policy file %s: policy %d needs a name and a deny expression", path, i+1)
+		}
+		if policy.expr, err = parsePolicyExpr(policy.Deny); err != nil {
+			return nil, fmt.Errorf("policy '%s': %v", policy.Name, err)
+		}
+	}
+	return file.Policies, nil
+}
+
+// evaluatePolicies sets the policy violations of the results and returns the number of violating runtimes. The
+// expressions see the fields of the JSON output of a runtime.
+func evaluatePolicies(policies []Policy, results []*JavaResult, evaluate bool) int {
+	violating := 0
+	for _, result := range results {
+		fields := runtimeFields(createRuntimeJSON(result, evaluate))
+		for _, policy := range policies {
+			if policy.expr.eval(fields) {
+				result.PolicyViolations = append(result.PolicyViolations, PolicyViolation{Policy: policy.Name, Message: policy.Message})
+			}
+		}
+		if len(result.PolicyViolations) > 0 {
+			violating++
+		}
+	}
+	return violating
+}
+
+// runtimeFields returns the fields of the JSON output of a runtime
+func runtimeFields(runtime JavaRuntimeJSON) map[string]any {
+	var fields map[string]any
+	data, err := json.Marshal(runtime)
+	if err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// policyExpr is a compiled deny expression
+type policyExpr interface {
+	eval(fields map[string]any) bool
+}
+
+type andExpr struct{ left, right policyExpr }
+type orExpr struct{ left, right policyExpr }
+type notExpr struct{ expr policyExpr }
+
+// fieldExpr is true for a field with a true, non-zero or non-empty value
+type fieldExpr struct{ field string }
+
+// compareExpr compares a field with a value, numbers numerically and strings with < and > as versions
+type compareExpr struct {
+	field string
+	op    string
+	value any
+}
+
+// inExpr is true if the value of a field, or an element of a list field, is one of the values
+type inExpr struct {
+	field  string
+	values []any
+	negate bool
+}
+
+type matchExpr struct {
+	field string
+	re    *regexp.Regexp
+}
+
+func (e andExpr) eval(fields map[string]any) bool { return e.left.eval(fields) && e.right.eval(fields) }
+func (e orExpr) eval(fields map[string]any) bool  { return e.left.eval(fields) || e.right.eval(fields) }
+func (e notExpr) eval(fields map[string]any) bool { return !e.expr.eval(fields) }
+
+func (e fieldExpr) eval(fields map[string]any) bool {
+	switch v := lookupField(fields, e.field).(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+	return false
+}
+
+// eval of a comparison with a field the runtime does not have, e.g. the version of an unevaluated runtime, is false
+// for every operator
+func (e compareExpr) eval(fields map[string]any) bool {
+	value := lookupField(fields, e.field)
+	if value == nil {
+		return false
+	}
+	if list, ok := value.([]any); ok {
+		for _, element := range list {
+			if compareValues(element, e.op, e.value) {
+				return true
+			}
+		}
+		return false
+	}
+	return compareValues(value, e.op, e.value)
+}
+
+func (e inExpr) eval(fields map[string]any) bool {
+	value := lookupField(fields, e.field)
+	if value == nil {
+		return false
+	}
+	elements, ok := value.([]any)
+	if !ok {
+		elements = []any{value}
+	}
+	for _, element := range elements {
+		for _, candidate := range e.values {
+			if compareValues(element, "==", candidate) {
+				return !e.negate
+			}
+		}
+	}
+	return e.negate
+}
+
+func (e matchExpr) eval(fields map[string]any) bool {
+	value, ok := lookupField(fields, e.field).(string)
+	return ok && e.re.MatchString(value)
+}
+
+// lookupField returns the value of a field, nested fields are separated by dots, e.g. cacerts.expired_count
+func lookupField(fields map[string]any, name string) any {
+	var value any = fields
+	for _, part := range strings.Split(name, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[part]
+	}
+	return value
+}
+
+// compareValues compares a field value with a literal of the expression
+func compareValues(value any, op string, literal any) bool {
+	cmp, ok := 0, false
+	switch v := value.(type) {
+	case float64:
+		if l, isNumber := literal.(float64); isNumber {
+			cmp, ok = compareNumbers(v, l), true
+		}
+	case string:
+		l := fmt.Sprint(literal)
+		if op == "==" || op == "!=" {
+			if strings.EqualFold(v, l) {
+				cmp = 0
+			} else {
+				cmp = 1
+			}
+			ok = true
+		} else {
+			cmp, ok = compareVersionStrings(v, l), true
+		}
+	case bool:
+		if l, isBool := literal.(bool); isBool && (op == "==" || op == "!=") {
+			cmp, ok = 1, true
+			if v == l {
+				cmp = 0
+			}
+		}
+	}
+	if !ok {
+		return false
+	}
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// versionNumbers matches the numeric parts of a version, 1.8.0_202 has the parts 1, 8, 0 and 202
+var versionNumbers = regexp.MustCompile(`\d+`)
+
+// compareVersionStrings compares versions by their numeric parts, a version is less than its extensions
+func compareVersionStrings(a, b string) int {
+	pa, pb := versionNumbers.FindAllString(a, -1), versionNumbers.FindAllString(b, -1)
+	if len(pa) == 0 || len(pb) == 0 {
+		return strings.Compare(a, b)
+	}
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, _ := strconv.Atoi(pa[i])
+		nb, _ := strconv.Atoi(pb[i])
+		if na != nb {
+			return compareNumbers(float64(na), float64(nb))
+		}
+	}
+	return compareNumbers(float64(len(pa)), float64(len(pb)))
+}
+
+// policyParser parses deny expressions:
+//
+//	expr       = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" expr ")" | field [ op value | ["not"] "in" list | "matches" string | "contains" value ]
+//	op         = "==" | "!=" | "<" | "<=" | ">" | ">="
+//	value      = number | string | "true" | "false" | word
+//	list       = "[" value { "," value } "]"
+type policyParser struct {
+	tokens []string
+	pos    int
+}
+
+// parsePolicyExpr compiles a deny expression
+func parsePolicyExpr(input string) (policyExpr, error) {
+	tokens, err := tokenizePolicy(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &policyParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected '%s' in '%s'", p.tokens[p.pos], input)
+	}
+	return expr, nil
+}
+
+// tokenizePolicy splits an expression into words, numbers, quoted strings (kept with their quotes) and operators.
+// Strings are quoted with " or ', a string containing one quote character is quoted with the other.
+func tokenizePolicy(input string) ([]string, error) {
+	var tokens []string
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string in '%s'", input)
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("=!<>", r):
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, string(runes[i:i+2]))
+				i += 2
+			} else if r == '<' || r == '>' {
+				tokens = append(tokens, string(r))
+				i++
+			} else {
+				return nil, fmt.Errorf("invalid operator '%c' in '%s'", r, input)
+			}
+		default:
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || strings.ContainsRune("_.-+", runes[end])) {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("invalid character '%c' in '%s'", r, input)
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func (p *policyParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *policyParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *policyParser) expect(token string) error {
+	if got := p.next(); got != token {
+		if got == "" {
+			return fmt.Errorf("expected '%s' at the end of the expression", token)
+		}
+		return fmt.Errorf("expected '%s', got '%s'", token, got)
+	}
+	return nil
+}
+
+func (p *policyParser) parseOr() (policyExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *policyParser) parseAnd() (policyExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *policyParser) parseUnary() (policyExpr, error) {
+	switch token := p.next(); {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of the expression")
+	case token == "not":
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	case token == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	case !isPolicyField(token):
+		return nil, fmt.Errorf("expected a field name, got '%s'", token)
+	default:
+		return p.parseCondition(token)
+	}
+}
+
+// parseCondition parses the condition on a field
+func (p *policyParser) parseCondition(field string) (policyExpr, error) {
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return compareExpr{field: field, op: op, value: value}, nil
+	case "contains":
+		p.next()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return inExpr{field: field, values: []any{value}}, nil
+	case "in", "not":
+		p.next()
+		negate := op == "not"
+		if negate {
+			if err := p.expect("in"); err != nil {
+				return nil, err
+			}
+		}
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return inExpr{field: field, values: values, negate: negate}, nil
+	case "matches":
+		p.next()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(fmt.Sprint(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of %s: %v", field, err)
+		}
+		return matchExpr{field: field, re: re}, nil
+	}
+	return fieldExpr{field: field}, nil
+}
+
+func (p *policyParser) parseList() ([]any, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	var values []any
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+	return values, p.expect("]")
+}
+
+func (p *policyParser) parseValue() (any, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("expected a value at the end of the expression")
+	case token == "true" || token == "false":
+		return token == "true", nil
+	case strings.HasPrefix(token, `"`) || strings.HasPrefix(token, `'`):
+		// strings are taken verbatim, so patterns need no escaping
+		return token[1 : len(token)-1], nil
+	case strings.ContainsRune("()[],=!<>", rune(token[0])):
+		return nil, fmt.Errorf("expected a value, got '%s'", token)
+	}
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return number, nil
+	}
+	return token, nil
+}
+
+// isPolicyField reports whether a token is a field name
+func isPolicyField(token string) bool {
+	switch token {
+	case "and", "or", "not", "in", "matches", "contains", "true", "false":
+		return false
+	}
+	r := []rune(token)[0]
+	return unicode.IsLetter(r) || r == '_'
+}
+
+// printPolicyViolations prints the number of runtimes violating policies
+func printPolicyViolations(violating int) {
+	if violating > 0 {
+		printf("Policy violations: %d runtimes violate the policies\n", violating)
+	}
+}
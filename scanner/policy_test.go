@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicyExpressions(t *testing.T) {
+	fields := map[string]any{
+		"java_version":       "1.8.0_202",
+		"java_version_major": float64(8),
+		"distribution":       "Temurin",
+		"is_oracle":          false,
+		"binary_arch":        []any{"x86_64", "arm64"},
+		"cacerts":            map[string]any{"expired_count": float64(2)},
+		"java_home":          "/opt/jdk8u202",
+	}
+	for expr, want := range map[string]bool{
+		"java_version_major < 17":                             true,
+		"java_version_major >= 8 and java_version_major <= 8": true,
+		`java_version < "11"`:                                 true,
+		"java_version < 1.8.0_191":                            false,
+		"distribution not in [temurin, corretto]":             false,
+		"distribution in [zulu, 'oracle']":                    false,
+		"distribution == TEMURIN":                             true,
+		"is_oracle":                                           false,
+		"not is_oracle":                                       true,
+		"is_oracle == false":                                  true,
+		"binary_arch contains arm64":                          true,
+		"binary_arch in [ppc64le, s390x]":                     false,
+		"cacerts.expired_count > 0":                           true,
+		`java_home matches "^/opt/jdk8u\d+$"`:                 true,
+		"vm_implementation != hotspot":                        false,
+		"vm_implementation not in [hotspot]":                  false,
+		"is_oracle or (java_version_major < 11 and not distribution in [zulu])": true,
+	} {
+		compiled, err := parsePolicyExpr(expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+		if got := compiled.eval(fields); got != want {
+			t.Errorf("%s = %v, want %v", expr, got, want)
+		}
+	}
+
+	for _, expr := range []string{"", "java_version <", "(is_oracle", "distribution in temurin", "17 < java_version_major", `java_home matches "("`, "a = b", "is_oracle is_oracle"} {
+		if _, err := parsePolicyExpr(expr); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+}
+
+func TestCompareVersionStrings(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.8.0_202", "17", -1},
+		{"17.0.9", "17", 1},
+		{"21.0.1", "21.0.1", 0},
+		{"11.0.20", "11.0.3", 1},
+	} {
+		if got := compareVersionStrings(c.a, c.b); got != c.want {
+			t.Errorf("compareVersionStrings(%s, %s) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	data := `policies:
+  - name: minimum-version
+    message: JDK below 17 is non-compliant
+    deny: java_version_major < 17
+  - name: approved-vendors
+    deny: distribution not in [temurin, corretto]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := loadPolicies(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*JavaResult{
+		{Path: "/opt/jdk-21/bin/java", Evaluated: true, Properties: &JavaProperties{Version: "21.0.1", Major: 21, Vendor: "Eclipse Adoptium", RuntimeName: "OpenJDK Runtime Environment"}},
+		{Path: "/opt/jdk-11/bin/java", Evaluated: true, Properties: &JavaProperties{Version: "11.0.20", Major: 11, Vendor: "Azul Systems, Inc.", RuntimeName: "OpenJDK Runtime Environment"}},
+		{Path: "/opt/unknown/bin/java"},
+	}
+	if violating := evaluatePolicies(policies, results, true); violating != 1 {
+		t.Errorf("expected 1 violating runtime, got %d", violating)
+	}
+	if len(results[0].PolicyViolations) != 0 || len(results[2].PolicyViolations) != 0 {
+		t.Errorf("unexpected violations %+v, %+v", results[0].PolicyViolations, results[2].PolicyViolations)
+	}
+	want := []PolicyViolation{{Policy: "minimum-version", Message: "JDK below 17 is non-compliant"}, {Policy: "approved-vendors"}}
+	if got := results[1].PolicyViolations; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("unexpected violations %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte("policies:\n  - name: broken\n    deny: java_version <\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPolicies(path); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+}
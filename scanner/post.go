@@ -10,15 +10,15 @@ import (
 	"os"
 )
 
-// sendJSON sends the JSON payload to the specified URL via HTTP POST
-func sendJSON(jsonData []byte, urlStr string) error {
+// sendPayload sends a payload with the given content type to the specified URL via HTTP POST
+func sendPayload(payload []byte, urlStr string, contentType string) error {
 	// Validate URL
 	parsedURL, err := url.Parse(urlStr)
 	if err != nil || !parsedURL.IsAbs() {
 		return fmt.Errorf("invalid URL %s: %v", urlStr, err)
 	}
 
-	resp, err := http.Post(urlStr, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := http.Post(urlStr, contentType, bytes.NewBuffer(payload))
 	if err != nil {
 		// Check if it's a connection error
 		if netErr, ok := err.(*net.OpError); ok {
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// toolCacheDirs are the directories below a home directory where IDEs, build tools and version managers install JDKs
+var toolCacheDirs = []string{
+	filepath.Join(".sdkman", "candidates", "java"),
+	".jdks",
+	filepath.Join(".gradle", "jdks"),
+	filepath.Join(".asdf", "installs", "java"),
+	filepath.Join(".local", "share", "mise", "installs", "java"),
+	filepath.Join(".jenv", "versions"),
+	filepath.Join("Library", "Java", "JavaVirtualMachines"),
+}
+
+// likelyJavaDirs returns the directories most likely to contain Java installations on a platform, the more specific
+// ones first. The tool caches of the given home directories follow the system locations.
+func likelyJavaDirs(goos string, homes []string) []string {
+	var dirs []string
+	switch goos {
+	case "windows":
+		for _, env := range []string{"ProgramFiles", "ProgramW6432", "ProgramFiles(x86)"} {
+			if dir := os.Getenv(env); dir != "" {
+				dirs = append(dirs, filepath.Join(dir, "Java"), dir)
+			}
+		}
+	case "darwin":
+		dirs = append(dirs, "/Library/Java/JavaVirtualMachines", "/opt/homebrew", "/usr/local/Cellar", "/opt")
+	default:
+		dirs = append(dirs, wellKnownJVMDirs...)
+	}
+	for _, home := range homes {
+		for _, dir := range toolCacheDirs {
+			dirs = append(dirs, filepath.Join(home, dir))
+		}
+	}
+	return dirs
+}
+
+// priorityDirs returns the likely Java directories below the start path that the walk would reach,
+// they are walked before the rest of the tree, so installations are found early in time-boxed and interrupted scans
+func (f *JavaFinder) priorityDirs() []string {
+	var homes []string
+	if home, err := os.UserHomeDir(); err == nil {
+		homes = append(homes, home)
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, dir := range likelyJavaDirs(runtime.GOOS, homes) {
+		if dir == f.startPath || seen[dir] || !isBelow(dir, f.startPath) || !f.withinDepth(dir) || !f.reachable(dir) {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// reachable reports whether the walk from the start path would enter dir: dir and its parents below the start path
+// are directories, not links, and are not skipped as network mounts, .git directories or by owner
+func (f *JavaFinder) reachable(dir string) bool {
+	for path := dir; path != f.startPath; path = filepath.Dir(path) {
+		info, err := os.Lstat(path)
+		if err != nil || !info.IsDir() || info.Name() == ".git" || f.excluded(path) {
+			return false
+		}
+		if _, ok := f.skipMounts[path]; ok {
+			return false
+		}
+		if !f.owners.permits(path, info) {
+			return false
+		}
+		if parent := filepath.Dir(path); parent == path {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestPriorityDirs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses HOME as home directory")
+	}
+
+	root := t.TempDir()
+	home := filepath.Join(root, "home", "dev")
+	t.Setenv("HOME", home)
+	writeFakeJava(t, filepath.Join(home, ".sdkman", "candidates", "java", "17.0.13-tem", "bin"))
+	writeFakeJava(t, filepath.Join(home, ".gradle", "jdks", "jdk-21", "bin"))
+	writeFakeJava(t, filepath.Join(root, "srv", "app", "jre", "bin"))
+	// a linked tool cache is not reachable by the walk
+	if err := os.Symlink(filepath.Join(root, "srv"), filepath.Join(home, ".jdks")); err != nil {
+		t.Fatal(err)
+	}
+
+	finder := NewJavaFinder(root, -1, false)
+	dirs := finder.priorityDirs()
+	if len(dirs) != 2 || dirs[0] != filepath.Join(home, ".sdkman", "candidates", "java") ||
+		dirs[1] != filepath.Join(home, ".gradle", "jdks") {
+		t.Errorf("unexpected priority directories %v", dirs)
+	}
+	finder.maxDepth = 3
+	if dirs := finder.priorityDirs(); len(dirs) != 0 {
+		t.Errorf("expected no priority directories beyond the maximum depth, got %v", dirs)
+	}
+
+	// root, home, dev, .sdkman, candidates, java, 17.0.13-tem, bin, .gradle, jdks, jdk-21, bin, srv, app, jre, bin
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 3 || results[0].Path > results[1].Path || results[1].Path > results[2].Path {
+			t.Errorf("threads %d: expected 3 sorted results, got %d", threads, len(results))
+		}
+		if scanned := finder.scanned.Load(); scanned != 16 {
+			t.Errorf("threads %d: expected 16 scanned directories, got %d", threads, scanned)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// homeDirPattern matches the user name component of home directory paths on all platforms
+var homeDirPattern = regexp.MustCompile(`^(/home/|/Users/|(?i:[a-z]:\\Users\\))([^/\\]+)`)
+
+// pseudonymizer replaces personal identifiers with stable keyed pseudonyms,
+// the same input and key always produce the same pseudonym, so hosts can be correlated over time
+type pseudonymizer struct {
+	key []byte
+}
+
+// newPseudonymizer reads the secret key from a file
+func newPseudonymizer(keyFile string) (*pseudonymizer, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading pseudonymization key: %v", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return nil, errors.New("pseudonymization key file is empty")
+	}
+	return &pseudonymizer{key: []byte(key)}, nil
+}
+
+// pseudonym returns the pseudonym of a value with the given prefix
+func (p *pseudonymizer) pseudonym(prefix, value string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(value))
+	return prefix + "-" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// pseudonymIfSet returns the pseudonym of a value, empty values stay empty
+func (p *pseudonymizer) pseudonymIfSet(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return p.pseudonym(prefix, value)
+}
+
+// user returns the pseudonym of a user name
+func (p *pseudonymizer) user(name string) string {
+	return p.pseudonym("user", name)
+}
+
+// host returns the pseudonym of a computer name
+func (p *pseudonymizer) host(name string) string {
+	return p.pseudonym("host", name)
+}
+
+// path replaces the user name component of home directory paths
+func (p *pseudonymizer) path(path string) string {
+	return homeDirPattern.ReplaceAllStringFunc(path, func(match string) string {
+		groups := homeDirPattern.FindStringSubmatch(match)
+		return groups[1] + p.user(groups[2])
+	})
+}
+
+// apply pseudonymizes the identifiers and paths of a JSON output
+func (p *pseudonymizer) apply(output *JSONOutput) {
+	output.Meta.UserName = p.user(output.Meta.UserName)
+	output.Meta.ComputerName = p.host(output.Meta.ComputerName)
+	if host := output.Meta.Host; host != nil {
+		host.FQDN = p.pseudonymIfSet("host", host.FQDN)
+		host.SerialNumber = p.pseudonymIfSet("serial", host.SerialNumber)
+		host.IPAddress = p.pseudonymIfSet("ip", host.IPAddress)
+		host.MACAddress = p.pseudonymIfSet("mac", host.MACAddress)
+	}
+	for i := range output.Profiles {
+		output.Profiles[i].User = p.user(output.Profiles[i].User)
+	}
+	for i := range output.Runtimes {
+		if output.Runtimes[i].ProfileUser != "" {
+			output.Runtimes[i].ProfileUser = p.user(output.Runtimes[i].ProfileUser)
+		}
+		if name, ok := output.Runtimes[i].Properties["user.name"]; ok {
+			output.Runtimes[i].Properties["user.name"] = p.user(name)
+		}
+	}
+	output.rewritePaths(p.path)
+}
+
+// rewritePaths applies fn to every file system path contained in the output
+func (o *JSONOutput) rewritePaths(fn func(string) string) {
+	// the roots of a scan of several paths are joined into the scan path
+	o.Meta.ScanPath = rewritePathList(o.Meta.ScanPath, fn)
+	if o.Meta.LastScannedPath != "" {
+		o.Meta.LastScannedPath = fn(o.Meta.LastScannedPath)
+	}
+	if o.BaselineMissing != nil {
+		o.BaselineMissing = append([]BaselineEntry(nil), o.BaselineMissing...)
+		for i := range o.BaselineMissing {
+			o.BaselineMissing[i].Path = fn(o.BaselineMissing[i].Path)
+			o.BaselineMissing[i].JavaHome = fn(o.BaselineMissing[i].JavaHome)
+		}
+	}
+	if o.Meta.Roots != nil {
+		o.Meta.Roots = append([]RootStats(nil), o.Meta.Roots...)
+		for i := range o.Meta.Roots {
+			o.Meta.Roots[i].Path = fn(o.Meta.Roots[i].Path)
+			if o.Meta.Roots[i].LastScannedPath != "" {
+				o.Meta.Roots[i].LastScannedPath = fn(o.Meta.Roots[i].LastScannedPath)
+			}
+		}
+	}
+
+	for i := range o.Runtimes {
+		o.Runtimes[i].rewritePaths(fn)
+	}
+
+	for i := range o.Entitlements {
+		o.Entitlements[i].rewritePaths(fn)
+	}
+
+	for i := range o.Errors {
+		o.Errors[i].Path = fn(o.Errors[i].Path)
+	}
+
+	for i := range o.Suspected {
+		o.Suspected[i].Path = fn(o.Suspected[i].Path)
+	}
+
+	for i := range o.Applications {
+		o.Applications[i].rewritePaths(fn)
+	}
+
+	for i := range o.Legacy {
+		o.Legacy[i].rewritePaths(fn)
+	}
+
+	for i := range o.Installers {
+		o.Installers[i].Path = fn(o.Installers[i].Path)
+	}
+
+	for i := range o.Profiles {
+		o.Profiles[i].Path = fn(o.Profiles[i].Path)
+	}
+
+	for i := range o.Programs {
+		if o.Programs[i].InstallLocation != "" {
+			o.Programs[i].InstallLocation = fn(o.Programs[i].InstallLocation)
+		}
+	}
+}
+
+// rewritePathList applies fn to every element of a list of paths joined by the path list separator
+func rewritePathList(list string, fn func(string) string) string {
+	elements := filepath.SplitList(list)
+	for i := range elements {
+		elements[i] = fn(elements[i])
+	}
+	return strings.Join(elements, string(os.PathListSeparator))
+}
+
+// rewriteEmbeddedPaths applies fn to the occurrences of paths in a message, a path preceding another one takes
+// precedence where both occur
+func rewriteEmbeddedPaths(message string, fn func(string) string, paths ...string) string {
+	var replacements []string
+	for _, path := range paths {
+		if path != "" {
+			replacements = append(replacements, path, fn(path))
+		}
+	}
+	if len(replacements) == 0 {
+		return message
+	}
+	return strings.NewReplacer(replacements...).Replace(message)
+}
+
+// rewritePaths applies fn to every file system path of a runtime
+func (j *JavaRuntimeJSON) rewritePaths(fn func(string) string) {
+	// the error of a failed evaluation names the executable, e.g. fork/exec <path>: exec format error
+	if j.ExecError != "" {
+		j.ExecError = rewriteEmbeddedPaths(j.ExecError, fn, j.JavaExecutable, j.JavaHome)
+	}
+	j.JavaExecutable = fn(j.JavaExecutable)
+	if j.Symlinks != nil {
+		links := make([]string, len(j.Symlinks))
+		for i, link := range j.Symlinks {
+			links[i] = fn(link)
+		}
+		j.Symlinks = links
+	}
+	if j.Aliases != nil {
+		aliases := make([]string, len(j.Aliases))
+		for i, alias := range j.Aliases {
+			aliases[i] = fn(alias)
+		}
+		j.Aliases = aliases
+	}
+	// path list properties like java.library.path are rewritten element by element
+	for key, value := range j.Properties {
+		j.Properties[key] = rewritePathList(value, fn)
+	}
+	if j.JavaHome != "" {
+		j.JavaHome = fn(j.JavaHome)
+	}
+	if j.DuplicateOf != "" {
+		j.DuplicateOf = fn(j.DuplicateOf)
+	}
+	if j.Wine != nil {
+		j.Wine.Prefix = fn(j.Wine.Prefix)
+	}
+	if j.DefaultPathEntry != "" {
+		j.DefaultPathEntry = fn(j.DefaultPathEntry)
+	}
+	if j.Application != nil {
+		j.Application.rewritePaths(fn)
+	}
+	if j.InstallProvenance != nil && j.InstallProvenance.InstallLocation != "" {
+		// the program is shared with the installed programs of the report, which are rewritten separately
+		provenance := *j.InstallProvenance
+		provenance.InstallLocation = fn(provenance.InstallLocation)
+		j.InstallProvenance = &provenance
+	}
+	if j.TrustStore != nil {
+		j.TrustStore.Path = fn(j.TrustStore.Path)
+	}
+	if j.Security != nil {
+		j.Security.Path = fn(j.Security.Path)
+		if j.Security.JCEPolicyFile != "" {
+			j.Security.JCEPolicyFile = fn(j.Security.JCEPolicyFile)
+		}
+	}
+}
+
+// rewritePaths applies fn to the path of entitlement evidence and the log file named in the detail of a usage
+// tracker configuration
+func (e *EntitlementEvidence) rewritePaths(fn func(string) string) {
+	e.Path = fn(e.Path)
+	if e.Type != evidenceUsageTracker || e.Detail == "" {
+		return
+	}
+	details := strings.Split(e.Detail, ";")
+	for i, detail := range details {
+		if log, ok := strings.CutPrefix(detail, "logToFile="); ok {
+			details[i] = "logToFile=" + fn(log)
+		}
+	}
+	e.Detail = strings.Join(details, ";")
+}
+
+// rewritePaths applies fn to the paths of an application
+func (a *JPackageApp) rewritePaths(fn func(string) string) {
+	a.Path = fn(a.Path)
+	a.Runtime = fn(a.Runtime)
+	if a.JavaExecutable != "" {
+		a.JavaExecutable = fn(a.JavaExecutable)
+	}
+}
+
+// rewritePaths applies fn to the paths of a legacy component
+func (c *LegacyComponent) rewritePaths(fn func(string) string) {
+	c.Path = fn(c.Path)
+	if c.JavaHome != "" {
+		c.JavaHome = fn(c.JavaHome)
+	}
+	if c.Target != "" {
+		c.Target = fn(c.Target)
+	}
+	if c.Settings != nil && c.Settings.ExceptionSitesFile != "" {
+		settings := *c.Settings
+		settings.ExceptionSitesFile = fn(settings.ExceptionSitesFile)
+		c.Settings = &settings
+	}
+}
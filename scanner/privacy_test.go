@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestPseudonymizer(t *testing.T) {
+	p := &pseudonymizer{key: []byte("secret")}
+
+	user := p.user("alice")
+	if user != p.user("alice") {
+		t.Error("Expected stable pseudonym")
+	}
+	if user == (&pseudonymizer{key: []byte("other")}).user("alice") {
+		t.Error("Expected pseudonym to depend on the key")
+	}
+
+	tests := map[string]string{
+		"/home/alice/.sdkman/candidates/java/17/bin/java": "/home/" + user + "/.sdkman/candidates/java/17/bin/java",
+		"/Users/alice/Library/Java/bin/java":              "/Users/" + user + "/Library/Java/bin/java",
+		`C:\Users\alice\jdk\bin\java.exe`:                 `C:\Users\` + user + `\jdk\bin\java.exe`,
+		"/opt/jdk/bin/java":                               "/opt/jdk/bin/java",
+	}
+	for path, want := range tests {
+		if got := p.path(path); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// isPrivileged reports whether jfind runs as root or from an elevated prompt
+func isPrivileged() bool {
+	if runtime.GOOS == "windows" {
+		// 'net session' requires an elevated prompt
+		return exec.Command("net", "session").Run() == nil
+	}
+	return os.Geteuid() == 0
+}
+
+// coverage counts the directories directly below the scan roots and how many of them cannot be read
+type coverage struct {
+	dirs       int
+	unreadable []string
+}
+
+// estimateCoverage checks which directories directly below the roots cannot be read, their subtrees are missing
+// from an unprivileged scan. It is an estimate, deeper directories may be unreadable as well.
+func estimateCoverage(roots []string) coverage {
+	var c coverage
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(root, entry.Name())
+			c.dirs++
+			if checkReadableDir(path) != nil {
+				c.unreadable = append(c.unreadable, path)
+			}
+		}
+	}
+	return c
+}
+
+// checkReadableDir returns the error listing the entries of a directory, nil if it is readable
+func checkReadableDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = dir.Close() }()
+	if _, err = dir.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// String describes the coverage impact for the privilege warning
+func (c coverage) String() string {
+	if c.dirs == 0 {
+		return "directories of other users may not be readable"
+	}
+	if len(c.unreadable) == 0 {
+		return fmt.Sprintf("all %d directories below the scan roots are readable, deeper directories may not be", c.dirs)
+	}
+	percent := 100 * len(c.unreadable) / c.dirs
+	examples := c.unreadable
+	if len(examples) > 3 {
+		examples = examples[:3]
+	}
+	return fmt.Sprintf("%d of %d directories below the scan roots (%d%%) cannot be read and are skipped, e.g. %v",
+		len(c.unreadable), c.dirs, percent, examples)
+}
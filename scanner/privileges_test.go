@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestEstimateCoverage(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"opt", "srv", "home"} {
+		if err := os.Mkdir(filepath.Join(root, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "file"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := estimateCoverage([]string{root, filepath.Join(root, "missing")})
+	if c.dirs != 3 || len(c.unreadable) != 0 {
+		t.Errorf("unexpected coverage %+v", c)
+	}
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		return
+	}
+	locked := filepath.Join(root, "srv")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chmod(locked, 0o755) }()
+	c = estimateCoverage([]string{root})
+	if len(c.unreadable) != 1 || c.unreadable[0] != locked {
+		t.Errorf("unexpected coverage %+v", c)
+	}
+}
+
+func TestCoverageString(t *testing.T) {
+	c := coverage{dirs: 8, unreadable: []string{"/a", "/b", "/c", "/d"}}
+	if s := c.String(); !strings.HasPrefix(s, "4 of 8 directories below the scan roots (50%) cannot be read") ||
+		!strings.Contains(s, "[/a /b /c]") {
+		t.Errorf("unexpected description %s", s)
+	}
+}
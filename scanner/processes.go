@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Commercial features of Oracle JDK 7 and 8, using them requires a license regardless of the version rules
+const (
+	featureUnlockCommercial   = "UnlockCommercialFeatures"
+	featureFlightRecorder     = "FlightRecorder"
+	featureResourceManagement = "ResourceManagement"
+	featureAppCDS             = "AppCDS"
+	featureMissionControl     = "MissionControl"
+)
+
+// commercialFlags maps command line options to the commercial feature they enable
+var commercialFlags = []struct {
+	prefix  string
+	feature string
+}{
+	{"-XX:+UnlockCommercialFeatures", featureUnlockCommercial},
+	{"-XX:+FlightRecorder", featureFlightRecorder},
+	{"-XX:StartFlightRecording", featureFlightRecorder},
+	{"-XX:FlightRecorderOptions", featureFlightRecorder},
+	{"-XX:+ResourceManagement", featureResourceManagement},
+	{"-XX:+UseAppCDS", featureAppCDS},
+}
+
+// javaProcess is a running process started from a runtime's bin directory
+type javaProcess struct {
+	PID         int
+	Executable  string
+	CommandLine string
+}
+
+// isJVMLauncher reports whether an executable name starts a JVM: java, javaw or Java Mission Control
+func isJVMLauncher(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+	return name == "java" || name == "javaw" || name == "jmc"
+}
+
+// commercialFeatures returns the commercial features used by a JVM process
+func commercialFeatures(p javaProcess) []string {
+	var features []string
+	for _, arg := range strings.Fields(p.CommandLine) {
+		for _, flag := range commercialFlags {
+			if strings.HasPrefix(arg, flag.prefix) && !containsString(features, flag.feature) {
+				features = append(features, flag.feature)
+			}
+		}
+	}
+	if strings.TrimSuffix(strings.ToLower(filepath.Base(p.Executable)), ".exe") == "jmc" {
+		features = append(features, featureMissionControl)
+	}
+	return features
+}
+
+// listJavaProcesses returns the running JVM processes visible to the scanning user
+func listJavaProcesses() ([]javaProcess, error) {
+	var processes []javaProcess
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		processes, err = procJavaProcesses("/proc")
+	case "windows":
+		processes, err = windowsJavaProcesses()
+	default:
+		processes, err = psJavaProcesses()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var jvms []javaProcess
+	for _, p := range processes {
+		if p.Executable != "" && isJVMLauncher(filepath.Base(p.Executable)) {
+			jvms = append(jvms, p)
+		}
+	}
+	return jvms, nil
+}
+
+// procJavaProcesses reads the executable and command line of all processes from /proc
+func procJavaProcesses(proc string) ([]javaProcess, error) {
+	entries, err := os.ReadDir(proc)
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []javaProcess
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// processes of other users are not readable without privileges
+		exe, err := os.Readlink(filepath.Join(proc, entry.Name(), "exe"))
+		if err != nil {
+			continue
+		}
+		cmdline, _ := os.ReadFile(filepath.Join(proc, entry.Name(), "cmdline"))
+		processes = append(processes, javaProcess{
+			PID:         pid,
+			Executable:  strings.TrimSuffix(exe, " (deleted)"),
+			CommandLine: strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " ")),
+		})
+	}
+	return processes, nil
+}
+
+// psJavaProcesses lists processes with ps(1), the executable is the first word of the command
+func psJavaProcesses() ([]javaProcess, error) {
+	out, err := exec.Command("ps", "-axww", "-o", "pid=,args=").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parsePSOutput(string(out)), nil
+}
+
+// parsePSOutput parses 'ps -o pid=,args=' lines
+func parsePSOutput(out string) []javaProcess {
+	var processes []javaProcess
+	for _, line := range splitLines(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		processes = append(processes, javaProcess{
+			PID:         pid,
+			Executable:  fields[1],
+			CommandLine: strings.Join(fields[1:], " "),
+		})
+	}
+	return processes
+}
+
+// windowsJavaProcesses lists processes with their command line using PowerShell and CIM
+func windowsJavaProcesses() ([]javaProcess, error) {
+	script := "Get-CimInstance Win32_Process -Filter \"Name='java.exe' OR Name='javaw.exe' OR Name='jmc.exe'\" | " +
+		"Select-Object ProcessId,ExecutablePath,CommandLine | ConvertTo-Json -Compress"
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseCIMProcesses(out)
+}
+
+// parseCIMProcesses parses ConvertTo-Json output, a single process is not wrapped in an array
+func parseCIMProcesses(out []byte) ([]javaProcess, error) {
+	type cimProcess struct {
+		ProcessId      int
+		ExecutablePath string
+		CommandLine    string
+	}
+
+	trimmed := strings.TrimSpace(string(out))
+	if trimmed == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(trimmed, "[") {
+		trimmed = "[" + trimmed + "]"
+	}
+	var cim []cimProcess
+	if err := json.Unmarshal([]byte(trimmed), &cim); err != nil {
+		return nil, err
+	}
+
+	processes := make([]javaProcess, 0, len(cim))
+	for _, p := range cim {
+		processes = append(processes, javaProcess{PID: p.ProcessId, Executable: p.ExecutablePath, CommandLine: p.CommandLine})
+	}
+	return processes, nil
+}
+
+// attachProcesses links running JVM processes to the runtime in the same bin directory
+// and records their commercial feature usage
+func attachProcesses(processes []javaProcess, results []*JavaResult) {
+	byBinDir := make(map[string]*JavaResult)
+	for _, result := range results {
+		byBinDir[canonicalPath(filepath.Dir(result.Path))] = result
+	}
+
+	for _, p := range processes {
+		result, ok := byBinDir[canonicalPath(filepath.Dir(p.Executable))]
+		if !ok {
+			continue
+		}
+		result.ProcessIDs = append(result.ProcessIDs, p.PID)
+		for _, feature := range commercialFeatures(p) {
+			if !containsString(result.CommercialFeatures, feature) {
+				result.CommercialFeatures = append(result.CommercialFeatures, feature)
+			}
+		}
+	}
+	for _, result := range results {
+		sort.Ints(result.ProcessIDs)
+		sort.Strings(result.CommercialFeatures)
+	}
+}
+
+// applyCommercialFeatures requires a license for Oracle JDK 7 and 8 runtimes running with commercial features,
+// these need a Java SE Advanced or Suite license independent of the version rules. Newer releases ship
+// Flight Recorder and Mission Control as open source.
+func (j *JavaRuntimeJSON) applyCommercialFeatures(features []string) {
+	if len(features) == 0 || !j.IsOracle || j.checkOpenJDK() || j.VersionMajor == 0 || j.VersionMajor > 8 {
+		return
+	}
+	j.CommercialFeatures = features
+	required := true
+	j.RequireLicense = &required
+	j.LicenseReason = "Commercial features in use by running processes: " + strings.Join(features, ", ")
+}
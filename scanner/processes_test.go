@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCommercialFeatures(t *testing.T) {
+	p := javaProcess{
+		Executable: "/opt/jdk1.8.0_202/bin/java",
+		CommandLine: "/opt/jdk1.8.0_202/bin/java -XX:+UnlockCommercialFeatures -XX:+FlightRecorder " +
+			"-XX:StartFlightRecording=duration=60s,filename=app.jfr -Xmx2g -jar app.jar",
+	}
+	expected := []string{featureUnlockCommercial, featureFlightRecorder}
+	if features := commercialFeatures(p); !reflect.DeepEqual(features, expected) {
+		t.Errorf("unexpected features %v", features)
+	}
+
+	jmc := javaProcess{Executable: "/opt/jdk1.8.0_202/bin/jmc"}
+	if features := commercialFeatures(jmc); !reflect.DeepEqual(features, []string{featureMissionControl}) {
+		t.Errorf("unexpected features %v", features)
+	}
+}
+
+func TestProcJavaProcesses(t *testing.T) {
+	proc := t.TempDir()
+	pidDir := filepath.Join(proc, "4242")
+	if err := os.Mkdir(pidDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/opt/jdk1.8.0_202/bin/java", filepath.Join(pidDir, "exe")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+	cmdline := "java\x00-XX:+UnlockCommercialFeatures\x00-jar\x00app.jar\x00"
+	if err := os.WriteFile(filepath.Join(pidDir, "cmdline"), []byte(cmdline), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(proc, "self"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	processes, err := procJavaProcesses(proc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 1 || processes[0].PID != 4242 || processes[0].Executable != "/opt/jdk1.8.0_202/bin/java" ||
+		processes[0].CommandLine != "java -XX:+UnlockCommercialFeatures -jar app.jar" {
+		t.Errorf("unexpected processes %+v", processes)
+	}
+}
+
+func TestParseProcessLists(t *testing.T) {
+	ps := parsePSOutput("  1 /sbin/launchd\n 812 /Library/Java/JavaVirtualMachines/jdk1.8.0_202.jdk/Contents/Home/bin/java -jar app.jar\n")
+	if len(ps) != 2 || ps[1].PID != 812 || ps[1].Executable != "/Library/Java/JavaVirtualMachines/jdk1.8.0_202.jdk/Contents/Home/bin/java" {
+		t.Errorf("unexpected ps processes %+v", ps)
+	}
+
+	cim, err := parseCIMProcesses([]byte(`{"ProcessId":7,"ExecutablePath":"C:\\jdk\\bin\\javaw.exe","CommandLine":"javaw -jar app.jar"}`))
+	if err != nil || len(cim) != 1 || cim[0].PID != 7 || cim[0].Executable != `C:\jdk\bin\javaw.exe` {
+		t.Errorf("unexpected CIM processes %+v, %v", cim, err)
+	}
+}
+
+func TestAttachProcesses(t *testing.T) {
+	jdk8 := &JavaResult{Path: "/opt/jdk1.8.0_202/bin/java"}
+	jdk17 := &JavaResult{Path: "/opt/jdk-17/bin/java"}
+	attachProcesses([]javaProcess{
+		{PID: 20, Executable: "/opt/jdk1.8.0_202/bin/java", CommandLine: "java -XX:+FlightRecorder -jar a.jar"},
+		{PID: 10, Executable: "/opt/jdk1.8.0_202/bin/jmc"},
+		{PID: 30, Executable: "/opt/jdk-17/bin/java", CommandLine: "java -XX:StartFlightRecording -jar b.jar"},
+		{PID: 40, Executable: "/usr/local/other/bin/java"},
+	}, []*JavaResult{jdk8, jdk17})
+
+	if !reflect.DeepEqual(jdk8.ProcessIDs, []int{10, 20}) ||
+		!reflect.DeepEqual(jdk8.CommercialFeatures, []string{featureFlightRecorder, featureMissionControl}) {
+		t.Errorf("unexpected JDK 8 processes %v, features %v", jdk8.ProcessIDs, jdk8.CommercialFeatures)
+	}
+
+	oracle8 := JavaRuntimeJSON{IsOracle: true, JavaRuntime: "Java(TM) SE Runtime Environment", VersionMajor: 8, VersionUpdate: 202}
+	oracle8.checkLicenseRequirement()
+	oracle8.applyCommercialFeatures(jdk8.CommercialFeatures)
+	if oracle8.RequireLicense == nil || !*oracle8.RequireLicense || len(oracle8.CommercialFeatures) != 2 {
+		t.Errorf("expected license requirement for commercial features, got %+v", oracle8)
+	}
+
+	// Flight Recorder is open source since JDK 11
+	oracle17 := JavaRuntimeJSON{IsOracle: true, JavaRuntime: "Java(TM) SE Runtime Environment", VersionMajor: 17}
+	oracle17.applyCommercialFeatures(jdk17.CommercialFeatures)
+	if oracle17.CommercialFeatures != nil {
+		t.Errorf("unexpected commercial features for JDK 17: %v", oracle17.CommercialFeatures)
+	}
+}
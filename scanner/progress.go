@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Default progress intervals on a terminal, where the report is updated in place, and for logs
+const (
+	interactiveProgressInterval = 1 * time.Second
+	logProgressInterval         = 30 * time.Second
+)
+
+// defaultProgressFormat is the template of the progress report
+const defaultProgressFormat = `Scanned {{comma .Scanned}} directories{{if gt .Roots 1}} of {{.Roots}} roots{{end}}, found {{.Found}} java executables after {{.Elapsed}}`
+
+// progressStatus is the data of the progress report template
+type progressStatus struct {
+	Scanned int64
+	Found   int64
+	Errors  int64
+	Roots   int
+	Elapsed time.Duration
+	// Path is the directory walked last and Last the java found last
+	Path string
+	Last string
+}
+
+// progressFuncs are the functions of the progress report template
+var progressFuncs = template.FuncMap{"comma": humanize.Comma}
+
+// parseProgressFormat compiles a progress report template, the default template if format is empty
+func parseProgressFormat(format string) (*template.Template, error) {
+	if format == "" {
+		format = defaultProgressFormat
+	}
+	tmpl, err := template.New("progress").Funcs(progressFuncs).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid progress format: %v", err)
+	}
+	// fields are only checked when the template is executed
+	if err := tmpl.Execute(io.Discard, progressStatus{}); err != nil {
+		return nil, fmt.Errorf("invalid progress format: %v", err)
+	}
+	return tmpl, nil
+}
+
+// progressReporter reports the progress of the finders of a scan periodically. On a terminal the report is updated
+// in place with a carriage return, otherwise (CI logs, redirected stderr) a plain log line is written per interval.
+// With JSON logs the progress is logged as a record with the counters per interval.
+type progressReporter struct {
+	finders     []*JavaFinder
+	interval    time.Duration
+	format      *template.Template
+	interactive bool
+	start       time.Time
+	done        chan struct{}
+	stopped     sync.WaitGroup
+	shown       bool
+	// width is the length of the longest report updated in place, shorter reports are padded to overwrite it
+	width int
+	// out receives the report, stderr or the log file
+	out io.Writer
+	// structured logs the report as a record instead of writing it to out
+	structured bool
+}
+
+// newProgressReporter creates the progress reporter of the finders. An interval of 0 selects the default interval,
+// a nil format the default template.
+func newProgressReporter(finders []*JavaFinder, interval time.Duration, format *template.Template) *progressReporter {
+	r := &progressReporter{finders: finders, interval: interval, format: format, interactive: interactiveLogs(),
+		done: make(chan struct{}), out: logOutput, structured: jsonLogs}
+	if r.interval <= 0 {
+		r.interval = logProgressInterval
+		if r.interactive {
+			r.interval = interactiveProgressInterval
+		}
+	}
+	if r.format == nil {
+		r.format, _ = parseProgressFormat("")
+	}
+	return r
+}
+
+// stderrIsTerminal reports whether stderr is a terminal
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// run starts reporting the progress until stop is called
+func (r *progressReporter) run() {
+	r.start = time.Now()
+	r.stopped.Add(1)
+	go func() {
+		defer r.stopped.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.report()
+			case <-r.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop ends the progress report, an updated report line is terminated
+func (r *progressReporter) stop() {
+	close(r.done)
+	r.stopped.Wait()
+	if r.shown && r.interactive {
+		fmt.Fprintln(r.out)
+	}
+}
+
+// status returns the combined progress of the finders
+func (r *progressReporter) status() progressStatus {
+	status := progressStatus{Roots: len(r.finders), Elapsed: time.Since(r.start).Round(time.Second)}
+	for _, finder := range r.finders {
+		status.Scanned += finder.scanned.Load()
+		status.Found += finder.found.Load()
+		status.Errors += finder.failed.Load()
+		if path := finder.lastScannedPath(); path != "" {
+			status.Path = path
+		}
+		if path := finder.last.Load(); path != nil {
+			status.Last = *path
+		}
+	}
+	return status
+}
+
+// report writes the progress of the finders
+func (r *progressReporter) report() {
+	status := r.status()
+	if r.structured {
+		slog.Info("progress", "scanned", status.Scanned, "found", status.Found, "errors", status.Errors,
+			"roots", status.Roots, "elapsed_seconds", int(status.Elapsed.Seconds()), "path", status.Path, "last", status.Last)
+		return
+	}
+	var buf bytes.Buffer
+	if err := r.format.Execute(&buf, status); err != nil {
+		return
+	}
+	// a report line is a single line
+	message := strings.ReplaceAll(buf.String(), "\n", " ")
+	r.shown = true
+
+	if !r.interactive {
+		fmt.Fprintf(r.out, "Progress: %s\n", message)
+		return
+	}
+	for _, finder := range r.finders {
+		// other messages start a new line
+		finder.ticker.Store(true)
+	}
+	padding := max(0, r.width-len(message))
+	r.width = max(r.width, len(message))
+	// no linefeed, so progress report stay on same output line
+	fmt.Fprintf(r.out, "\r%s%s", message, strings.Repeat(" ", padding))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReporter(t *testing.T) {
+	finders := []*JavaFinder{NewJavaFinder("/opt", -1, false), NewJavaFinder("/home", 3, false)}
+	finders[0].scanned.Store(1200)
+	finders[0].found.Store(2)
+	finders[1].scanned.Store(34)
+
+	reporter := newProgressReporter(finders, 0, nil)
+	if reporter.interactive && reporter.interval != interactiveProgressInterval ||
+		!reporter.interactive && reporter.interval != logProgressInterval {
+		t.Errorf("unexpected default interval %v", reporter.interval)
+	}
+
+	var out bytes.Buffer
+	reporter = newProgressReporter(finders, 10*time.Millisecond, nil)
+	reporter.out = &out
+	reporter.interactive = false
+	reporter.run()
+	time.Sleep(35 * time.Millisecond)
+	reporter.stop()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) < 2 || strings.Contains(out.String(), "\r") {
+		t.Fatalf("expected plain log lines, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[0], "Progress: Scanned 1,234 directories of 2 roots, found 2 java executables after ") {
+		t.Errorf("unexpected progress line %q", lines[0])
+	}
+	if finders[0].ticker.Load() {
+		t.Error("log lines need no new line before other messages")
+	}
+
+	out.Reset()
+	reporter = newProgressReporter(finders[:1], 10*time.Millisecond, nil)
+	reporter.out = &out
+	reporter.interactive = true
+	reporter.run()
+	time.Sleep(15 * time.Millisecond)
+	reporter.stop()
+	if !strings.HasPrefix(out.String(), "\rScanned 1,200 directories, found 2 java executables after ") || !strings.HasSuffix(out.String(), "s\n") {
+		t.Errorf("unexpected interactive progress %q", out.String())
+	}
+	if !finders[0].ticker.Load() {
+		t.Error("expected other messages to start a new line")
+	}
+}
+
+func TestProgressFormat(t *testing.T) {
+	if _, err := parseProgressFormat("{{.Scanned"); err == nil {
+		t.Error("expected an error for an unterminated action")
+	}
+	if _, err := parseProgressFormat("{{.Unknown}}"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+
+	format, err := parseProgressFormat("{{.Found}} found, last {{.Last}}, in {{.Path}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	finder := NewJavaFinder("/opt", -1, false)
+	finder.enterDir("/opt/jdk-17/lib")
+	finder.foundJava("/opt/jdk-17/bin/java")
+
+	var out bytes.Buffer
+	reporter := newProgressReporter([]*JavaFinder{finder}, time.Second, format)
+	reporter.out = &out
+	reporter.interactive = true
+	reporter.report()
+	finder.enterDir("/opt/x")
+	reporter.report()
+	want := "\r1 found, last /opt/jdk-17/bin/java, in /opt/jdk-17/lib" +
+		"\r1 found, last /opt/jdk-17/bin/java, in /opt/x" + strings.Repeat(" ", len("jdk-17/lib")-len("x"))
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
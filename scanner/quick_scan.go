@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// profileQuick runs only the targeted detectors and skips the walk
+const profileQuick = "quick"
+
+// scanProfiles are the names of the built-in scan profiles
+var scanProfiles = []string{profileQuick}
+
+// validateProfile checks the name of a scan profile
+func validateProfile(name string) error {
+	for _, profile := range scanProfiles {
+		if name == profile {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown scan profile '%s', supported: %s", name, strings.Join(scanProfiles, ", "))
+}
+
+// quickProfile resolves --quick, the shorthand of -profile quick, against the selected profile
+func quickProfile(profile string, quick bool) (string, error) {
+	if !quick || profile == profileQuick {
+		return profile, nil
+	}
+	if profile != "" {
+		return "", fmt.Errorf("--quick cannot be combined with -profile %s", profile)
+	}
+	return profileQuick, nil
+}
+
+// installationJavaPaths returns the java executables of an installation directory relative to it:
+// the JDK, the JRE of a JDK 8, and the home of a macOS bundle
+func installationJavaPaths() []string {
+	java := javaExecutableName()
+	return []string{
+		filepath.Join("bin", java),
+		filepath.Join("jre", "bin", java),
+		filepath.Join("Contents", "Home", "bin", java),
+	}
+}
+
+// installationCandidates lists the candidate java executables of the installations in dirs: the directories
+// themselves and their subdirectories two levels deep, which covers vendor directories like
+// Program Files\Eclipse Adoptium\jdk-17 and versioned layouts like Cellar/openjdk/21.0.1.
+// Linked subdirectories are aliases of other installations and skipped.
+func installationCandidates(dirs []string) []string {
+	var candidates []string
+	add := func(dir string) {
+		for _, java := range installationJavaPaths() {
+			candidates = append(candidates, filepath.Join(dir, java))
+		}
+	}
+	var subdirs func(dir string) []string
+	subdirs = func(dir string) []string {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil
+		}
+		var dirs []string
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, filepath.Join(dir, entry.Name()))
+			}
+		}
+		return dirs
+	}
+
+	for _, dir := range dirs {
+		add(dir)
+		for _, child := range subdirs(dir) {
+			add(child)
+			for _, grandchild := range subdirs(child) {
+				add(grandchild)
+			}
+		}
+	}
+	return candidates
+}
+
+// quickCandidates lists the java executables found by the targeted detectors of the quick profile: well-known
+// directories and the JDK caches of tool and version managers in homes, the installation directories registered
+// by installers (Windows registry, macOS package receipts), the java on PATH and JAVA_HOME.
+// Executables reached through links are resolved, so each installation is listed once.
+func quickCandidates(homes []string) []string {
+	dirs := likelyJavaDirs(runtime.GOOS, homes)
+	if programs, ok := detectInstalledPrograms(); ok {
+		for _, program := range programs {
+			if program.InstallLocation != "" && !program.LocationMissing {
+				dirs = append(dirs, program.InstallLocation)
+			}
+		}
+	}
+	candidates := installationCandidates(dirs)
+
+	if java, _ := resolveDefaultJava(); java != "" {
+		candidates = append(candidates, java)
+	}
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		for _, java := range installationJavaPaths() {
+			candidates = append(candidates, filepath.Join(home, java))
+		}
+	}
+
+	seen := make(map[string]bool)
+	var resolved []string
+	for _, candidate := range candidates {
+		target, err := filepath.EvalSymlinks(candidate)
+		if err != nil || seen[target] {
+			continue
+		}
+		seen[target] = true
+		resolved = append(resolved, target)
+	}
+	sort.Strings(resolved)
+	return resolved
+}
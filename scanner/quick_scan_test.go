@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestQuickCandidates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses HOME as home directory")
+	}
+
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	sdkman := filepath.Join(home, ".sdkman", "candidates", "java", "21.0.5-tem")
+	writeFakeJava(t, filepath.Join(sdkman, "bin"))
+	// version manager links like 'current' are aliases
+	if err := os.Symlink(sdkman, filepath.Join(home, ".sdkman", "candidates", "java", "current")); err != nil {
+		t.Fatal(err)
+	}
+	javaHome := filepath.Join(root, "apps", "jdk-17")
+	writeFakeJava(t, filepath.Join(javaHome, "bin"))
+	// not found by any detector
+	writeFakeJava(t, filepath.Join(root, "srv", "jdk-11", "bin"))
+
+	t.Setenv("JAVA_HOME", javaHome)
+	t.Setenv("PATH", filepath.Join(home, ".sdkman", "candidates", "java", "current", "bin"))
+
+	var found []string
+	for _, candidate := range quickCandidates([]string{home}) {
+		if isBelow(candidate, root) {
+			found = append(found, candidate)
+		}
+	}
+	want := []string{filepath.Join(javaHome, "bin", "java"), filepath.Join(sdkman, "bin", "java")}
+	if len(found) != 2 || found[0] != want[0] || found[1] != want[1] {
+		t.Errorf("quickCandidates() = %v, want %v", found, want)
+	}
+
+	finder := NewJavaFinder(root, -1, false)
+	finder.quick = true
+	finder.homes = []string{home}
+	results, err := finder.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || finder.scanned.Load() != 0 {
+		t.Errorf("expected 2 results without a walk, got %d results and %d scanned directories", len(results), finder.scanned.Load())
+	}
+}
+
+func TestInstallationCandidates(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "Eclipse Adoptium", "jdk-17.0.13.11-hotspot"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates := installationCandidates([]string{root})
+	// the directory, the vendor directory and the installation, each with three layouts
+	if len(candidates) != 9 {
+		t.Fatalf("expected 9 candidates, got %d", len(candidates))
+	}
+	want := filepath.Join(root, "Eclipse Adoptium", "jdk-17.0.13.11-hotspot", "bin", javaExecutableName())
+	if candidates[6] != want {
+		t.Errorf("expected %s, got %s", want, candidates[6])
+	}
+	if err := validateProfile("quick"); err != nil {
+		t.Error(err)
+	}
+	if err := validateProfile("deep"); err == nil {
+		t.Errorf("expected an error for an unknown profile")
+	}
+}
+
+func TestQuickProfile(t *testing.T) {
+	for _, tt := range []struct {
+		profile string
+		quick   bool
+		want    string
+	}{
+		{"", false, ""},
+		{"", true, profileQuick},
+		{profileQuick, true, profileQuick},
+		{"fleet", false, "fleet"},
+	} {
+		if got, err := quickProfile(tt.profile, tt.quick); err != nil || got != tt.want {
+			t.Errorf("quickProfile(%q, %v) = %q, %v, want %q", tt.profile, tt.quick, got, err, tt.want)
+		}
+	}
+	if _, err := quickProfile("fleet", true); err == nil {
+		t.Error("expected an error for --quick with another profile")
+	}
+}
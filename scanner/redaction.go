@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// redactionSeparator separates the pattern and the replacement of a redaction rule
+const redactionSeparator = "=>"
+
+// redactionRule replaces all matches of a regular expression in emitted paths
+type redactionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// redactor applies redaction rules in order
+type redactor []redactionRule
+
+// parseRedactionRule parses a rule of the form 'REGEX=>REPLACEMENT',
+// the replacement may reference capture groups with ${1}
+func parseRedactionRule(rule string) (redactionRule, error) {
+	idx := strings.Index(rule, redactionSeparator)
+	if idx == -1 {
+		return redactionRule{}, fmt.Errorf("invalid redaction rule %q, expected REGEX%sREPLACEMENT", rule, redactionSeparator)
+	}
+	pattern, err := regexp.Compile(rule[:idx])
+	if err != nil {
+		return redactionRule{}, fmt.Errorf("invalid redaction pattern %q: %v", rule[:idx], err)
+	}
+	return redactionRule{pattern: pattern, replacement: rule[idx+len(redactionSeparator):]}, nil
+}
+
+// loadRedactor builds a redactor from rules given on the command line and an optional rules file
+// with one rule per line ('#' starts a comment line)
+func loadRedactor(rules []string, file string) (redactor, error) {
+	if file != "" {
+		fileRules, err := readRedactionFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(fileRules, rules...)
+	}
+
+	r := make(redactor, 0, len(rules))
+	for _, rule := range rules {
+		parsed, err := parseRedactionRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, parsed)
+	}
+	return r, nil
+}
+
+func readRedactionFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading redaction rules: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var rules []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, line)
+	}
+	return rules, scanner.Err()
+}
+
+// path applies all rules to a path
+func (r redactor) path(path string) string {
+	if path == "" {
+		return path
+	}
+	for _, rule := range r {
+		path = rule.pattern.ReplaceAllString(path, rule.replacement)
+	}
+	return path
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	r, err := loadRedactor([]string{
+		`^/home/[^/]+/=>/home/REDACTED/`,
+		`/mnt/clients/([^/]+)/=>/mnt/clients/CLIENT/`,
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"/home/alice/jdk/bin/java":           "/home/REDACTED/jdk/bin/java",
+		"/mnt/clients/acme/app/jre/bin/java": "/mnt/clients/CLIENT/app/jre/bin/java",
+		"/opt/jdk/bin/java":                  "/opt/jdk/bin/java",
+	}
+	for path, want := range tests {
+		if got := r.path(path); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+
+	if _, err := loadRedactor([]string{"/home/"}, ""); err == nil {
+		t.Error("Expected error for rule without separator")
+	}
+}
+
+func TestRedactInstallProvenance(t *testing.T) {
+	r, err := loadRedactor([]string{`^/home/[^/]+/=>/home/REDACTED/`}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	programs := []InstalledProgram{{Name: "Eclipse Temurin JDK 17", InstallLocation: "/home/alice/.jdks/temurin-17"}}
+	output := JSONOutput{
+		Runtimes: []JavaRuntimeJSON{{JavaExecutable: "/home/alice/.jdks/temurin-17/bin/java", InstallProvenance: &programs[0]}},
+		Programs: programs,
+	}
+	output.rewritePaths(r.path)
+
+	if got := output.Runtimes[0].InstallProvenance.InstallLocation; got != "/home/REDACTED/.jdks/temurin-17" {
+		t.Errorf("expected the install location of the provenance to be redacted, got %s", got)
+	}
+	if got := output.Programs[0].InstallLocation; got != "/home/REDACTED/.jdks/temurin-17" {
+		t.Errorf("expected the install location of the program to be redacted, got %s", got)
+	}
+
+	// the provenance shares the program of the report, a rewrite must apply once to each
+	output = JSONOutput{
+		Runtimes: []JavaRuntimeJSON{{InstallProvenance: &programs[0]}},
+		Programs: programs,
+	}
+	output.rewritePaths(func(path string) string { return "/x" + path })
+	if got := output.Runtimes[0].InstallProvenance.InstallLocation; got != "/x/home/REDACTED/.jdks/temurin-17" {
+		t.Errorf("expected the install location of the provenance to be rewritten once, got %s", got)
+	}
+}
+
+func TestRedactScanPathList(t *testing.T) {
+	r, err := loadRedactor([]string{`^/home/[^/]+/=>/home/REDACTED/`}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sep := string(os.PathListSeparator)
+	output := JSONOutput{Meta: MetaInfo{ScanPath: "/home/alice/jdks" + sep + "/home/bob/tools"}}
+	output.rewritePaths(r.path)
+
+	if want := "/home/REDACTED/jdks" + sep + "/home/REDACTED/tools"; output.Meta.ScanPath != want {
+		t.Errorf("expected every root of the scan path to be redacted, got %s", output.Meta.ScanPath)
+	}
+}
+
+func TestRedactExecError(t *testing.T) {
+	r, err := loadRedactor([]string{`^/home/[^/]+/=>/home/REDACTED/`}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pseudonymizer{key: []byte("secret")}
+	runtime := JavaRuntimeJSON{
+		JavaExecutable: "/home/alice/jdk/bin/java",
+		JavaHome:       "/home/alice/jdk",
+		ExecFailed:     true,
+		ExecError:      "fork/exec /home/alice/jdk/bin/java: exec format error: /home/alice/jdk/lib/libjvm.so",
+	}
+
+	tests := map[string]func(string) string{
+		"/home/REDACTED/jdk/bin/java: exec format error: /home/REDACTED/jdk/lib/libjvm.so":                               r.path,
+		"/home/" + p.user("alice") + "/jdk/bin/java: exec format error: /home/" + p.user("alice") + "/jdk/lib/libjvm.so": p.path,
+	}
+	for want, fn := range tests {
+		output := JSONOutput{Runtimes: []JavaRuntimeJSON{runtime}}
+		output.rewritePaths(fn)
+		if got := output.Runtimes[0].ExecError; got != "fork/exec "+want {
+			t.Errorf("expected the paths of the exec error to be rewritten, got %s", got)
+		}
+	}
+}
+
+func TestRedactUsageTrackerDetail(t *testing.T) {
+	r, err := loadRedactor([]string{`^/home/[^/]+/=>/home/REDACTED/`}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &pseudonymizer{key: []byte("secret")}
+	evidence := EntitlementEvidence{
+		Type:   evidenceUsageTracker,
+		Path:   "/home/alice/jdk/lib/management/usagetracker.properties",
+		Detail: "logToServer=tracker.example.com:32139;logToFile=/home/alice/usage.log",
+	}
+
+	tests := map[string]func(string) string{
+		"logToServer=tracker.example.com:32139;logToFile=/home/REDACTED/usage.log":                r.path,
+		"logToServer=tracker.example.com:32139;logToFile=/home/" + p.user("alice") + "/usage.log": p.path,
+	}
+	for want, fn := range tests {
+		output := JSONOutput{Entitlements: []EntitlementEvidence{evidence}}
+		output.rewritePaths(fn)
+		if got := output.Entitlements[0].Detail; got != want {
+			t.Errorf("expected the log file of the usage tracker to be rewritten, got %s", got)
+		}
+		if got := output.Entitlements[0].Path; got != fn(evidence.Path) {
+			t.Errorf("expected the path of the evidence to be rewritten, got %s", got)
+		}
+	}
+}
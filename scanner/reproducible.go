@@ -0,0 +1,54 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// makeReproducible prepares a report for -reproducible, so two scans of an unchanged host produce identical reports:
+// the volatile fields (timestamp, durations, directory counts, process IDs) are omitted, paths use forward slashes
+// and all sections are sorted by path.
+func (o *JSONOutput) makeReproducible() {
+	o.Meta.ScanTimestamp = ""
+	o.Meta.ScanDuration = ""
+	o.Meta.ScannedDirs = 0
+	if o.Meta.Roots != nil {
+		o.Meta.Roots = append([]RootStats(nil), o.Meta.Roots...)
+		for i := range o.Meta.Roots {
+			o.Meta.Roots[i].Duration = ""
+			o.Meta.Roots[i].ScannedDirs = 0
+		}
+	}
+
+	// the other sections are shared with the configuration of the scan, they are rewritten and sorted as copies
+	o.Profiles = append([]UserProfile(nil), o.Profiles...)
+	o.Installers = append([]InstallerArtifact(nil), o.Installers...)
+	o.Suspected = append([]SuspectedInstall(nil), o.Suspected...)
+	o.Applications = append([]JPackageApp(nil), o.Applications...)
+	o.Legacy = append([]LegacyComponent(nil), o.Legacy...)
+	o.BaselineMissing = append([]BaselineEntry(nil), o.BaselineMissing...)
+	o.rewritePaths(filepath.ToSlash)
+
+	for i := range o.Runtimes {
+		o.Runtimes[i].ProcessIDs = nil
+	}
+	sort.Slice(o.Runtimes, func(i, j int) bool { return o.Runtimes[i].JavaExecutable < o.Runtimes[j].JavaExecutable })
+	sort.Slice(o.Entitlements, func(i, j int) bool {
+		a, b := o.Entitlements[i], o.Entitlements[j]
+		return a.Path < b.Path || a.Path == b.Path && a.Type < b.Type
+	})
+	sort.Slice(o.Programs, func(i, j int) bool {
+		a, b := o.Programs[i], o.Programs[j]
+		return a.Name < b.Name || a.Name == b.Name && a.InstallLocation < b.InstallLocation
+	})
+	sort.Slice(o.Errors, func(i, j int) bool { return o.Errors[i].Path < o.Errors[j].Path })
+	sort.Slice(o.Profiles, func(i, j int) bool { return o.Profiles[i].Path < o.Profiles[j].Path })
+	sort.Slice(o.Installers, func(i, j int) bool { return o.Installers[i].Path < o.Installers[j].Path })
+	sort.Slice(o.Suspected, func(i, j int) bool { return o.Suspected[i].Path < o.Suspected[j].Path })
+	sort.Slice(o.Applications, func(i, j int) bool { return o.Applications[i].Path < o.Applications[j].Path })
+	sort.Slice(o.Legacy, func(i, j int) bool {
+		a, b := o.Legacy[i], o.Legacy[j]
+		return a.Path < b.Path || a.Path == b.Path && a.Kind < b.Kind
+	})
+	sort.Slice(o.BaselineMissing, func(i, j int) bool { return o.BaselineMissing[i].key() < o.BaselineMissing[j].key() })
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMakeReproducible(t *testing.T) {
+	profiles := []UserProfile{{User: "bob", Path: "/home/bob"}, {User: "alice", Path: "/home/alice"}}
+	first := &JSONOutput{
+		Meta: MetaInfo{ScanTimestamp: "2026-10-16T08:00:00Z", ScanDuration: "12.5s", ScannedDirs: 48211, ComputerName: "build01",
+			Roots: []RootStats{{Path: "/opt", ScannedDirs: 100, Duration: "1s", CountResult: 2}}},
+		Runtimes: []JavaRuntimeJSON{
+			{JavaExecutable: "/opt/jdk-21/bin/java", ProcessIDs: []int{4711}},
+			{JavaExecutable: "/opt/jdk-17/bin/java"},
+		},
+		Errors:   []PathError{{Path: "/opt/z"}, {Path: "/opt/a"}},
+		Profiles: profiles,
+	}
+	second := &JSONOutput{
+		Meta: MetaInfo{ScanTimestamp: "2026-10-17T08:00:00Z", ScanDuration: "9.1s", ScannedDirs: 48215, ComputerName: "build01",
+			Roots: []RootStats{{Path: "/opt", ScannedDirs: 104, Duration: "2s", CountResult: 2}}},
+		Runtimes: []JavaRuntimeJSON{
+			{JavaExecutable: "/opt/jdk-17/bin/java"},
+			{JavaExecutable: "/opt/jdk-21/bin/java"},
+		},
+		Errors:   []PathError{{Path: "/opt/a"}, {Path: "/opt/z"}},
+		Profiles: []UserProfile{profiles[1], profiles[0]},
+	}
+
+	first.makeReproducible()
+	second.makeReproducible()
+	a, _ := json.Marshal(first)
+	b, _ := json.Marshal(second)
+	if string(a) != string(b) {
+		t.Errorf("expected identical reports:\n%s\n%s", a, b)
+	}
+	if first.Runtimes[0].JavaExecutable != "/opt/jdk-17/bin/java" || first.Meta.ScanTimestamp != "" || first.Runtimes[1].ProcessIDs != nil {
+		t.Errorf("unexpected report %s", a)
+	}
+	if profiles[0].User != "bob" {
+		t.Error("expected the profiles of the scan to stay unsorted")
+	}
+
+	// the parts of a split report share a scan ID derived from the results
+	a, _ = json.Marshal(splitOutput(first, 1, reproducibleScanID(first)))
+	b, _ = json.Marshal(splitOutput(second, 1, reproducibleScanID(second)))
+	if string(a) != string(b) {
+		t.Errorf("expected identical parts:\n%s\n%s", a, b)
+	}
+	second.Runtimes = second.Runtimes[:1]
+	if reproducibleScanID(first) == reproducibleScanID(second) {
+		t.Error("expected different results to have different scan IDs")
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RootStats are the statistics of a scan root, reported for scans of several roots so slow roots (usually network
+// mounts) can be identified and excluded or scheduled separately
+type RootStats struct {
+	Path        string `json:"path"`
+	ScannedDirs int    `json:"scanned_dirs"`
+	Duration    string `json:"duration"`
+	CountResult int    `json:"count_result"`
+	ErrorCount  int    `json:"error_count"`
+	// Truncated is set when the walk of the root was ended by --max-duration or an interrupt
+	Truncated       bool   `json:"truncated,omitempty"`
+	LastScannedPath string `json:"last_scanned_path,omitempty"`
+}
+
+// rootConfig is a scan root of the configuration file, depth, excludes and profile override the settings of the scan
+type rootConfig struct {
+	Path    string   `yaml:"path"`
+	Depth   *int     `yaml:"depth"`
+	Exclude []string `yaml:"exclude"`
+	Profile string   `yaml:"profile"`
+}
+
+// scanRoot is a root of the scan with its walk settings
+type scanRoot struct {
+	path     string
+	maxDepth int
+	excludes []string
+	profile  string
+	threads  string
+	// optional roots are skipped if missing on this host
+	optional bool
+}
+
+// scanRoot returns a root walked with the settings of the scan
+func (c config) scanRoot(path string) scanRoot {
+	return scanRoot{path: path, maxDepth: c.maxDepth, excludes: c.excludes, profile: c.profile, threads: c.threads}
+}
+
+// splitPathList splits -path values at commas, empty entries are dropped
+func splitPathList(values []string) []string {
+	var paths []string
+	for _, value := range values {
+		for _, path := range strings.Split(value, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// scanRoots returns the roots of the configuration file. A root starts with the settings of base, its profile and
+// then its own depth and excludes override them, excludes are added to the excludes of the scan.
+func (c *fileConfig) scanRoots(base scanRoot) ([]scanRoot, error) {
+	var roots []scanRoot
+	for _, rc := range c.Roots {
+		if rc.Path == "" {
+			return nil, fmt.Errorf("scan root without path")
+		}
+		root := base
+		root.path = rc.Path
+		root.optional = true
+		root.excludes = append([]string{}, base.excludes...)
+		if rc.Profile != "" {
+			if err := c.applyRootProfile(&root, rc.Profile); err != nil {
+				return nil, err
+			}
+		}
+		if rc.Depth != nil {
+			root.maxDepth = *rc.Depth
+		}
+		for _, pattern := range rc.Exclude {
+			if err := validateExclude(pattern); err != nil {
+				return nil, fmt.Errorf("root %s: %v", rc.Path, err)
+			}
+			root.excludes = append(root.excludes, pattern)
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// applyRootProfile applies a profile to a root. Only the walk settings depth, exclude and threads of a profile can
+// differ per root, the other settings apply to the whole scan.
+func (c *fileConfig) applyRootProfile(root *scanRoot, name string) error {
+	settings, ok := c.Profiles[name]
+	if !ok && validateProfile(name) != nil {
+		return fmt.Errorf("root %s: unknown scan profile '%s', available: %s", root.path, name, strings.Join(c.profileNames(), ", "))
+	}
+	root.profile = name
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values, isList := settings[key].([]any)
+		if !isList {
+			values = []any{settings[key]}
+		}
+		switch key {
+		case "depth":
+			depth, err := strconv.Atoi(fmt.Sprint(settings[key]))
+			if err != nil {
+				return fmt.Errorf("profile '%s': invalid depth: %v", name, err)
+			}
+			root.maxDepth = depth
+		case "exclude":
+			for _, value := range values {
+				pattern := fmt.Sprint(value)
+				if err := validateExclude(pattern); err != nil {
+					return fmt.Errorf("profile '%s': %v", name, err)
+				}
+				root.excludes = append(root.excludes, pattern)
+			}
+		case "threads":
+			root.threads = fmt.Sprint(settings[key])
+		default:
+			return fmt.Errorf("root %s: setting '%s' of profile '%s' applies to the whole scan and cannot be set per root", root.path, key, name)
+		}
+	}
+	return nil
+}
+
+// rootScan is the outcome of the scan of a root
+type rootScan struct {
+	results  []*JavaResult
+	duration time.Duration
+	err      error
+}
+
+// findRoots runs the finders of the roots concurrently and returns their outcome in the order of the roots
+func findRoots(finders []*JavaFinder) []rootScan {
+	scans := make([]rootScan, len(finders))
+
+	var wg sync.WaitGroup
+	for i, finder := range finders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			results, err := finder.Find()
+			scans[i] = rootScan{results: results, duration: time.Since(start), err: err}
+		}()
+	}
+	wg.Wait()
+	return scans
+}
+
+// mergeRootResults merges the results of the roots, a java found below several overlapping roots or through symlinks
+// is reported once
+func mergeRootResults(scans []rootScan) []*JavaResult {
+	var results []*JavaResult
+	for _, scan := range scans {
+		results = append(results, scan.results...)
+	}
+	return mergeHardLinks(mergeLinkedResults(results))
+}
+
+// rootStats returns the statistics of the root scanned by a finder
+func (f *JavaFinder) rootStats(found int, duration time.Duration) RootStats {
+	stats := RootStats{
+		Path:        f.startPath,
+		ScannedDirs: int(f.scanned.Load()),
+		Duration:    formatDurationISO8601(duration),
+		CountResult: found,
+		ErrorCount:  int(f.failed.Load()),
+	}
+	if f.truncated {
+		stats.Truncated = true
+		stats.LastScannedPath = f.lastScannedPath()
+	}
+	return stats
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRootStats(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	root := t.TempDir()
+	writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		log := newErrorLog(10)
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		finder.errors = log
+		start := time.Now()
+		found, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.recordError(filepath.Join(root, "secret"), &os.PathError{Op: "open", Path: "secret", Err: os.ErrPermission})
+
+		stats := finder.rootStats(len(found), time.Since(start))
+		if stats.Path != root || stats.ScannedDirs != 3 || stats.CountResult != 1 || stats.ErrorCount != 1 {
+			t.Errorf("threads %d: unexpected root stats %+v", threads, stats)
+		}
+		if stats.Duration == "" {
+			t.Errorf("threads %d: missing duration", threads)
+		}
+		if counts := log.classCounts(); counts[errorClassPermission] != 1 {
+			t.Errorf("threads %d: error not recorded in the error log: %v", threads, counts)
+		}
+	}
+}
+
+func TestRewriteRootPaths(t *testing.T) {
+	roots := []RootStats{{Path: "/home/alice"}, {Path: "/opt"}}
+	output := &JSONOutput{Meta: MetaInfo{Roots: roots}}
+	output.rewritePaths(func(path string) string {
+		if path == "/home/alice" {
+			return "/home/user-1"
+		}
+		return path
+	})
+	if output.Meta.Roots[0].Path != "/home/user-1" || output.Meta.Roots[1].Path != "/opt" {
+		t.Errorf("unexpected root paths %+v", output.Meta.Roots)
+	}
+	if roots[0].Path != "/home/alice" {
+		t.Error("rewriting modified the collected root stats")
+	}
+}
+
+func TestScanRoots(t *testing.T) {
+	depth := 3
+	file := &fileConfig{
+		Profiles: map[string]map[string]any{
+			"home":  {"exclude": []any{".cache"}, "threads": 2},
+			"fleet": {"eval": true},
+		},
+		Roots: []rootConfig{
+			{Path: "/opt"},
+			{Path: "/home", Depth: &depth, Exclude: []string{"node_modules"}, Profile: "home"},
+			{Path: "/srv", Profile: profileQuick},
+		},
+	}
+	base := scanRoot{maxDepth: -1, excludes: []string{".git"}}
+	roots, err := file.scanRoots(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scanRoot{
+		{path: "/opt", maxDepth: -1, excludes: []string{".git"}, optional: true},
+		{path: "/home", maxDepth: 3, excludes: []string{".git", ".cache", "node_modules"}, profile: "home", threads: "2", optional: true},
+		{path: "/srv", maxDepth: -1, excludes: []string{".git"}, profile: profileQuick, optional: true},
+	}
+	if !reflect.DeepEqual(roots, want) {
+		t.Errorf("unexpected roots\n got %+v\nwant %+v", roots, want)
+	}
+	if len(base.excludes) != 1 {
+		t.Errorf("root excludes modified the excludes of the scan: %v", base.excludes)
+	}
+
+	for _, rc := range []rootConfig{{Path: "/opt", Profile: "fleet"}, {Path: "/opt", Profile: "missing"}, {Depth: &depth}} {
+		file.Roots = []rootConfig{rc}
+		if _, err := file.scanRoots(base); err == nil {
+			t.Errorf("expected an error for root %+v", rc)
+		}
+	}
+}
+
+func TestFindRoots(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	root := t.TempDir()
+	writeFakeJava(t, filepath.Join(root, "opt", "jdk-21", "bin"))
+	writeFakeJava(t, filepath.Join(root, "home", "alice", "a", "b", "jdk-17", "bin"))
+	writeFakeJava(t, filepath.Join(root, "home", "alice", "jdk-11", "bin"))
+
+	finders := []*JavaFinder{
+		NewJavaFinder(filepath.Join(root, "opt"), -1, false),
+		NewJavaFinder(filepath.Join(root, "home"), 4, false),
+		// overlaps the home root, its results are merged
+		NewJavaFinder(filepath.Join(root, "home", "alice"), -1, false),
+	}
+	scans := findRoots(finders)
+	for i, scan := range scans {
+		if scan.err != nil {
+			t.Fatalf("root %d: %v", i, scan.err)
+		}
+	}
+	if len(scans[0].results) != 1 || len(scans[1].results) != 1 || len(scans[2].results) != 2 {
+		t.Errorf("unexpected results per root: %d, %d, %d", len(scans[0].results), len(scans[1].results), len(scans[2].results))
+	}
+
+	var paths []string
+	for _, result := range mergeRootResults(scans) {
+		paths = append(paths, result.Path)
+	}
+	want := []string{
+		filepath.Join(root, "opt", "jdk-21", "bin", "java"),
+		filepath.Join(root, "home", "alice", "jdk-11", "bin", "java"),
+		filepath.Join(root, "home", "alice", "a", "b", "jdk-17", "bin", "java"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("unexpected merged results %v", paths)
+	}
+}
+
+func TestSplitPathList(t *testing.T) {
+	got := splitPathList([]string{"/usr", "/opt, /home,", "C:\\Program Files"})
+	want := []string{"/usr", "/opt", "/home", "C:\\Program Files"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitPathList = %q, want %q", got, want)
+	}
+	if got := splitPathList(nil); got != nil {
+		t.Errorf("expected no paths, got %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// oracleJDKRuntimeName is the java.runtime.name of Oracle JDK/JRE builds
+const oracleJDKRuntimeName = "Java(TM) SE Runtime Environment"
+
+type rulesCheckConfig struct {
+	vendor      string
+	version     string
+	versionDate string
+	runtimeName string
+	jsonOutput  bool
+	help        bool
+}
+
+// runRules implements the 'rules' subcommand
+func runRules(args []string) int {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s rules show\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s rules check --vendor <vendor> --version <version> [options]\n", os.Args[0])
+	}
+	if len(args) == 0 {
+		usage()
+		return 1
+	}
+
+	switch args[0] {
+	case "show":
+		showRules()
+		return 0
+	case "check":
+		return runRulesCheck(args[1:])
+	case "-h", "-help", "--help":
+		usage()
+		return 0
+	}
+	usage()
+	return 1
+}
+
+// runRulesCheck runs the license rules on supplied runtime attributes, without scanning
+func runRulesCheck(args []string) int {
+	var config rulesCheckConfig
+
+	flags := flag.NewFlagSet("rules check", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s rules check --vendor <vendor> --version <version> [options]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Check the license requirement of a Java runtime described by its properties.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&config.vendor, "vendor", "", "Value of java.vendor, e.g. 'Oracle Corporation' (required)")
+	flags.StringVar(&config.version, "version", "", "Value of java.version, e.g. 1.8.0_311 or 17.0.13 (required)")
+	flags.StringVar(&config.versionDate, "version-date", "", "Value of java.version.date (default: estimated from the version)")
+	flags.StringVar(&config.runtimeName, "runtime-name", "", "Value of java.runtime.name (default: '"+oracleJDKRuntimeName+"' for Oracle)")
+	flags.BoolVar(&config.jsonOutput, "json", false, "Output the verdict in JSON format")
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+	_ = flags.Parse(args)
+
+	if config.help || config.vendor == "" || config.version == "" {
+		flags.Usage()
+		return 1
+	}
+
+	runtime := config.runtime()
+	if config.jsonOutput {
+		data, err := json.MarshalIndent(runtime, "", "  ")
+		if err != nil {
+			slog.Error("marshaling JSON", "error", err)
+			return 1
+		}
+		fmt.Println(string(data))
+		return 0
+	}
+
+	printf("Vendor: %s\n", runtime.JavaVendor)
+	printf("Runtime name: %s\n", runtime.JavaRuntime)
+	printf("Version: %s (major %d, update %d)\n", runtime.JavaVersion, runtime.VersionMajor, runtime.VersionUpdate)
+	if runtime.Distribution != "" {
+		printf("Distribution: %s\n", runtime.Distribution)
+	}
+	if *runtime.RequireLicense {
+		printf("Verdict: requires a commercial license\n")
+	} else {
+		printf("Verdict: no commercial license required\n")
+	}
+	printf("Rationale: %s\n", runtime.LicenseReason)
+	if runtime.LicenseFreeUntil != "" {
+		printf("Free use under NFTC ends for updates released after %s\n", runtime.LicenseFreeUntil)
+	}
+	return 0
+}
+
+// runtime builds the runtime description checked by the license rules
+func (c rulesCheckConfig) runtime() JavaRuntimeJSON {
+	runtime := JavaRuntimeJSON{
+		JavaVendor:      c.vendor,
+		JavaVersion:     c.version,
+		JavaVersionDate: c.versionDate,
+		JavaRuntime:     c.runtimeName,
+		IsOracle:        strings.Contains(c.vendor, "Oracle"),
+	}
+	if runtime.JavaRuntime == "" && runtime.IsOracle {
+		runtime.JavaRuntime = oracleJDKRuntimeName
+	}
+	runtime.VersionMajor, runtime.VersionUpdate = parseJavaVersion(c.version)
+	runtime.Distribution = normalizeDistribution(runtime.JavaVendor, runtime.JavaRuntime)
+	runtime.checkLicenseRequirement()
+	return runtime
+}
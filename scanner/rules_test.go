@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestRulesCheckRuntime(t *testing.T) {
+	runtime := rulesCheckConfig{vendor: "Oracle Corporation", version: "1.8.0_311"}.runtime()
+	if runtime.JavaRuntime != oracleJDKRuntimeName || !*runtime.RequireLicense {
+		t.Errorf("Expected Oracle JDK 8u311 to require a license, got %v (%s)", *runtime.RequireLicense, runtime.JavaRuntime)
+	}
+
+	runtime = rulesCheckConfig{vendor: "Oracle Corporation", version: "21.0.1", runtimeName: "OpenJDK Runtime Environment"}.runtime()
+	if *runtime.RequireLicense || runtime.Distribution != distOracleOpenJDK {
+		t.Errorf("Expected Oracle OpenJDK to be free, got %v (%s)", *runtime.RequireLicense, runtime.Distribution)
+	}
+
+	runtime = rulesCheckConfig{vendor: "Oracle Corporation", version: "17.0.13", versionDate: "2024-10-15"}.runtime()
+	if !*runtime.RequireLicense || runtime.LicenseFreeUntil != "2024-09-30" {
+		t.Errorf("Expected Oracle JDK 17.0.13 to require a license, got %v", *runtime.RequireLicense)
+	}
+
+	runtime = rulesCheckConfig{vendor: "Azul Systems, Inc.", version: "1.8.0_311"}.runtime()
+	if *runtime.RequireLicense || runtime.JavaRuntime != "" {
+		t.Error("Expected Zulu to be free")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// sarifLicenseRule is the rule of the findings of a SARIF report, an Oracle runtime requiring a license
+const sarifLicenseRule = "oracle-license-required"
+
+// sarifLog is a SARIF 2.1.0 log as accepted by GitHub Code Scanning and Azure DevOps
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool         `json:"tool"`
+	Results    []sarifResult     `json:"results"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	Name                 string            `json:"name"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	FullDescription      sarifMessage      `json:"fullDescription"`
+	Help                 sarifMessage      `json:"help"`
+	DefaultConfiguration sarifRuleDefaults `json:"defaultConfiguration"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// renderSARIF renders every runtime requiring a license as a finding of a SARIF log
+func renderSARIF(output *JSONOutput, _ renderOptions) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name: "jfind",
+			Rules: []sarifRule{{
+				ID:               sarifLicenseRule,
+				Name:             "OracleLicenseRequired",
+				ShortDescription: sarifMessage{Text: "Oracle Java runtime requiring a license"},
+				FullDescription: sarifMessage{Text: "An Oracle Java runtime was found whose version is not covered by a " +
+					"free use license (BCL, NFTC or OTN terms), its commercial use requires a Java SE subscription."},
+				Help: sarifMessage{Text: "Remove the runtime, replace it with an OpenJDK distribution or record the " +
+					"subscription covering the host."},
+				DefaultConfiguration: sarifRuleDefaults{Level: "error"},
+			}},
+		}},
+		Results: []sarifResult{},
+		Properties: map[string]string{
+			"computer_name": output.Meta.ComputerName,
+			"scan_ts":       output.Meta.ScanTimestamp,
+			"scan_path":     output.Meta.ScanPath,
+		},
+	}
+
+	for _, runtime := range output.Runtimes {
+		if runtime.RequireLicense == nil || !*runtime.RequireLicense {
+			continue
+		}
+		message := "Oracle Java " + runtime.JavaVersion + " requires a license"
+		if runtime.LicenseReason != "" {
+			message += ": " + runtime.LicenseReason
+		}
+		// the fingerprint tracks the finding across scans of the host
+		fingerprint := sha256.Sum256([]byte(output.Meta.ComputerName + "\x00" + runtime.JavaExecutable))
+		properties := map[string]any{
+			"computer_name": output.Meta.ComputerName,
+			"java_home":     runtime.JavaHome,
+			"java_version":  runtime.JavaVersion,
+			"java_vendor":   runtime.JavaVendor,
+			"distribution":  runtime.Distribution,
+		}
+		if runtime.LicenseFreeUntil != "" {
+			properties["license_free_until"] = runtime.LicenseFreeUntil
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  sarifLicenseRule,
+			Level:   "error",
+			Message: sarifMessage{Text: message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: fileURI(runtime.JavaExecutable)},
+			}}},
+			PartialFingerprints: map[string]string{"jfindRuntime/v1": hex.EncodeToString(fingerprint[:])},
+			Properties:          properties,
+		})
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+// fileURI returns the file URI of an absolute path, C:\jdk becomes file:///C:/jdk
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRenderSARIF(t *testing.T) {
+	data, err := renderSARIF(testOutput(), renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected a SARIF 2.1.0 log with one run, got %s", data)
+	}
+
+	// only the runtime requiring a license is a finding
+	results := log.Runs[0].Results
+	if len(results) != 1 {
+		t.Fatalf("expected 1 finding, got %d", len(results))
+	}
+	result := results[0]
+	if result.RuleID != sarifLicenseRule || result.Level != "error" {
+		t.Errorf("unexpected rule %s or level %s", result.RuleID, result.Level)
+	}
+	if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "file:///opt/jdk-17/bin/java" {
+		t.Errorf("unexpected location %s", uri)
+	}
+	if result.PartialFingerprints["jfindRuntime/v1"] == "" {
+		t.Errorf("expected a fingerprint")
+	}
+	if result.Message.Text != "Oracle Java 17.0.13 requires a license: Released 2024-10-15, after the NFTC free use window ended on 2024-09-30" {
+		t.Errorf("unexpected message %q", result.Message.Text)
+	}
+}
+
+func TestFileURI(t *testing.T) {
+	for path, want := range map[string]string{
+		"/opt/jdk 17/bin/java":  "file:///opt/jdk%2017/bin/java",
+		"C:/Program Files/Java": "file:///C:/Program%20Files/Java",
+	} {
+		if uri := fileURI(path); uri != want {
+			t.Errorf("fileURI(%q) = %s, want %s", path, uri, want)
+		}
+	}
+}
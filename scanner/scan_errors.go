@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Classes of errors skipping a path during the scan
+const (
+	errorClassPermission = "permission"
+	errorClassTimeout    = "timeout"
+	errorClassNotFound   = "not-found"
+	errorClassIO         = "io"
+)
+
+// defaultMaxErrors is the default number of error records included in the JSON output
+const defaultMaxErrors = 100
+
+// PathError records a path skipped by the scan
+type PathError struct {
+	Path  string `json:"path"`
+	Class string `json:"class"`
+	Error string `json:"error"`
+}
+
+// errorLog counts the errors of a scan by class and keeps a uniform random sample of at most limit records,
+// so systematic coverage gaps show up even in scans with many thousand errors
+type errorLog struct {
+	mu     sync.Mutex
+	limit  int
+	total  int
+	counts map[string]int
+	sample []PathError
+	rng    *rand.Rand
+}
+
+// newErrorLog creates an error log keeping at most limit records
+func newErrorLog(limit int) *errorLog {
+	return &errorLog{limit: limit, counts: make(map[string]int), rng: rand.New(rand.NewSource(1))}
+}
+
+// errorClass classifies an error skipping a path
+func errorClass(err error) string {
+	switch {
+	case os.IsPermission(err):
+		return errorClassPermission
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return errorClassTimeout
+	case os.IsNotExist(err):
+		return errorClassNotFound
+	default:
+		return errorClassIO
+	}
+}
+
+// record counts an error and samples it with reservoir sampling, a nil log ignores errors
+func (l *errorLog) record(path string, err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// the message of a *fs.PathError repeats the path, which would bypass path redaction
+	message := err.Error()
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		message = pathErr.Err.Error()
+	}
+	rec := PathError{Path: path, Class: errorClass(err), Error: message}
+	l.counts[rec.Class]++
+	l.total++
+	if len(l.sample) < l.limit {
+		l.sample = append(l.sample, rec)
+	} else if i := l.rng.Intn(l.total); i < l.limit {
+		l.sample[i] = rec
+	}
+}
+
+// records returns the sampled error records sorted by path
+func (l *errorLog) records() []PathError {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	records := append([]PathError(nil), l.sample...)
+	sort.Slice(records, func(i, j int) bool { return records[i].Path < records[j].Path })
+	return records
+}
+
+// classCounts returns the number of errors by class, nil if there were none
+func (l *errorLog) classCounts() map[string]int {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.total == 0 {
+		return nil
+	}
+	counts := make(map[string]int, len(l.counts))
+	for class, n := range l.counts {
+		counts[class] = n
+	}
+	return counts
+}
+
+// printErrorSummary prints the error counts by class in text output mode
+func printErrorSummary(counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	classes := make([]string, 0, len(counts))
+	total := 0
+	for class, n := range counts {
+		classes = append(classes, class)
+		total += n
+	}
+	sort.Strings(classes)
+	printf("Skipped paths: %d", total)
+	for _, class := range classes {
+		printf(", %s %d", class, counts[class])
+	}
+	printf("\n")
+}
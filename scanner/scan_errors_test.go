@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestErrorClass(t *testing.T) {
+	tests := map[error]string{
+		&fs.PathError{Op: "open", Path: "/root", Err: fs.ErrPermission}:          errorClassPermission,
+		&fs.PathError{Op: "stat", Path: "/mnt/nfs", Err: os.ErrDeadlineExceeded}: errorClassTimeout,
+		&fs.PathError{Op: "lstat", Path: "/proc/1/fd/5", Err: fs.ErrNotExist}:    errorClassNotFound,
+		fmt.Errorf("input/output error"):                                         errorClassIO,
+	}
+	for err, expected := range tests {
+		if class := errorClass(err); class != expected {
+			t.Errorf("errorClass(%v) = %s, expected %s", err, class, expected)
+		}
+	}
+}
+
+func TestErrorLogSampling(t *testing.T) {
+	l := newErrorLog(10)
+	for i := 0; i < 1000; i++ {
+		l.record(fmt.Sprintf("/data/%04d", i), &fs.PathError{Op: "open", Path: fmt.Sprintf("/data/%04d", i), Err: fs.ErrPermission})
+	}
+	l.record("/data/io", fmt.Errorf("input/output error"))
+
+	records := l.records()
+	if len(records) != 10 {
+		t.Fatalf("expected 10 sampled records, got %d", len(records))
+	}
+	if records[0].Error != "permission denied" {
+		t.Errorf("expected the message without the path, got %q", records[0].Error)
+	}
+	// a uniform sample of 1001 records rarely consists of the first ten only
+	if records[9].Path == "/data/0009" {
+		t.Errorf("expected a sample of all records, got %v", records)
+	}
+	if counts := l.classCounts(); counts[errorClassPermission] != 1000 || counts[errorClassIO] != 1 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+
+	var nilLog *errorLog
+	nilLog.record("/x", fs.ErrPermission)
+	if nilLog.records() != nil || nilLog.classCounts() != nil {
+		t.Error("nil log must ignore errors")
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// reportSchemaVersion is the version of the JSON report structure. It is raised when fields are removed or change
+// their type, added fields keep the version.
+const reportSchemaVersion = 1
+
+// reportSchema is the JSON Schema of the JSON report, generated from JSONOutput by generateReportSchema. The test of
+// the schema fails when it is outdated, UPDATE_SCHEMA=1 go test -run TestReportSchema regenerates it.
+//
+//go:embed report.schema.json
+var reportSchema []byte
+
+// generateReportSchema derives the JSON Schema of the JSON report from the Go types. Fields without omitempty are
+// required, unknown fields are allowed so that reports of later versions with added fields still validate.
+func generateReportSchema() ([]byte, error) {
+	defs := make(map[string]any)
+	root := schemaOf(reflect.TypeOf(JSONOutput{}), defs)
+	schema := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         fmt.Sprintf("urn:jfind:report:v%d", reportSchemaVersion),
+		"title":       fmt.Sprintf("jfind report, version %d", reportSchemaVersion),
+		"description": "Report of the Java runtimes found by a jfind scan",
+		"$ref":        root["$ref"],
+		"$defs":       defs,
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaOf returns the schema of a Go type, named structs are added to defs and referenced
+func schemaOf(t reflect.Type, defs map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem(), defs)
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), defs)}
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			// the entry is reserved before the fields are visited, for types referencing themselves
+			defs[t.Name()] = nil
+			properties := make(map[string]any)
+			required := []string{}
+			addFields(t, properties, &required, defs)
+			def := map[string]any{"type": "object", "properties": properties}
+			if len(required) > 0 {
+				def["required"] = required
+			}
+			defs[t.Name()] = def
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	// interface values can be anything
+	return map[string]any{}
+}
+
+// addFields adds the JSON fields of a struct to the properties of its schema
+func addFields(t reflect.Type, properties map[string]any, required *[]string, defs map[string]any) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addFields(field.Type, properties, required, defs)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := schemaOf(field.Type, defs)
+		kind := field.Type.Kind()
+		if kind == reflect.Pointer || kind == reflect.Slice || kind == reflect.Map {
+			// nil values are written as null
+			schema = map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+		}
+		properties[name] = schema
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaConfig holds the options of the schema command
+type schemaConfig struct {
+	help bool
+}
+
+// runSchema prints the JSON Schema of the JSON report
+func runSchema(args []string) int {
+	var config schemaConfig
+
+	flags := flag.NewFlagSet("schema", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s schema\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print the JSON Schema (version %d) of the JSON report.\n", reportSchemaVersion)
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+	_ = flags.Parse(args)
+
+	if config.help || flags.NArg() != 0 {
+		flags.Usage()
+		return 1
+	}
+	if _, err := os.Stdout.Write(reportSchema); err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	return 0
+}
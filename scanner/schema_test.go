@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestReportSchema(t *testing.T) {
+	generated, err := generateReportSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv("UPDATE_SCHEMA") != "" {
+		if err := os.WriteFile("report.schema.json", generated, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if !bytes.Equal(generated, reportSchema) {
+		t.Fatalf("report.schema.json is outdated, regenerate it with UPDATE_SCHEMA=1 go test -run TestReportSchema")
+	}
+
+	var schema struct {
+		ID   string                    `json:"$id"`
+		Ref  string                    `json:"$ref"`
+		Defs map[string]map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal(reportSchema, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.ID != "urn:jfind:report:v1" || schema.Ref != "#/$defs/JSONOutput" {
+		t.Errorf("unexpected schema id %s or root %s", schema.ID, schema.Ref)
+	}
+	runtime := schema.Defs["JavaRuntimeJSON"]
+	if required, _ := runtime["required"].([]any); len(required) != 1 || required[0] != "java_executable" {
+		t.Errorf("expected java_executable to be the only required field of a runtime, got %v", runtime["required"])
+	}
+	properties, _ := runtime["properties"].(map[string]any)
+	if _, ok := properties["require_license"]; !ok {
+		t.Errorf("expected require_license in the runtime properties")
+	}
+}
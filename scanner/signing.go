@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// signatureHeader is the first line of a jfind signature file, followed by the key id and the signature
+const signatureHeader = "jfind-signature-v1 ed25519"
+
+type verifyConfig struct {
+	keyFile       string
+	signatureFile string
+	help          bool
+}
+
+// loadSigningKey reads an Ed25519 private key in PKCS#8 PEM format
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key %s: %v", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an Ed25519 key", path)
+	}
+	return privateKey, nil
+}
+
+// loadVerifyKey reads an Ed25519 public key in PKIX PEM format, a private key is accepted as well
+func loadVerifyKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "PRIVATE KEY" {
+		privateKey, err := loadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return privateKey.Public().(ed25519.PublicKey), nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key %s: %v", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return publicKey, nil
+}
+
+func readPEMFile(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block, nil
+}
+
+// keyID returns a short identifier of a public key, to match signatures with keys
+func keyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// signReport creates the content of a detached signature file for a report.
+// Trailing whitespace is not signed, so the signature holds whether or not a final newline was written.
+func signReport(report []byte, privateKey ed25519.PrivateKey) ([]byte, error) {
+	signature, err := privateKey.Sign(nil, bytes.TrimRight(report, " \t\r\n"), crypto.Hash(0))
+	if err != nil {
+		return nil, err
+	}
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return []byte(fmt.Sprintf("%s\n%s\n%s\n", signatureHeader, keyID(publicKey), base64.StdEncoding.EncodeToString(signature))), nil
+}
+
+// verifyReport checks a detached signature file against a report
+func verifyReport(report, signatureFile []byte, publicKey ed25519.PublicKey) error {
+	lines := strings.Split(strings.TrimSpace(string(signatureFile)), "\n")
+	if len(lines) != 3 || strings.TrimSpace(lines[0]) != signatureHeader {
+		return errors.New("unsupported signature format")
+	}
+	if id := strings.TrimSpace(lines[1]); id != keyID(publicKey) {
+		return fmt.Errorf("signature was created with key %s, verifying with key %s", id, keyID(publicKey))
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[2]))
+	if err != nil {
+		return fmt.Errorf("decoding signature: %v", err)
+	}
+	if !ed25519.Verify(publicKey, bytes.TrimRight(report, " \t\r\n"), signature) {
+		return errors.New("signature does not match the report")
+	}
+	return nil
+}
+
+// writeSignature signs a report and writes the detached signature file
+func writeSignature(report []byte, keyFile, signatureFile string) error {
+	privateKey, err := loadSigningKey(keyFile)
+	if err != nil {
+		return err
+	}
+	signature, err := signReport(report, privateKey)
+	if err != nil {
+		return fmt.Errorf("signing report: %v", err)
+	}
+	return os.WriteFile(signatureFile, signature, 0o644)
+}
+
+// runVerify implements the 'verify' subcommand
+func runVerify(args []string) int {
+	var config verifyConfig
+
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s verify -key <public_key.pem> [-signature <file>] <report.json>\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Verify the detached signature of a scan report.")
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&config.keyFile, "key", "", "Ed25519 public key in PEM format (required)")
+	flags.StringVar(&config.signatureFile, "signature", "", "Signature file (default: <report>.sig)")
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+	_ = flags.Parse(args)
+
+	if config.help || config.keyFile == "" || flags.NArg() != 1 {
+		flags.Usage()
+		return 1
+	}
+
+	reportFile := flags.Arg(0)
+	if config.signatureFile == "" {
+		config.signatureFile = reportFile + ".sig"
+	}
+
+	publicKey, err := loadVerifyKey(config.keyFile)
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	report, err := os.ReadFile(reportFile)
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	signature, err := os.ReadFile(config.signatureFile)
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+
+	if err := verifyReport(report, signature, publicKey); err != nil {
+		slog.Error("signature verification FAILED", "error", err)
+		return 1
+	}
+	printf("Signature OK: %s (key %s)\n", reportFile, keyID(publicKey))
+	return 0
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+)
+
+func TestSignAndVerifyReport(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := []byte(`{"meta":{"computer_name":"host"},"runtimes":[]}`)
+	signature, err := signReport(report, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifyReport(append(report, '\n'), signature, publicKey); err != nil {
+		t.Errorf("Expected signature to verify, got %v", err)
+	}
+
+	tampered := []byte(`{"meta":{"computer_name":"other"},"runtimes":[]}`)
+	if err := verifyReport(tampered, signature, publicKey); err == nil {
+		t.Error("Expected tampered report to fail verification")
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	if err := verifyReport(report, signature, otherKey); err == nil {
+		t.Error("Expected verification with another key to fail")
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// smbCredentials are read from a file in the format of mount.cifs credential files
+type smbCredentials struct {
+	username string
+	password string
+	domain   string
+}
+
+// smbMount is a share mounted read-only to a temporary directory for the duration of a scan
+type smbMount struct {
+	share      string // //server/share
+	subPath    string // path below the share to scan
+	mountPoint string
+	unmounted  sync.Once
+}
+
+// statWithTimeout stats a path, returning an error instead of hanging when a network share does not respond.
+// The stat call itself cannot be interrupted, it is left behind in the background.
+func statWithTimeout(path string, timeout time.Duration) (os.FileInfo, error) {
+	type statResult struct {
+		info os.FileInfo
+		err  error
+	}
+	done := make(chan statResult, 1)
+	go func() {
+		info, err := os.Stat(path)
+		done <- statResult{info, err}
+	}()
+
+	if timeout <= 0 {
+		r := <-done
+		return r.info, r.err
+	}
+	select {
+	case r := <-done:
+		return r.info, r.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("no response within %s", timeout)
+	}
+}
+
+// splitSMBShare splits //server/share/sub/path (or \\server\share\sub\path) into the share and the sub path
+func splitSMBShare(share string) (string, string, error) {
+	normalized := strings.ReplaceAll(share, `\`, "/")
+	normalized = strings.TrimPrefix(normalized, "smb:")
+	if !strings.HasPrefix(normalized, "//") {
+		return "", "", fmt.Errorf("invalid share %s, expected //server/share", share)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(normalized, "//"), "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid share %s, expected //server/share", share)
+	}
+	subPath := ""
+	if len(parts) == 3 {
+		subPath = strings.Trim(parts[2], "/")
+	}
+	return "//" + parts[0] + "/" + parts[1], subPath, nil
+}
+
+// parseSMBCredentials parses username=, password= and domain= lines
+func parseSMBCredentials(content string) (smbCredentials, error) {
+	var creds smbCredentials
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "username", "user":
+			creds.username = value
+		case "password", "pass":
+			creds.password = value
+		case "domain", "workgroup":
+			creds.domain = value
+		}
+	}
+	if creds.username == "" {
+		return creds, errors.New("credentials file has no username")
+	}
+	return creds, nil
+}
+
+// mountSMB mounts a share read-only, with mount.cifs on Linux and mount_smbfs on macOS
+func mountSMB(share, credentialsFile string, timeout time.Duration) (*smbMount, error) {
+	shareRoot, subPath, err := splitSMBShare(share)
+	if err != nil {
+		return nil, err
+	}
+
+	mountPoint, err := os.MkdirTemp("", "jfind-smb-")
+	if err != nil {
+		return nil, fmt.Errorf("creating mount point: %v", err)
+	}
+	m := &smbMount{share: shareRoot, subPath: subPath, mountPoint: mountPoint}
+
+	args, err := m.mountCommand(credentialsFile)
+	if err != nil {
+		_ = os.Remove(mountPoint)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	slog.Info("Mounting share read-only", "share", shareRoot, "mount_point", mountPoint)
+	if out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput(); err != nil {
+		_ = os.Remove(mountPoint)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("mounting %s: no response within %s", shareRoot, timeout)
+		}
+		return nil, fmt.Errorf("mounting %s: %v: %s", shareRoot, err, strings.TrimSpace(string(out)))
+	}
+	return m, nil
+}
+
+// mountCommand returns the platform mount command line
+func (m *smbMount) mountCommand(credentialsFile string) ([]string, error) {
+	switch runtime.GOOS {
+	case "linux":
+		options := "ro,guest"
+		if credentialsFile != "" {
+			options = "ro,credentials=" + credentialsFile
+		}
+		return []string{"mount", "-t", "cifs", m.share, m.mountPoint, "-o", options}, nil
+	case "darwin":
+		// mount_smbfs would take the password as part of the share URL, visible in the process list. Only the user
+		// is passed, -N disables the password prompt and mount_smbfs reads the password from the keychain or
+		// ~/Library/Preferences/nsmb.conf.
+		user := "guest"
+		if credentialsFile != "" {
+			data, err := os.ReadFile(credentialsFile)
+			if err != nil {
+				return nil, fmt.Errorf("reading SMB credentials: %v", err)
+			}
+			creds, err := parseSMBCredentials(string(data))
+			if err != nil {
+				return nil, err
+			}
+			if creds.password != "" {
+				slog.Warn("the password of the SMB credentials file is not used on macOS, mount_smbfs reads it from the keychain")
+			}
+			user = creds.username
+			if creds.domain != "" {
+				user = creds.domain + ";" + user
+			}
+		}
+		return []string{"mount_smbfs", "-N", "-o", "rdonly", "//" + url.User(user).String() + "@" + strings.TrimPrefix(m.share, "//"), m.mountPoint}, nil
+	}
+	return nil, fmt.Errorf("mounting SMB shares is not supported on %s, scan UNC paths with -path instead", runtime.GOOS)
+}
+
+// scanPath returns the directory to scan below the mount point
+func (m *smbMount) scanPath() string {
+	return filepath.Join(m.mountPoint, filepath.FromSlash(m.subPath))
+}
+
+// redactionRule maps paths below the mount point back to the share
+func (m *smbMount) redactionRule() redactionRule {
+	return redactionRule{
+		pattern:     regexp.MustCompile("^" + regexp.QuoteMeta(m.mountPoint)),
+		replacement: strings.ReplaceAll(m.share, "$", "$$"),
+	}
+}
+
+// unmount unmounts the share and removes the mount point, once when called both on return and on a signal
+func (m *smbMount) unmount() {
+	m.unmounted.Do(func() {
+		if err := exec.Command("umount", m.mountPoint).Run(); err != nil {
+			slog.Warn("unmounting the share failed", "mount_point", m.mountPoint, "error", err)
+			return
+		}
+		_ = os.Remove(m.mountPoint)
+	})
+}
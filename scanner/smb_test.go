@@ -0,0 +1,60 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSplitSMBShare(t *testing.T) {
+	tests := []struct {
+		input, share, subPath string
+	}{
+		{"//fs01/apps", "//fs01/apps", ""},
+		{"//fs01/apps/java/", "//fs01/apps", "java"},
+		{`\\fs01\apps\tools\jdk`, "//fs01/apps", "tools/jdk"},
+		{"smb://fs01/apps", "//fs01/apps", ""},
+	}
+	for _, tt := range tests {
+		share, subPath, err := splitSMBShare(tt.input)
+		if err != nil || share != tt.share || subPath != tt.subPath {
+			t.Errorf("splitSMBShare(%s) = %s, %s, %v", tt.input, share, subPath, err)
+		}
+	}
+	for _, invalid := range []string{"fs01/apps", "//fs01", "///apps"} {
+		if _, _, err := splitSMBShare(invalid); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+}
+
+func TestParseSMBCredentials(t *testing.T) {
+	creds, err := parseSMBCredentials("username=scanner\npassword=se=cret\ndomain=CORP\n")
+	if err != nil || creds.username != "scanner" || creds.password != "se=cret" || creds.domain != "CORP" {
+		t.Errorf("unexpected credentials %+v, %v", creds, err)
+	}
+	if _, err := parseSMBCredentials("password=x"); err == nil {
+		t.Error("expected error without username")
+	}
+}
+
+func TestSMBMountRedaction(t *testing.T) {
+	m := &smbMount{share: "//fs01/apps", subPath: "java", mountPoint: "/tmp/jfind-smb-1"}
+	if got := m.scanPath(); got != filepath.Join("/tmp/jfind-smb-1", "java") {
+		t.Errorf("unexpected scan path %s", got)
+	}
+	r := redactor{m.redactionRule()}
+	if got := r.path("/tmp/jfind-smb-1/java/jdk-17/bin/java"); got != "//fs01/apps/java/jdk-17/bin/java" {
+		t.Errorf("unexpected reported path %s", got)
+	}
+}
+
+func TestStatWithTimeout(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := statWithTimeout(dir, time.Second); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := statWithTimeout(filepath.Join(dir, "missing"), time.Second); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// SplitInfo identifies a part of a report split with -split. All parts of a scan share the scan ID.
+type SplitInfo struct {
+	ScanID string `json:"scan_id"`
+	Part   int    `json:"part"`
+	Parts  int    `json:"parts"`
+	// TotalCount is the number of runtimes of all parts, count_result counts the runtimes of the part
+	TotalCount int `json:"total_count_result"`
+}
+
+// newScanID returns a random version 4 UUID
+func newScanID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return formatUUID(b, 4)
+}
+
+// reproducibleScanID returns a version 8 UUID derived from the content of a reproducible report, the same scan
+// results have the same scan ID while the parts of different results are never mixed up
+func reproducibleScanID(output *JSONOutput) string {
+	data, _ := json.Marshal(output)
+	sum := sha256.Sum256(data)
+	return formatUUID([16]byte(sum[:16]), 8)
+}
+
+// formatUUID sets the version and variant bits of a UUID and formats it
+func formatUUID(b [16]byte, version byte) string {
+	b[6] = b[6]&0x0f | version<<4
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// splitOutput splits a report into parts of at most size runtimes, sharing scanID. Every part has the meta section
+// with the counts of its runtimes, the other sections are only included in the first part.
+func splitOutput(output *JSONOutput, size int, scanID string) []*JSONOutput {
+	total := len(output.Runtimes)
+	parts := max(1, (total+size-1)/size)
+
+	split := make([]*JSONOutput, 0, parts)
+	for i := 0; i < parts; i++ {
+		part := &JSONOutput{Meta: output.Meta}
+		if i == 0 {
+			*part = *output
+		}
+		part.Runtimes = output.Runtimes[i*size : min(total, (i+1)*size)]
+
+		part.Meta.CountResult = len(part.Runtimes)
+		part.Meta.CountRequireLicense = 0
+		for _, runtime := range part.Runtimes {
+			if runtime.RequireLicense != nil && *runtime.RequireLicense {
+				part.Meta.CountRequireLicense++
+			}
+		}
+		part.Meta.Split = &SplitInfo{ScanID: scanID, Part: i + 1, Parts: parts, TotalCount: total}
+		split = append(split, part)
+	}
+	return split
+}
+
+// partPath returns the file name of a part of a split report, scan.json becomes scan.1.json and scan.json.gz
+// becomes scan.1.json.gz
+func partPath(path string, part int) string {
+	compression := ""
+	if isGzipOutput(path) {
+		compression = filepath.Ext(path)
+		path = strings.TrimSuffix(path, compression)
+	}
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.%d%s%s", strings.TrimSuffix(path, ext), part, ext, compression)
+}
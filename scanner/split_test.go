@@ -0,0 +1,64 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSplitOutput(t *testing.T) {
+	requireLicense := true
+	output := &JSONOutput{
+		Meta:   MetaInfo{ComputerName: "cache01", CountResult: 5, CountRequireLicense: 2},
+		Errors: []PathError{{Path: "/srv/secret", Class: "permission", Error: "permission denied"}},
+	}
+	for i := 0; i < 5; i++ {
+		runtime := JavaRuntimeJSON{JavaExecutable: "/srv/cache/jdk/bin/java"}
+		if i%2 == 1 {
+			runtime.RequireLicense = &requireLicense
+		}
+		output.Runtimes = append(output.Runtimes, runtime)
+	}
+
+	parts := splitOutput(output, 2, newScanID())
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	wantCounts := [][2]int{{2, 1}, {2, 1}, {1, 0}}
+	for i, part := range parts {
+		split := part.Meta.Split
+		if split == nil || split.Part != i+1 || split.Parts != 3 || split.TotalCount != 5 {
+			t.Fatalf("part %d: unexpected split info %+v", i+1, split)
+		}
+		if split.ScanID != parts[0].Meta.Split.ScanID || !uuid.MatchString(split.ScanID) {
+			t.Errorf("part %d: unexpected scan ID %q", i+1, split.ScanID)
+		}
+		if part.Meta.ComputerName != "cache01" {
+			t.Errorf("part %d: meta not copied", i+1)
+		}
+		if part.Meta.CountResult != wantCounts[i][0] || part.Meta.CountRequireLicense != wantCounts[i][1] {
+			t.Errorf("part %d: counts %d/%d, want %v", i+1, part.Meta.CountResult, part.Meta.CountRequireLicense, wantCounts[i])
+		}
+		if len(part.Runtimes) != wantCounts[i][0] {
+			t.Errorf("part %d: %d runtimes", i+1, len(part.Runtimes))
+		}
+		if (len(part.Errors) > 0) != (i == 0) {
+			t.Errorf("part %d: errors only expected in the first part", i+1)
+		}
+	}
+	if output.Meta.Split != nil || output.Meta.CountResult != 5 {
+		t.Error("splitting modified the report")
+	}
+
+	if parts := splitOutput(&JSONOutput{}, 2, newScanID()); len(parts) != 1 || parts[0].Meta.Split.Parts != 1 {
+		t.Errorf("expected a single part without runtimes, got %d", len(parts))
+	}
+}
+
+func TestPartPath(t *testing.T) {
+	for path, want := range map[string]string{"scan.json": "scan.1.json", "out/scan": "out/scan.1", "scan.sig": "scan.1.sig", "scan.json.gz": "scan.1.json.gz"} {
+		if got := partPath(path, 1); got != want {
+			t.Errorf("partPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+//go:build cgo
+
+package main
+
+// sqliteSupported reports whether SQLite output works in this build, the driver requires cgo
+const sqliteSupported = true
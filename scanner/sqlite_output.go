@@ -0,0 +1,166 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	// the SQLite driver requires cgo, builds without cgo reject SQLite output (see sqliteSupported)
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteExtensions are the file extensions selecting SQLite output with -output
+var sqliteExtensions = []string{".db", ".sqlite", ".sqlite3"}
+
+// sqliteSchema is the schema of SQLite output. Every scan written to a database adds a row to scans, its runtimes
+// and errors reference it by scan_id. The runtimes table has a column for the common fields and the complete
+// runtime object as JSON in data, e.g. json_extract(data, '$.cacerts.expired_count').
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS scans (
+	id                    INTEGER PRIMARY KEY,
+	scan_ts               TEXT NOT NULL,
+	computer_name         TEXT NOT NULL,
+	user_name             TEXT,
+	scan_path             TEXT,
+	scan_duration         TEXT,
+	scan_profile          TEXT,
+	platform_info         TEXT,
+	privileged            INTEGER NOT NULL,
+	scanned_dirs          INTEGER NOT NULL,
+	count_result          INTEGER NOT NULL,
+	count_require_license INTEGER NOT NULL,
+	has_oracle_jdk        INTEGER NOT NULL,
+	meta                  TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS runtimes (
+	id                  INTEGER PRIMARY KEY,
+	scan_id             INTEGER NOT NULL REFERENCES scans(id),
+	java_executable     TEXT NOT NULL,
+	java_home           TEXT,
+	edition             TEXT,
+	java_version        TEXT,
+	java_version_major  INTEGER,
+	java_version_update INTEGER,
+	java_vendor         TEXT,
+	distribution        TEXT,
+	java_runtime        TEXT,
+	is_oracle           INTEGER NOT NULL,
+	require_license     INTEGER,
+	license_reason      TEXT,
+	exec_failed         INTEGER NOT NULL,
+	version_source      TEXT,
+	default_for_user    INTEGER NOT NULL,
+	duplicate_of        TEXT,
+	installed_program   TEXT,
+	profile_user        TEXT,
+	data                TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS errors (
+	id      INTEGER PRIMARY KEY,
+	scan_id INTEGER NOT NULL REFERENCES scans(id),
+	path    TEXT NOT NULL,
+	class   TEXT NOT NULL,
+	error   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runtimes_scan ON runtimes(scan_id);
+CREATE INDEX IF NOT EXISTS errors_scan ON errors(scan_id);
+`
+
+// isSQLiteOutput reports whether an output file is written as SQLite database
+func isSQLiteOutput(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, candidate := range sqliteExtensions {
+		if ext == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// writeSQLite adds the scan to the SQLite database at path, creating the database and its tables if needed.
+// It returns the ID of the scan row.
+func writeSQLite(path string, output *JSONOutput) (int64, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return 0, fmt.Errorf("opening database %s: %v", path, err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("opening database %s: %v", path, err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return 0, fmt.Errorf("creating tables: %v", err)
+	}
+
+	meta, err := json.Marshal(output.Meta)
+	if err != nil {
+		return 0, err
+	}
+	m := output.Meta
+	result, err := tx.Exec(`INSERT INTO scans (scan_ts, computer_name, user_name, scan_path, scan_duration, scan_profile,
+		platform_info, privileged, scanned_dirs, count_result, count_require_license, has_oracle_jdk, meta)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.ScanTimestamp, m.ComputerName, m.UserName, m.ScanPath, m.ScanDuration, nullString(m.ScanProfile),
+		m.PlatformInfo, m.Privileged, m.ScannedDirs, m.CountResult, m.CountRequireLicense, m.HasOracleJDK, string(meta))
+	if err != nil {
+		return 0, fmt.Errorf("writing scan: %v", err)
+	}
+	scanID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	insertRuntime, err := tx.Prepare(`INSERT INTO runtimes (scan_id, java_executable, java_home, edition, java_version,
+		java_version_major, java_version_update, java_vendor, distribution, java_runtime, is_oracle, require_license,
+		license_reason, exec_failed, version_source, default_for_user, duplicate_of, installed_program, profile_user, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer insertRuntime.Close()
+	for _, r := range output.Runtimes {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return 0, err
+		}
+		var requireLicense sql.NullBool
+		if r.RequireLicense != nil {
+			requireLicense = sql.NullBool{Bool: *r.RequireLicense, Valid: true}
+		}
+		_, err = insertRuntime.Exec(scanID, r.JavaExecutable, nullString(r.JavaHome), nullString(r.Edition),
+			nullString(r.JavaVersion), nullInt(r.VersionMajor), nullInt(r.VersionUpdate), nullString(r.JavaVendor),
+			nullString(r.Distribution), nullString(r.JavaRuntime), r.IsOracle, requireLicense, nullString(r.LicenseReason),
+			r.ExecFailed, nullString(r.VersionSource), r.DefaultForUser, nullString(r.DuplicateOf),
+			nullString(r.InstalledProgram), nullString(r.ProfileUser), string(data))
+		if err != nil {
+			return 0, fmt.Errorf("writing runtime %s: %v", r.JavaExecutable, err)
+		}
+	}
+
+	for _, e := range output.Errors {
+		if _, err := tx.Exec(`INSERT INTO errors (scan_id, path, class, error) VALUES (?, ?, ?, ?)`,
+			scanID, e.Path, e.Class, e.Error); err != nil {
+			return 0, fmt.Errorf("writing error record: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("writing database %s: %v", path, err)
+	}
+	return scanID, nil
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullInt stores zero as NULL, for values only known for evaluated runtimes
+func nullInt(i int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(i), Valid: i != 0}
+}
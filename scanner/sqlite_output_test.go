@@ -0,0 +1,79 @@
+//go:build cgo
+
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsSQLiteOutput(t *testing.T) {
+	for path, want := range map[string]bool{"scan.db": true, "out/Scan.SQLite": true, "scan.sqlite3": true, "scan.json": false, "": false} {
+		if got := isSQLiteOutput(path); got != want {
+			t.Errorf("isSQLiteOutput(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestWriteSQLite(t *testing.T) {
+	requireLicense := true
+	output := &JSONOutput{
+		Meta: MetaInfo{ScanTimestamp: "2026-10-16T08:00:00Z", ComputerName: "build01", ScanPath: "/opt", CountResult: 2, CountRequireLicense: 1},
+		Runtimes: []JavaRuntimeJSON{
+			{JavaExecutable: "/opt/jdk1.8.0_202/bin/java", JavaVersion: "1.8.0_202", VersionMajor: 8, VersionUpdate: 202,
+				JavaVendor: "Oracle Corporation", IsOracle: true, RequireLicense: &requireLicense, BinaryArch: []string{"x86_64"}},
+			{JavaExecutable: "/opt/broken/bin/java", ExecFailed: true},
+		},
+		Errors: []PathError{{Path: "/opt/secret", Class: "permission", Error: "permission denied"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "scan.db")
+	for want := int64(1); want <= 2; want++ {
+		scanID, err := writeSQLite(path, output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if scanID != want {
+			t.Errorf("scan ID = %d, want %d", scanID, want)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var runtimes, errors int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM runtimes WHERE scan_id = 2`).Scan(&runtimes); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM errors e JOIN scans s ON s.id = e.scan_id WHERE s.computer_name = 'build01'`).Scan(&errors); err != nil {
+		t.Fatal(err)
+	}
+	if runtimes != 2 || errors != 2 {
+		t.Errorf("expected 2 runtimes of the second scan and 2 errors, got %d and %d", runtimes, errors)
+	}
+
+	var version string
+	var major int
+	var license sql.NullBool
+	var arch string
+	err = db.QueryRow(`SELECT java_version, java_version_major, require_license, json_extract(data, '$.binary_arch[0]')
+		FROM runtimes WHERE scan_id = 1 AND is_oracle`).Scan(&version, &major, &license, &arch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "1.8.0_202" || major != 8 || !license.Valid || !license.Bool || arch != "x86_64" {
+		t.Errorf("unexpected runtime row %s %d %v %s", version, major, license, arch)
+	}
+
+	var failedVersion sql.NullString
+	if err := db.QueryRow(`SELECT java_version FROM runtimes WHERE scan_id = 1 AND exec_failed`).Scan(&failedVersion); err != nil {
+		t.Fatal(err)
+	}
+	if failedVersion.Valid {
+		t.Errorf("expected NULL version of a failed runtime, got %q", failedVersion.String)
+	}
+}
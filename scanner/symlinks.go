@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// symlinkTarget returns the executable a java symlink points to, e.g. /opt/jdk-17/bin/java for /usr/local/bin/java.
+// The target may be outside the scanned root. Dangling links have no target and are evaluated as they are.
+func symlinkTarget(path string) (string, bool) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return "", false
+	}
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	return target, true
+}
+
+// mergeLinkedResults reports an executable found directly and through symlinks, in the same or several roots, once
+// with the paths of all its links
+func mergeLinkedResults(results []*JavaResult) []*JavaResult {
+	merged := make([]*JavaResult, 0, len(results))
+	byPath := make(map[string]*JavaResult)
+	for _, result := range results {
+		first, ok := byPath[result.Path]
+		if !ok {
+			byPath[result.Path] = result
+			merged = append(merged, result)
+			continue
+		}
+		for _, link := range result.Symlinks {
+			if !containsString(first.Symlinks, link) {
+				first.Symlinks = append(first.Symlinks, link)
+			}
+		}
+	}
+	for _, result := range merged {
+		sort.Strings(result.Symlinks)
+	}
+	return merged
+}
+
+// mergeHardLinks reports an executable reached through several real paths, hard links or the paths of bind mounts, once.
+// Results are compared by file identity (device and inode), the one with the lowest path is kept with the other
+// paths as its hard links.
+func mergeHardLinks(results []*JavaResult) []*JavaResult {
+	merged := make([]*JavaResult, 0, len(results))
+	var infos []os.FileInfo
+	for _, result := range results {
+		info, err := os.Stat(result.Path)
+		i := -1
+		if err == nil {
+			i = slices.IndexFunc(infos, func(other os.FileInfo) bool { return other != nil && os.SameFile(other, info) })
+		}
+		if i < 0 {
+			merged = append(merged, result)
+			infos = append(infos, info)
+			continue
+		}
+
+		first := merged[i]
+		if result.Path < first.Path {
+			*first, *result = *result, *first
+		}
+		for _, path := range append([]string{result.Path}, result.HardLinks...) {
+			if !containsString(first.HardLinks, path) {
+				first.HardLinks = append(first.HardLinks, path)
+			}
+		}
+		for _, link := range result.Symlinks {
+			if !containsString(first.Symlinks, link) {
+				first.Symlinks = append(first.Symlinks, link)
+			}
+		}
+		sort.Strings(first.HardLinks)
+		sort.Strings(first.Symlinks)
+	}
+	return merged
+}
+
+// aliases returns all other paths of the executable of a result, its symlinks and hard links
+func (r *JavaResult) aliases() []string {
+	if len(r.Symlinks) == 0 && len(r.HardLinks) == 0 {
+		return nil
+	}
+	aliases := append(append([]string{}, r.Symlinks...), r.HardLinks...)
+	sort.Strings(aliases)
+	return aliases
+}
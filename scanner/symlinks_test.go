@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestSymlinkTargets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX symlinks")
+	}
+
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	usr, opt := filepath.Join(base, "usr"), filepath.Join(base, "opt")
+	target := writeFakeJava(t, filepath.Join(opt, "jdk-17", "bin"))
+	if err := os.MkdirAll(filepath.Join(usr, "local", "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(usr, "local", "bin", "java")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, threads := range []int{1, 4} {
+		// the target is outside the scanned root
+		finder := NewJavaFinder(usr, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		linked, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(linked) != 1 || linked[0].Path != target || !reflect.DeepEqual(linked[0].Symlinks, []string{link}) ||
+			linked[0].JavaHome != filepath.Join(opt, "jdk-17") {
+			t.Fatalf("threads %d: expected the link target, got %+v", threads, linked)
+		}
+
+		// the target found again in another root is reported once
+		finder = NewJavaFinder(opt, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		direct, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		merged := mergeRootResults([]rootScan{{results: direct}, {results: linked}})
+		if len(merged) != 1 || merged[0].Path != target || !reflect.DeepEqual(merged[0].Symlinks, []string{link}) {
+			t.Errorf("threads %d: expected a single runtime with the link, got %+v", threads, merged)
+		}
+	}
+}
+
+func TestMergeHardLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX symlinks")
+	}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a JDK installed twice through hard links, e.g. by a package manager store, and a link to the second path
+	first := writeFakeJava(t, filepath.Join(root, "a-jdk", "bin"))
+	if err := os.MkdirAll(filepath.Join(root, "b-copy", "bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	second := filepath.Join(root, "b-copy", "bin", "java")
+	if err := os.Link(first, second); err != nil {
+		t.Skipf("hard links not supported: %v", err)
+	}
+	link := filepath.Join(root, "c", "java")
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(second, link); err != nil {
+		t.Fatal(err)
+	}
+	other := writeFakeJava(t, filepath.Join(root, "d-jdk", "bin"))
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(root, -1, false)
+		finder.walkers, finder.evaluators = threads, threads
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		merged := mergeRootResults([]rootScan{{results: results}})
+		if len(merged) != 2 || merged[0].Path != first || merged[1].Path != other {
+			t.Fatalf("threads %d: expected two runtimes, got %+v", threads, merged)
+		}
+		if !reflect.DeepEqual(merged[0].HardLinks, []string{second}) || !reflect.DeepEqual(merged[0].Symlinks, []string{link}) {
+			t.Errorf("threads %d: unexpected links %q and %q", threads, merged[0].HardLinks, merged[0].Symlinks)
+		}
+		if aliases := createRuntimeJSON(merged[0], false).Aliases; !reflect.DeepEqual(aliases, []string{second, link}) {
+			t.Errorf("threads %d: unexpected aliases %q", threads, aliases)
+		}
+		if merged[1].aliases() != nil {
+			t.Errorf("threads %d: expected no aliases of a single path, got %q", threads, merged[1].aliases())
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// renderTable renders the runtimes as a table with aligned columns, one runtime per line
+func renderTable(output *JSONOutput, options renderOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	columns := []string{"EXECUTABLE", "VENDOR", "VERSION", "LICENSE"}
+	if options.wide {
+		columns = []string{"EXECUTABLE", "VENDOR", "VERSION", "DISTRIBUTION", "EDITION", "RELEASED", "ARCH", "LICENSE",
+			"FREE UNTIL", "REASON"}
+	}
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+
+	requireLicense := 0
+	for _, runtime := range output.Runtimes {
+		license := "-"
+		switch {
+		case runtime.RequireLicense != nil && *runtime.RequireLicense:
+			license = "required"
+			requireLicense++
+		case runtime.RequireLicense != nil:
+			license = "no"
+		case runtime.ExecFailed:
+			license = "exec failed"
+		}
+		row := []string{runtime.JavaExecutable, orDash(runtime.JavaVendor), orDash(runtime.JavaVersion), license}
+		if options.wide {
+			row = []string{
+				runtime.JavaExecutable, orDash(runtime.JavaVendor), orDash(runtime.JavaVersion),
+				orDash(runtime.Distribution), orDash(runtime.Edition), orDash(runtime.JavaVersionDate),
+				orDash(strings.Join(runtime.BinaryArch, ",")), license, orDash(runtime.LicenseFreeUntil),
+				orDash(runtime.LicenseReason),
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "\n%d runtimes, %d requiring a license\n", len(output.Runtimes), requireLicense)
+	return buf.Bytes(), nil
+}
+
+// orDash returns value, or - for an unknown value
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
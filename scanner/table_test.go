@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTable(t *testing.T) {
+	data, err := renderTable(testOutput(), renderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(data), "\n")
+	if !strings.HasPrefix(lines[0], "EXECUTABLE") || !strings.HasSuffix(lines[0], "LICENSE") {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	// the columns are aligned
+	if column := strings.Index(lines[0], "VENDOR"); strings.Index(lines[1], "Oracle Corporation") != column || strings.Index(lines[2], "-") != column {
+		t.Errorf("expected aligned columns, got\n%s", data)
+	}
+	if !strings.HasSuffix(lines[1], "required") || !strings.HasSuffix(lines[2], "-") {
+		t.Errorf("unexpected license column\n%s", data)
+	}
+	if !strings.Contains(string(data), "2 runtimes, 1 requiring a license") {
+		t.Errorf("expected the summary line\n%s", data)
+	}
+
+	if data, err = renderTable(testOutput(), renderOptions{wide: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "DISTRIBUTION") || !strings.Contains(string(data), "after the NFTC free use window") {
+		t.Errorf("expected the wide columns\n%s", data)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the functions available in output templates
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	// license renders the license requirement of a runtime as true, false or an empty string if unknown
+	"license": func(requireLicense *bool) string {
+		if requireLicense == nil {
+			return ""
+		}
+		return fmt.Sprint(*requireLicense)
+	},
+}
+
+// parseOutputTemplate parses the template of -format template, its fields are the fields of a runtime. Fields are
+// checked when the template is executed, an empty runtime cannot tell an unknown field from an index out of range.
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate renders every runtime with the output template, one per line
+func renderTemplate(output *JSONOutput, options renderOptions) ([]byte, error) {
+	if options.template == nil {
+		return nil, fmt.Errorf("-format template requires --template")
+	}
+	var buf bytes.Buffer
+	for _, runtime := range output.Runtimes {
+		if err := options.template.Execute(&buf, runtime); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
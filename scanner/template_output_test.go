@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tmpl, err := parseOutputTemplate(`{{.JavaExecutable}},{{.JavaVersion}},{{license .RequireLicense}},{{json .Distribution}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := renderTemplate(testOutput(), renderOptions{template: tmpl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "/opt/jdk-17/bin/java,17.0.13,true,\"oracle\"\n/opt/unknown/bin/java,,,\"\"\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	for _, text := range []string{"{{.JavaExecutable", "{{unknown .JavaHome}}"} {
+		if _, err := parseOutputTemplate(text); err == nil || !strings.Contains(err.Error(), "invalid template") {
+			t.Errorf("expected %q to be rejected, got %v", text, err)
+		}
+	}
+
+	// fields are checked when a runtime is rendered, indexes depend on the runtime
+	for text, valid := range map[string]bool{"{{index .BinaryArch 0}}": true, "{{.Colour}}": false} {
+		tmpl, err := parseOutputTemplate(text)
+		if err != nil {
+			t.Fatalf("expected %q to be accepted, got %v", text, err)
+		}
+		output := &JSONOutput{Runtimes: []JavaRuntimeJSON{{BinaryArch: []string{"arm64"}}}}
+		if _, err := renderTemplate(output, renderOptions{template: tmpl}); (err == nil) != valid {
+			t.Errorf("unexpected result of rendering %q: %v", text, err)
+		}
+	}
+}
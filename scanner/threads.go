@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Storage kinds of a scan root, they determine the automatic concurrency
+const (
+	storageNetwork    = "network"
+	storageRotational = "rotational"
+	storageSSD        = "ssd"
+	storageUnknown    = "unknown"
+)
+
+// maxAutoWalkers caps the automatic walker count, more parallel directory reads do not pay off
+const maxAutoWalkers = 16
+
+// concurrency is the number of directory walkers and evaluators of a scan
+type concurrency struct {
+	walkers    int
+	evaluators int
+}
+
+// resolveThreads converts a -threads value (auto or a number) into the scan concurrency for a root
+func resolveThreads(value, root string) (concurrency, error) {
+	if value == "" || value == "auto" {
+		storage := detectStorage(root)
+		c := autoConcurrency(storage, runtime.NumCPU())
+		slog.Info("Using walkers and evaluators", "walkers", c.walkers, "evaluators", c.evaluators, "storage", storage, "cpus", runtime.NumCPU())
+		return c, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return concurrency{}, fmt.Errorf("invalid -threads value '%s', use auto or a positive number", value)
+	}
+	return concurrency{walkers: n, evaluators: n}, nil
+}
+
+// autoConcurrency picks the concurrency for a storage kind. Directory reads on SSDs scale with the CPUs,
+// rotational disks suffer from seeks with many readers, and network file systems profit from parallel
+// requests hiding the latency while executing java from them is slow.
+func autoConcurrency(storage string, cpus int) concurrency {
+	switch storage {
+	case storageRotational:
+		return concurrency{walkers: 2, evaluators: max(1, cpus/2)}
+	case storageNetwork:
+		return concurrency{walkers: 8, evaluators: 2}
+	}
+	return concurrency{walkers: min(cpus, maxAutoWalkers), evaluators: cpus}
+}
+
+// detectStorage determines the storage kind of the file system containing root, only on Linux
+func detectStorage(root string) string {
+	if runtime.GOOS != "linux" {
+		return storageUnknown
+	}
+
+	data, err := os.ReadFile("/proc/mounts")
+	if err != nil {
+		return storageUnknown
+	}
+	mount, ok := mountOf(parseProcMounts(string(data)), root)
+	if !ok {
+		return storageUnknown
+	}
+	if networkFileSystems[mount.FSType] {
+		return storageNetwork
+	}
+	return blockDeviceStorage(mount.Device)
+}
+
+// blockDeviceStorage reads the rotational flag of a block device from sysfs. Partitions have no queue
+// directory of their own, the flag of the parent disk applies.
+func blockDeviceStorage(device string) string {
+	if !strings.HasPrefix(device, "/dev/") {
+		return storageUnknown
+	}
+	// /dev/mapper/* and /dev/disk/by-* are links to the kernel device
+	if resolved, err := filepath.EvalSymlinks(device); err == nil {
+		device = resolved
+	}
+
+	sysPath, err := filepath.EvalSymlinks(filepath.Join("/sys/class/block", filepath.Base(device)))
+	if err != nil {
+		return storageUnknown
+	}
+	for _, dir := range []string{sysPath, filepath.Dir(sysPath)} {
+		data, err := os.ReadFile(filepath.Join(dir, "queue", "rotational"))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(data)) == "1" {
+			return storageRotational
+		}
+		return storageSSD
+	}
+	return storageUnknown
+}
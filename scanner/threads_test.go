@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestResolveThreads(t *testing.T) {
+	c, err := resolveThreads("6", "/")
+	if err != nil || c.walkers != 6 || c.evaluators != 6 {
+		t.Errorf("unexpected concurrency %+v, %v", c, err)
+	}
+	for _, value := range []string{"0", "-2", "many"} {
+		if _, err := resolveThreads(value, "/"); err == nil {
+			t.Errorf("expected error for -threads %s", value)
+		}
+	}
+}
+
+func TestAutoConcurrency(t *testing.T) {
+	tests := []struct {
+		storage string
+		cpus    int
+		want    concurrency
+	}{
+		{storageSSD, 8, concurrency{walkers: 8, evaluators: 8}},
+		{storageUnknown, 64, concurrency{walkers: maxAutoWalkers, evaluators: 64}},
+		{storageRotational, 8, concurrency{walkers: 2, evaluators: 4}},
+		{storageRotational, 1, concurrency{walkers: 2, evaluators: 1}},
+		{storageNetwork, 8, concurrency{walkers: 8, evaluators: 2}},
+	}
+	for _, tt := range tests {
+		if got := autoConcurrency(tt.storage, tt.cpus); got != tt.want {
+			t.Errorf("autoConcurrency(%s, %d) = %+v, want %+v", tt.storage, tt.cpus, got, tt.want)
+		}
+	}
+}
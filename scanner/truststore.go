@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// wellKnownRoots are modern root CAs a current trust store is expected to contain, by subject common name
+var wellKnownRoots = []string{
+	"ISRG Root X1",
+	"DigiCert Global Root G2",
+	"Amazon Root CA 1",
+	"GTS Root R1",
+}
+
+const (
+	jksMagic   = 0xFEEDFEED
+	jceksMagic = 0xCECECECE
+)
+
+var (
+	oidPKCS7Data          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS7EncryptedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidCertBag            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+)
+
+// TrustStoreInfo summarizes the cacerts trust store of a runtime
+type TrustStoreInfo struct {
+	Path             string   `json:"path"`
+	Format           string   `json:"format,omitempty"`
+	CertificateCount int      `json:"certificate_count"`
+	ExpiredCount     int      `json:"expired_count,omitempty"`
+	SoonestExpiry    string   `json:"soonest_expiry,omitempty"`
+	SoonestSubject   string   `json:"soonest_expiry_subject,omitempty"`
+	MissingRoots     []string `json:"missing_roots,omitempty"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// inspectTrustStore locates and summarizes the cacerts file of a Java installation, nil if there is none
+func inspectTrustStore(javaHome string, now time.Time) *TrustStoreInfo {
+	candidates := []string{
+		filepath.Join(javaHome, "lib", "security", "cacerts"),
+		filepath.Join(javaHome, "jre", "lib", "security", "cacerts"),
+	}
+
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(candidate)
+		if err != nil {
+			continue
+		}
+
+		info := &TrustStoreInfo{Path: candidate}
+		format, certs, err := parseTrustStore(data)
+		info.Format = format
+		if err != nil {
+			info.Error = err.Error()
+		}
+		info.summarize(certs, now)
+		return info
+	}
+	return nil
+}
+
+// summarize fills the certificate statistics
+func (t *TrustStoreInfo) summarize(certs []*x509.Certificate, now time.Time) {
+	t.CertificateCount = len(certs)
+
+	present := make(map[string]bool)
+	var soonest *x509.Certificate
+	for _, cert := range certs {
+		present[cert.Subject.CommonName] = true
+		if cert.NotAfter.Before(now) {
+			t.ExpiredCount++
+			continue
+		}
+		if soonest == nil || cert.NotAfter.Before(soonest.NotAfter) {
+			soonest = cert
+		}
+	}
+
+	if soonest != nil {
+		t.SoonestExpiry = soonest.NotAfter.UTC().Format(time.DateOnly)
+		t.SoonestSubject = soonest.Subject.CommonName
+	}
+
+	for _, root := range wellKnownRoots {
+		if !present[root] {
+			t.MissingRoots = append(t.MissingRoots, root)
+		}
+	}
+}
+
+// parseTrustStore returns the format and the trusted certificates of a JKS, JCEKS or PKCS12 keystore
+func parseTrustStore(data []byte) (string, []*x509.Certificate, error) {
+	if len(data) >= 4 {
+		switch binary.BigEndian.Uint32(data) {
+		case jksMagic:
+			certs, err := parseJKS(data)
+			return "jks", certs, err
+		case jceksMagic:
+			certs, err := parseJKS(data)
+			return "jceks", certs, err
+		}
+	}
+	certs, err := parsePKCS12(data)
+	return "pkcs12", certs, err
+}
+
+// parseJKS extracts the certificates of a JKS/JCEKS keystore, no password is needed to read certificates
+func parseJKS(data []byte) ([]*x509.Certificate, error) {
+	r := bytes.NewReader(data)
+	var header struct {
+		Magic, Version, Count uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading keystore header: %v", err)
+	}
+
+	var certs []*x509.Certificate
+	for i := uint32(0); i < header.Count; i++ {
+		var tag uint32
+		if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+			return certs, fmt.Errorf("reading entry %d: %v", i, err)
+		}
+		if _, err := readJKSUTF(r); err != nil { // alias
+			return certs, err
+		}
+		if _, err := r.Seek(8, io.SeekCurrent); err != nil { // timestamp
+			return certs, err
+		}
+
+		switch tag {
+		case 1: // private key with certificate chain
+			if err := skipJKSBlock(r); err != nil {
+				return certs, err
+			}
+			var chainLength uint32
+			if err := binary.Read(r, binary.BigEndian, &chainLength); err != nil {
+				return certs, err
+			}
+			for j := uint32(0); j < chainLength; j++ {
+				if _, err := readJKSCertificate(r, header.Version); err != nil {
+					return certs, err
+				}
+			}
+		case 2: // trusted certificate
+			cert, err := readJKSCertificate(r, header.Version)
+			if err != nil {
+				return certs, err
+			}
+			if cert != nil {
+				certs = append(certs, cert)
+			}
+		default:
+			return certs, fmt.Errorf("unsupported keystore entry type %d", tag)
+		}
+	}
+	return certs, nil
+}
+
+func readJKSUTF(r *bytes.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func readJKSBlock(r *bytes.Reader) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if int64(length) > int64(r.Len()) {
+		return nil, errors.New("truncated keystore")
+	}
+	buf := make([]byte, length)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}
+
+func skipJKSBlock(r *bytes.Reader) error {
+	_, err := readJKSBlock(r)
+	return err
+}
+
+// readJKSCertificate reads a certificate entry, returns nil for non X.509 certificates
+func readJKSCertificate(r *bytes.Reader, version uint32) (*x509.Certificate, error) {
+	certType := "X.509"
+	if version == 2 {
+		var err error
+		if certType, err = readJKSUTF(r); err != nil {
+			return nil, err
+		}
+	}
+	der, err := readJKSBlock(r)
+	if err != nil || certType != "X.509" {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+type pkcs12PFX struct {
+	Version  int
+	AuthSafe pkcs12ContentInfo
+	MacData  asn1.RawValue `asn1:"optional"`
+}
+
+type pkcs12ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type pkcs12SafeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue   `asn1:"tag:0,explicit"`
+	Attributes []asn1.RawValue `asn1:"set,optional"`
+}
+
+type pkcs12CertBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+// parsePKCS12 extracts the certificates stored in unencrypted safe contents of a PKCS12 keystore.
+// Since JDK 18 cacerts is a password-less PKCS12 file with unencrypted certificates.
+func parsePKCS12(data []byte) ([]*x509.Certificate, error) {
+	var pfx pkcs12PFX
+	if _, err := asn1.Unmarshal(data, &pfx); err != nil {
+		return nil, fmt.Errorf("unknown keystore format: %v", err)
+	}
+
+	var authSafeData []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafeData); err != nil {
+		return nil, fmt.Errorf("reading PKCS12 content: %v", err)
+	}
+	var authSafe []pkcs12ContentInfo
+	if _, err := asn1.Unmarshal(authSafeData, &authSafe); err != nil {
+		return nil, fmt.Errorf("reading PKCS12 content: %v", err)
+	}
+
+	var certs []*x509.Certificate
+	encrypted := false
+	for _, ci := range authSafe {
+		if ci.ContentType.Equal(oidPKCS7EncryptedData) {
+			encrypted = true
+			continue
+		}
+		if !ci.ContentType.Equal(oidPKCS7Data) {
+			continue
+		}
+
+		var safeContentsData []byte
+		if _, err := asn1.Unmarshal(ci.Content.Bytes, &safeContentsData); err != nil {
+			return certs, fmt.Errorf("reading PKCS12 safe contents: %v", err)
+		}
+		var bags []pkcs12SafeBag
+		if _, err := asn1.Unmarshal(safeContentsData, &bags); err != nil {
+			return certs, fmt.Errorf("reading PKCS12 safe bags: %v", err)
+		}
+		for _, bag := range bags {
+			if !bag.ID.Equal(oidCertBag) {
+				continue
+			}
+			var cb pkcs12CertBag
+			if _, err := asn1.Unmarshal(bag.Value.Bytes, &cb); err != nil {
+				continue
+			}
+			if cert, err := x509.ParseCertificate(cb.Data); err == nil {
+				certs = append(certs, cert)
+			}
+		}
+	}
+
+	if encrypted && len(certs) == 0 {
+		return nil, errors.New("certificates are encrypted, not inspected")
+	}
+	return certs, nil
+}
+
+// printTrustStore prints the trust store summary in text output mode
+func printTrustStore(t *TrustStoreInfo) {
+	fmt.Printf("Trust store: %s (%s, %d certificates)\n", t.Path, t.Format, t.CertificateCount)
+	if t.Error != "" {
+		fmt.Printf("Warning: Trust store not fully inspected: %s\n", t.Error)
+	}
+	if t.ExpiredCount > 0 {
+		fmt.Printf("Warning: Trust store contains %d expired certificates\n", t.ExpiredCount)
+	}
+	if t.SoonestExpiry != "" {
+		fmt.Printf("Trust store soonest expiry: %s (%s)\n", t.SoonestExpiry, t.SoonestSubject)
+	}
+	if len(t.MissingRoots) > 0 {
+		fmt.Printf("Warning: Trust store lacks modern roots: %s\n", strings.Join(t.MissingRoots, ", "))
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func createTestCertificate(t *testing.T, commonName string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.AddDate(-10, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func writeJKSEntry(buf *bytes.Buffer, alias string, der []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint32(2))
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(alias)))
+	buf.WriteString(alias)
+	_ = binary.Write(buf, binary.BigEndian, int64(0))
+	_ = binary.Write(buf, binary.BigEndian, uint16(len("X.509")))
+	buf.WriteString("X.509")
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(der)))
+	buf.Write(der)
+}
+
+func TestParseTrustStoreJKS(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, []uint32{jksMagic, 2, 3})
+	writeJKSEntry(&buf, "isrgrootx1", createTestCertificate(t, "ISRG Root X1", now.AddDate(10, 0, 0)))
+	writeJKSEntry(&buf, "soon", createTestCertificate(t, "Soon Root", now.AddDate(0, 2, 0)))
+	writeJKSEntry(&buf, "expired", createTestCertificate(t, "Expired Root", now.AddDate(0, -1, 0)))
+	buf.Write(make([]byte, 20)) // digest
+
+	format, certs, err := parseTrustStore(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if format != "jks" {
+		t.Errorf("Expected format jks, got %s", format)
+	}
+
+	info := &TrustStoreInfo{}
+	info.summarize(certs, now)
+
+	if info.CertificateCount != 3 {
+		t.Errorf("Expected 3 certificates, got %d", info.CertificateCount)
+	}
+	if info.ExpiredCount != 1 {
+		t.Errorf("Expected 1 expired certificate, got %d", info.ExpiredCount)
+	}
+	if info.SoonestSubject != "Soon Root" || info.SoonestExpiry != "2025-03-01" {
+		t.Errorf("Unexpected soonest expiry %s (%s)", info.SoonestExpiry, info.SoonestSubject)
+	}
+	for _, root := range info.MissingRoots {
+		if root == "ISRG Root X1" {
+			t.Error("Expected ISRG Root X1 to be present")
+		}
+	}
+}
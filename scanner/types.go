@@ -3,42 +3,143 @@ package main
 // JavaResult represents the result of evaluating a Java executable
 type JavaResult struct {
 	Path       string
+	JavaHome   string
+	Edition    string
 	Properties *JavaProperties
 	StdErr     string
 	ReturnCode int
 	Error      error
 	Evaluated  bool
+	Release    ReleaseInfo
+	TrustStore *TrustStoreInfo
+	Security   *SecurityConfig
+	Workload   *K8sWorkload
+	Arch       *BinaryArch
+	// VersionInfo is the VERSIONINFO resource of java.exe, read without executing it
+	VersionInfo *FileVersionInfo
+	// Wine is set for Windows runtimes in a Wine or Proton prefix
+	Wine *WineRuntime
+	// Symlinks are the links to the executable found by the scan, Path is their resolved target
+	Symlinks []string
+	// HardLinks are the other real paths of the executable, hard links or paths of bind mounts
+	HardLinks []string
+	// DefaultPathEntry is set for the runtime selected by the PATH of the scanning user
+	DefaultPathEntry string
+	// InstallSize and DuplicateOf are set by markDuplicates
+	InstallSize int64
+	DuplicateOf string
+	// InstalledProgram and Unregistered are set by correlateInstalledPrograms
+	InstalledProgram string
+	Program          *InstalledProgram
+	Unregistered     bool
+	// Application is set by attributeApplications for runtimes bundled with a jpackage application
+	Application *JPackageApp
+	// DeploymentRisks are set by attachDeploymentRisks for runtimes with Web Start or the browser plugin
+	DeploymentRisks []string
+	// ProfileUser is the account owning the user profile containing the runtime
+	ProfileUser string
+	// BaselineDeviation is set by compareBaseline for runtimes deviating from the baseline
+	BaselineDeviation string
+	// PolicyViolations are set by evaluatePolicies
+	PolicyViolations []PolicyViolation
+	// ProcessIDs and CommercialFeatures are set by attachProcesses
+	ProcessIDs         []int
+	CommercialFeatures []string
 }
 
 // JavaRuntimeJSON represents a single Java runtime for JSON output
 type JavaRuntimeJSON struct {
-	JavaExecutable string `json:"java_executable"`
-	JavaRuntime    string `json:"java_runtime,omitempty"`
-	JavaVendor     string `json:"java_vendor,omitempty"`
-	IsOracle       bool   `json:"is_oracle,omitempty"`
-	JavaVersion    string `json:"java_version,omitempty"`
-	VersionMajor   int    `json:"java_version_major,omitempty"`
-	VersionUpdate  int    `json:"java_version_update,omitempty"`
-	ExecFailed     bool   `json:"exec_failed,omitempty"`
-	RequireLicense *bool  `json:"require_license,omitempty"`
+	JavaExecutable     string            `json:"java_executable"`
+	Symlinks           []string          `json:"symlinks,omitempty"`
+	Aliases            []string          `json:"aliases,omitempty"`
+	JavaHome           string            `json:"java_home,omitempty"`
+	JavaRuntime        string            `json:"java_runtime,omitempty"`
+	JavaVendor         string            `json:"java_vendor,omitempty"`
+	Distribution       string            `json:"distribution,omitempty"`
+	Edition            string            `json:"edition,omitempty"`
+	JavaVMName         string            `json:"java_vm_name,omitempty"`
+	VMImplementation   string            `json:"vm_implementation,omitempty"`
+	IsOracle           bool              `json:"is_oracle,omitempty"`
+	JavaVersion        string            `json:"java_version,omitempty"`
+	VersionMajor       int               `json:"java_version_major,omitempty"`
+	VersionUpdate      int               `json:"java_version_update,omitempty"`
+	JavaVersionDate    string            `json:"java_version_date,omitempty"`
+	ExecFailed         bool              `json:"exec_failed,omitempty"`
+	ExecError          string            `json:"exec_error,omitempty"`
+	VersionSource      string            `json:"version_source,omitempty"`
+	RequireLicense     *bool             `json:"require_license,omitempty"`
+	LicenseFreeUntil   string            `json:"license_free_until,omitempty"`
+	LicenseReason      string            `json:"license_reason,omitempty"`
+	ModuleCount        int               `json:"module_count,omitempty"`
+	NotableModules     []string          `json:"notable_modules,omitempty"`
+	IsCustomRuntime    bool              `json:"is_custom_runtime,omitempty"`
+	Modules            []string          `json:"modules,omitempty"`
+	TrustStore         *TrustStoreInfo   `json:"cacerts,omitempty"`
+	Security           *SecurityConfig   `json:"security,omitempty"`
+	Kubernetes         *K8sWorkload      `json:"kubernetes,omitempty"`
+	BinaryArch         []string          `json:"binary_arch,omitempty"`
+	IsUniversal        bool              `json:"is_universal_binary,omitempty"`
+	RequiresRosetta    bool              `json:"requires_rosetta,omitempty"`
+	DefaultForUser     bool              `json:"default_for_user,omitempty"`
+	DefaultPathEntry   string            `json:"default_path_entry,omitempty"`
+	InstallSize        int64             `json:"install_size_bytes,omitempty"`
+	DuplicateOf        string            `json:"duplicate_of,omitempty"`
+	InstalledProgram   string            `json:"installed_program,omitempty"`
+	InstallProvenance  *InstalledProgram `json:"install_provenance,omitempty"`
+	VersionInfo        *FileVersionInfo  `json:"version_info,omitempty"`
+	Wine               *WineRuntime      `json:"wine,omitempty"`
+	Application        *JPackageApp      `json:"jpackage_application,omitempty"`
+	DeploymentRisks    []string          `json:"deployment_risks,omitempty"`
+	VersionMismatch    string            `json:"version_info_mismatch,omitempty"`
+	Unregistered       bool              `json:"unregistered,omitempty"`
+	ProfileUser        string            `json:"profile_user,omitempty"`
+	BaselineDeviation  string            `json:"baseline_deviation,omitempty"`
+	PolicyViolations   []PolicyViolation `json:"policy_violations,omitempty"`
+	ProcessIDs         []int             `json:"process_ids,omitempty"`
+	CommercialFeatures []string          `json:"commercial_features,omitempty"`
+	Properties         map[string]string `json:"properties,omitempty"`
 }
 
 // MetaInfo represents metadata about the scan
 type MetaInfo struct {
-	ScanTimestamp       string `json:"scan_ts"`
-	ComputerName        string `json:"computer_name"`
-	UserName            string `json:"user_name"`
-	ScanDuration        string `json:"scan_duration"`
-	HasOracleJDK        bool   `json:"has_oracle_jdk"`
-	CountResult         int    `json:"count_result"`
-	CountRequireLicense int    `json:"count_require_license"`
-	ScannedDirs         int    `json:"scanned_dirs"`
-	ScanPath            string `json:"scan_path"`
-	PlatformInfo        string `json:"platform_info"`
+	ScanTimestamp       string           `json:"scan_ts"`
+	ComputerName        string           `json:"computer_name"`
+	UserName            string           `json:"user_name"`
+	ScanDuration        string           `json:"scan_duration"`
+	HasOracleJDK        bool             `json:"has_oracle_jdk"`
+	CountResult         int              `json:"count_result"`
+	CountRequireLicense int              `json:"count_require_license"`
+	ScannedDirs         int              `json:"scanned_dirs"`
+	ScanPath            string           `json:"scan_path"`
+	PlatformInfo        string           `json:"platform_info"`
+	HasEntitlement      bool             `json:"has_entitlement_evidence"`
+	Privileged          bool             `json:"privileged"`
+	ErrorCounts         map[string]int   `json:"error_counts,omitempty"`
+	ScanProfile         string           `json:"scan_profile,omitempty"`
+	DuplicateSavings    int64            `json:"duplicate_savings_bytes,omitempty"`
+	Host                *HostIdentifiers `json:"host,omitempty"`
+	Split               *SplitInfo       `json:"split,omitempty"`
+	Roots               []RootStats      `json:"roots,omitempty"`
+	Baseline            *BaselineSummary `json:"baseline,omitempty"`
+	PolicyViolations    int              `json:"policy_violations,omitempty"`
+	// Truncated is set when the walk was ended by --max-duration or an interrupt (Interrupted), LastScannedPath is the
+	// directory walked last
+	Truncated       bool   `json:"truncated,omitempty"`
+	Interrupted     bool   `json:"interrupted,omitempty"`
+	LastScannedPath string `json:"last_scanned_path,omitempty"`
 }
 
 // JSONOutput represents the root JSON output structure
 type JSONOutput struct {
-	Meta     MetaInfo          `json:"meta"`
-	Runtimes []JavaRuntimeJSON `json:"runtimes"`
+	Meta            MetaInfo              `json:"meta"`
+	Runtimes        []JavaRuntimeJSON     `json:"runtimes"`
+	Entitlements    []EntitlementEvidence `json:"entitlement_evidence,omitempty"`
+	Programs        []InstalledProgram    `json:"installed_programs,omitempty"`
+	Profiles        []UserProfile         `json:"user_profiles,omitempty"`
+	Installers      []InstallerArtifact   `json:"installers,omitempty"`
+	Suspected       []SuspectedInstall    `json:"suspected_installations,omitempty"`
+	Applications    []JPackageApp         `json:"jpackage_applications,omitempty"`
+	Legacy          []LegacyComponent     `json:"legacy_components,omitempty"`
+	BaselineMissing []BaselineEntry       `json:"baseline_missing,omitempty"`
+	Errors          []PathError           `json:"errors,omitempty"`
 }
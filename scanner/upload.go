@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// uploadRetries is the number of attempts to send a chunk, uploadRetryDelay the delay before the first retry,
+// doubled with every further retry
+const (
+	uploadRetries    = 6
+	uploadRetryDelay = time.Second
+)
+
+// errUploadsUnsupported is returned by collectors without resumable uploads
+var errUploadsUnsupported = errors.New("collector does not support resumable uploads")
+
+// uploadState is the state of a resumable upload acknowledged by the collector
+type uploadState struct {
+	UploadID string `json:"upload_id"`
+	Size     int    `json:"size"`
+	Offset   int    `json:"offset"`
+}
+
+// uploadClient sends a report to the collector in chunks, each acknowledged with the number of bytes received.
+// The upload is identified by the SHA-256 of the report, an interrupted transfer continues at the acknowledged offset.
+type uploadClient struct {
+	uploadsURL string
+	chunkSize  int
+	client     *http.Client
+	retryDelay time.Duration
+}
+
+// sendResumable uploads a payload in chunks to the uploads endpoint of the collector at postURL. Collectors without
+// the endpoint receive the payload with a single POST.
+func sendResumable(payload []byte, postURL string, contentType string, chunkSize int) error {
+	u := &uploadClient{
+		uploadsURL: strings.TrimSuffix(postURL, "/") + "/uploads",
+		chunkSize:  chunkSize,
+		client:     &http.Client{Timeout: 5 * time.Minute},
+		retryDelay: uploadRetryDelay,
+	}
+	body, err := u.upload(payload)
+	if errors.Is(err, errUploadsUnsupported) {
+		slog.Warn("posting the report in one request", "error", err)
+		return sendPayload(payload, postURL, contentType)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stdout.Write(body); err != nil {
+		return fmt.Errorf("failed to write response: %v", err)
+	}
+	fmt.Println()
+	return nil
+}
+
+// upload sends the chunks of a payload from the offset acknowledged by the collector and returns the response to
+// the last chunk
+func (u *uploadClient) upload(payload []byte) ([]byte, error) {
+	digest := sha256.Sum256(payload)
+	announcement, _ := json.Marshal(map[string]any{"size": len(payload), "sha256": hex.EncodeToString(digest[:])})
+
+	var state uploadState
+	status, body, err := u.retry(func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, u.uploadsURL, bytes.NewReader(announcement))
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case status == http.StatusNotFound || status == http.StatusMethodNotAllowed:
+		return nil, errUploadsUnsupported
+	case status != http.StatusOK:
+		return nil, fmt.Errorf("starting upload: server returned %d: %s", status, body)
+	}
+	if err := json.Unmarshal(body, &state); err != nil {
+		return nil, fmt.Errorf("starting upload: %v", err)
+	}
+	if state.Offset > 0 {
+		slog.Info("Resuming upload", "offset", state.Offset, "size", len(payload))
+	}
+
+	for {
+		start := state.Offset
+		end := min(len(payload), start+u.chunkSize)
+		chunk := payload[start:end]
+		chunkDigest := sha256.Sum256(chunk)
+		status, body, err := u.retry(func() (*http.Request, error) {
+			req, err := http.NewRequest(http.MethodPut, u.uploadsURL+"/"+state.UploadID, bytes.NewReader(chunk))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/octet-stream")
+				req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(payload)))
+				req.Header.Set("X-Chunk-SHA256", hex.EncodeToString(chunkDigest[:]))
+			}
+			return req, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		// a conflict acknowledges another offset, e.g. of a chunk received before its response was lost
+		if status != http.StatusOK && status != http.StatusConflict {
+			return nil, fmt.Errorf("uploading bytes %d-%d: server returned %d: %s", start, end-1, status, body)
+		}
+		if err := json.Unmarshal(body, &state); err != nil {
+			return nil, fmt.Errorf("uploading bytes %d-%d: %v", start, end-1, err)
+		}
+		if state.Offset >= len(payload) {
+			return body, nil
+		}
+	}
+}
+
+// retry sends a request until the collector responds, with a growing delay between the attempts. Server errors
+// (5xx) are retried like failed connections.
+func (u *uploadClient) retry(request func() (*http.Request, error)) (int, []byte, error) {
+	delay := u.retryDelay
+	var lastErr error
+	for attempt := 1; attempt <= uploadRetries; attempt++ {
+		if attempt > 1 {
+			slog.Warn("retrying", "error", lastErr, "delay", delay)
+			time.Sleep(delay)
+			delay *= 2
+		}
+		req, err := request()
+		if err != nil {
+			return 0, nil, err
+		}
+		resp, err := u.client.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to send to %s: %v", req.URL, err)
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read the response of %s: %v", req.URL, err)
+			continue
+		}
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("server returned %s", resp.Status)
+			continue
+		}
+		return resp.StatusCode, body, nil
+	}
+	return 0, nil, lastErr
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCollector implements the uploads endpoint, the response to every third chunk is lost
+type fakeCollector struct {
+	mu       sync.Mutex
+	received []byte
+	size     int
+	puts     int
+}
+
+func (c *fakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	switch r.Method {
+	case http.MethodPost:
+		var announcement struct{ Size int }
+		_ = json.Unmarshal(body, &announcement)
+		c.size = announcement.Size
+	case http.MethodPut:
+		var start int
+		fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-", &start)
+		if start != len(c.received) {
+			w.WriteHeader(http.StatusConflict)
+			break
+		}
+		c.received = append(c.received, body...)
+		c.puts++
+		if c.puts%3 == 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+	}
+	fmt.Fprintf(w, `{"upload_id": "%064d", "size": %d, "offset": %d}`, 0, c.size, len(c.received))
+}
+
+func TestResumableUpload(t *testing.T) {
+	collector := &fakeCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	payload := bytes.Repeat([]byte("0123456789"), 100)
+	u := &uploadClient{uploadsURL: server.URL + "/uploads", chunkSize: 64, client: server.Client(), retryDelay: time.Millisecond}
+	body, err := u.upload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(collector.received, payload) {
+		t.Errorf("collector received %d bytes, want %d", len(collector.received), len(payload))
+	}
+	if !strings.Contains(string(body), `"offset": 1000`) {
+		t.Errorf("unexpected response %s", body)
+	}
+}
+
+func TestResumableUploadUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	u := &uploadClient{uploadsURL: server.URL + "/uploads", chunkSize: 64, client: server.Client(), retryDelay: time.Millisecond}
+	if _, err := u.upload([]byte("{}")); !errors.Is(err, errUploadsUnsupported) {
+		t.Errorf("expected errUploadsUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Types of usage tracker artifacts, in addition to the host wide usage tracker configuration
+const (
+	evidenceUsageTrackerLog = "usage-tracker-log"
+	evidenceJREUsage        = "jre-usage-timestamps"
+)
+
+// jreUsageDir is the directory Oracle JREs record their last use in, below the user's home directory
+const jreUsageDir = ".oracle_jre_usage"
+
+// runtimeUsageTrackerPaths are the locations of a runtime's own usage tracker configuration,
+// lib/management for JDK 8 and its JRE, conf/management for JDK 9+
+var runtimeUsageTrackerPaths = []string{
+	filepath.Join("lib", "management", "usagetracker.properties"),
+	filepath.Join("jre", "lib", "management", "usagetracker.properties"),
+	filepath.Join("conf", "management", "usagetracker.properties"),
+}
+
+// usageTrackerArtifacts returns the usage tracker configurations of the discovered runtimes, the log files of
+// all usage tracker configurations and the JRE usage timestamps in the home directories
+func usageTrackerArtifacts(hostEvidence []EntitlementEvidence, results []*JavaResult, homes []string) []EntitlementEvidence {
+	var artifacts []EntitlementEvidence
+	configs := make(map[string]bool)
+	for _, item := range hostEvidence {
+		if item.Type == evidenceUsageTracker {
+			configs[item.Path] = true
+		}
+	}
+
+	var runtimeConfigs []string
+	for _, result := range results {
+		if result.JavaHome == "" {
+			continue
+		}
+		for _, rel := range runtimeUsageTrackerPaths {
+			path := filepath.Join(result.JavaHome, rel)
+			if configs[path] {
+				continue
+			}
+			if _, err := os.Stat(path); err == nil {
+				configs[path] = true
+				runtimeConfigs = append(runtimeConfigs, path)
+				artifacts = append(artifacts, EntitlementEvidence{
+					Type:   evidenceUsageTracker,
+					Path:   path,
+					Detail: usageTrackerDetail(path),
+				})
+			}
+		}
+	}
+
+	// the configurations are sorted so the logs are reported in the same order on every scan
+	sorted := make([]string, 0, len(configs))
+	for config := range configs {
+		sorted = append(sorted, config)
+	}
+	sort.Strings(sorted)
+	logs := make(map[string]bool)
+	for _, config := range sorted {
+		log := usageTrackerLogFile(config)
+		if log == "" || logs[log] {
+			continue
+		}
+		logs[log] = true
+		if info, err := os.Stat(log); err == nil && !info.IsDir() {
+			artifacts = append(artifacts, EntitlementEvidence{
+				Type:   evidenceUsageTrackerLog,
+				Path:   log,
+				Detail: fmt.Sprintf("%s, last written %s", humanize.Bytes(uint64(info.Size())), info.ModTime().UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+
+	for _, home := range homes {
+		dir := filepath.Join(home, jreUsageDir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		artifacts = append(artifacts, EntitlementEvidence{
+			Type:   evidenceJREUsage,
+			Path:   dir,
+			Detail: fmt.Sprintf("%d runtimes", len(entries)),
+		})
+	}
+	return artifacts
+}
+
+// hasSubscriptionEvidence reports whether evidence indicates Oracle subscription tooling, JRE usage timestamps
+// are written by every Oracle JRE 8 and do not count
+func hasSubscriptionEvidence(evidence []EntitlementEvidence) bool {
+	for _, item := range evidence {
+		if item.Type != evidenceJREUsage {
+			return true
+		}
+	}
+	return false
+}
+
+// usageTrackerLogFile returns the file a usage tracker configuration logs to, if any
+func usageTrackerLogFile(config string) string {
+	props, err := readPropertiesFile(config)
+	if err != nil {
+		return ""
+	}
+	log := strings.TrimSpace(props["com.oracle.usagetracker.logToFile"])
+	if log == "" || !filepath.IsAbs(log) {
+		return ""
+	}
+	return filepath.Clean(log)
+}
+
+// userHomes returns the home directory of the scanning user and the inspected user profiles
+func userHomes(profiles []UserProfile) []string {
+	var homes []string
+	seen := make(map[string]bool)
+	if home, err := os.UserHomeDir(); err == nil {
+		homes = append(homes, home)
+		seen[home] = true
+	}
+	for _, profile := range profiles {
+		if profile.Inspected && !seen[profile.Path] {
+			homes = append(homes, profile.Path)
+			seen[profile.Path] = true
+		}
+	}
+	return homes
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsageTrackerArtifacts(t *testing.T) {
+	root := t.TempDir()
+	log := filepath.Join(root, "var", "log", "java_usage.log")
+	jdk := filepath.Join(root, "jdk1.8.0_202")
+	config := filepath.Join(jdk, "jre", "lib", "management", "usagetracker.properties")
+	home := filepath.Join(root, "home", "alice")
+	for _, dir := range []string{filepath.Dir(log), filepath.Dir(config), filepath.Join(home, jreUsageDir)} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(config, []byte("com.oracle.usagetracker.logToFile = "+log+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(log, []byte("usage\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, jreUsageDir, "6d1a7b6b5fcbb2de.timestamp"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*JavaResult{
+		{Path: filepath.Join(jdk, "bin", "java"), JavaHome: jdk},
+		{Path: filepath.Join(jdk, "jre", "bin", "java"), JavaHome: jdk},
+	}
+	artifacts := usageTrackerArtifacts(nil, results, []string{home, filepath.Join(root, "home", "bob")})
+	if len(artifacts) != 3 {
+		t.Fatalf("expected 3 artifacts, got %+v", artifacts)
+	}
+	if artifacts[0].Type != evidenceUsageTracker || artifacts[0].Path != config {
+		t.Errorf("unexpected configuration %+v", artifacts[0])
+	}
+	if artifacts[1].Type != evidenceUsageTrackerLog || artifacts[1].Path != log {
+		t.Errorf("unexpected log %+v", artifacts[1])
+	}
+	if artifacts[2].Type != evidenceJREUsage || artifacts[2].Detail != "1 runtimes" {
+		t.Errorf("unexpected JRE usage %+v", artifacts[2])
+	}
+
+	if !hasSubscriptionEvidence(artifacts) || hasSubscriptionEvidence(artifacts[2:]) {
+		t.Error("only usage tracker artifacts are subscription evidence")
+	}
+}
+
+func TestHostEvidence(t *testing.T) {
+	root := t.TempDir()
+	jdk := filepath.Join(root, "jdk1.8.0_202")
+	trackerConfig := filepath.Join(jdk, "lib", "management", "usagetracker.properties")
+	if err := os.MkdirAll(filepath.Dir(trackerConfig), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(trackerConfig, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results := []*JavaResult{{Path: filepath.Join(jdk, "bin", "java"), JavaHome: jdk}}
+
+	// the runtimes of a share, a cluster or -eval are reported without the evidence of the scanning host
+	for _, hostEvidence := range []bool{false, true} {
+		output := filepath.Join(root, "report.json")
+		config := config{outputPath: output, jsonOutput: true, hostEvidence: hostEvidence}
+		if err := handleJSONOutput(results, 1, config, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report JSONOutput
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, evidence := range report.Entitlements {
+			found = found || evidence.Path == trackerConfig
+		}
+		if found != hostEvidence {
+			t.Errorf("host evidence %v: unexpected entitlement evidence %+v", hostEvidence, report.Entitlements)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// systemProfiles are directories below the profiles root which do not belong to an account
+var systemProfiles = map[string]bool{
+	"all users":    true,
+	"default":      true,
+	"default user": true,
+	"public":       true,
+	"shared":       true,
+	"guest":        true,
+	"lost+found":   true,
+}
+
+// UserProfile is the home directory of an account on the scanned host
+type UserProfile struct {
+	User      string `json:"user"`
+	Path      string `json:"path"`
+	Inspected bool   `json:"inspected"`
+	Error     string `json:"error,omitempty"`
+}
+
+// rootHome is the home directory of root on Linux, it is not below the profiles root
+const rootHome = "/root"
+
+// profilesRoot returns the directory containing the user profiles
+func profilesRoot() string {
+	switch runtime.GOOS {
+	case "windows":
+		drive := os.Getenv("SystemDrive")
+		if drive == "" {
+			drive = "C:"
+		}
+		return drive + `\Users`
+	case "darwin":
+		return "/Users"
+	default:
+		return "/home"
+	}
+}
+
+// hostUserProfiles returns the profiles of the host: the directories below the profiles root and on Linux the home
+// directory of root
+func hostUserProfiles() ([]UserProfile, error) {
+	profiles, err := listUserProfiles(profilesRoot())
+	if runtime.GOOS == "linux" {
+		if info, statErr := os.Stat(rootHome); statErr == nil && info.IsDir() {
+			profiles = append(profiles, newUserProfile(rootHome, "root"))
+		}
+	}
+	return profiles, err
+}
+
+// listUserProfiles returns the profile directories below root and whether their content can be read
+func listUserProfiles(root string) ([]UserProfile, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []UserProfile
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || systemProfiles[strings.ToLower(name)] {
+			continue
+		}
+		profiles = append(profiles, newUserProfile(filepath.Join(root, name), name))
+	}
+	return profiles, nil
+}
+
+// newUserProfile returns the profile of a home directory named name, checking whether its content can be read
+func newUserProfile(path, name string) UserProfile {
+	profile := UserProfile{User: name, Path: path, Inspected: true}
+	if owner, ok := profileOwner(path); ok {
+		profile.User = owner
+	}
+	if err := checkReadableDir(profile.Path); err != nil {
+		profile.Inspected = false
+		profile.Error = err.Error()
+	}
+	return profile
+}
+
+// profileOwner returns the account owning a profile directory by its user ID, or the user ID if it has no account.
+// Home directories need not be named after their account, profiles of Windows are.
+func profileOwner(path string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		return "", false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	uid, err := fileOwner(path, info)
+	if err != nil {
+		return "", false
+	}
+	if account, err := user.LookupId(uid); err == nil {
+		return account.Username, true
+	}
+	return uid, true
+}
+
+// profileRoots returns the inspectable profiles which are not already covered by the scan paths
+func profileRoots(profiles []UserProfile, scanPaths []string) []string {
+	var roots []string
+	for _, profile := range profiles {
+		if profile.Inspected && !isBelowAny(profile.Path, scanPaths) {
+			roots = append(roots, profile.Path)
+		}
+	}
+	return roots
+}
+
+// attributeProfiles sets the owning account of results located in a user profile
+func attributeProfiles(profiles []UserProfile, results []*JavaResult) {
+	for _, result := range results {
+		for _, profile := range profiles {
+			if isBelow(result.Path, profile.Path) {
+				result.ProfileUser = profile.User
+				break
+			}
+		}
+	}
+}
+
+// isBelow reports whether path is dir or located below dir
+func isBelow(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isBelowAny reports whether path is located below one of dirs
+func isBelowAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if isBelow(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// printUninspectedProfiles lists the profiles which could not be read in text output mode
+func printUninspectedProfiles(profiles []UserProfile) {
+	var skipped []string
+	for _, profile := range profiles {
+		if !profile.Inspected {
+			skipped = append(skipped, profile.User)
+		}
+	}
+	if len(skipped) == 0 {
+		return
+	}
+	printf("User profiles not inspected (run elevated for a complete scan): %s\n", strings.Join(skipped, ", "))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestListUserProfiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"alice", "bob", "Public", "Default", ".cache"} {
+		if err := os.Mkdir(filepath.Join(root, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "desktop.ini"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := listUserProfiles(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// profiles are attributed to the owner of the directory, Windows profiles to the account named like it
+	alice, bob := "alice", "bob"
+	if runtime.GOOS != "windows" {
+		current, err := user.Current()
+		if err != nil {
+			t.Fatal(err)
+		}
+		alice, bob = current.Username, current.Username
+	}
+	if len(profiles) != 2 || profiles[0].User != alice || profiles[1].User != bob {
+		t.Fatalf("unexpected profiles %+v", profiles)
+	}
+	if !profiles[0].Inspected || profiles[0].Path != filepath.Join(root, "alice") {
+		t.Errorf("unexpected profile %+v", profiles[0])
+	}
+
+	// profiles below the scan path are walked by the main scan
+	roots := profileRoots(profiles, []string{filepath.Join(root, "bob")})
+	if len(roots) != 1 || roots[0] != filepath.Join(root, "alice") {
+		t.Errorf("unexpected roots %v", roots)
+	}
+	if roots := profileRoots(profiles, []string{root}); len(roots) != 0 {
+		t.Errorf("expected no extra roots, got %v", roots)
+	}
+
+	results := []*JavaResult{
+		{Path: filepath.Join(root, "alice", ".sdkman", "candidates", "java", "current", "bin", "java")},
+		{Path: filepath.Join(root, "bobby", "bin", "java")},
+	}
+	attributeProfiles(profiles, results)
+	if results[0].ProfileUser != alice || results[1].ProfileUser != "" {
+		t.Errorf("unexpected attribution %q, %q", results[0].ProfileUser, results[1].ProfileUser)
+	}
+}
@@ -80,21 +80,22 @@ func isJavaExecutable(name string) bool {
 	return name == "java" || name == "java.exe"
 }
 
-func printf(format string, a ...interface{}) {
-	fmt.Printf(format, a...)
+// stringList is a flag.Value collecting the values of a repeatable flag
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
 }
 
-// logf writes formatted output to stderr
-func logf(format string, a ...interface{}) {
-	fmt.Fprintf(os.Stderr, format, a...)
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
 }
 
-// log writes output to stderr
-/*
-func log(a ...interface{}) {
-	fmt.Fprintln(os.Stderr, a...)
+// printf writes formatted output to stdout, diagnostics are logged with log/slog
+func printf(format string, a ...interface{}) {
+	fmt.Printf(format, a...)
 }
-*/
 
 // getPlatformInfo returns platform information in a parsable string format
 // Format: OS=<os>;Version=<version>;Arch=<arch>;[Extra=<extra>]
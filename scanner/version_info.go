@@ -0,0 +1,264 @@
+package main
+
+import (
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// rtVersion is the resource type of the VERSIONINFO resource
+const rtVersion = 16
+
+// versionSourceVersionInfo marks versions taken from the VERSIONINFO resource of java.exe
+const versionSourceVersionInfo = "version-info"
+
+// FileVersionInfo is the VERSIONINFO resource of a Windows executable
+type FileVersionInfo struct {
+	CompanyName     string `json:"company_name,omitempty"`
+	ProductName     string `json:"product_name,omitempty"`
+	ProductVersion  string `json:"product_version,omitempty"`
+	FileVersion     string `json:"file_version,omitempty"`
+	FileDescription string `json:"file_description,omitempty"`
+	// Version is the binary product version of the fixed file info, e.g. [8 0 3510 10] for 8u351
+	Version [4]uint16 `json:"-"`
+}
+
+// readVersionInfo reads the VERSIONINFO resource of a PE file without executing it,
+// nil without error for executables in other formats
+func readVersionInfo(path string) (*FileVersionInfo, error) {
+	file, err := pe.Open(path)
+	if err != nil {
+		return nil, nil
+	}
+	defer file.Close()
+
+	section := file.Section(".rsrc")
+	if section == nil {
+		return nil, nil
+	}
+	data, err := section.Data()
+	if err != nil {
+		return nil, fmt.Errorf("reading resources: %v", err)
+	}
+	blob, err := versionResource(data, section.VirtualAddress)
+	if err != nil || blob == nil {
+		return nil, err
+	}
+	return parseVersionInfo(blob)
+}
+
+// versionResource returns the data of the first VERSIONINFO resource of a resource section loaded at rva
+func versionResource(rsrc []byte, rva uint32) ([]byte, error) {
+	// the resource tree has three levels: type, name and language
+	offset, ok := resourceEntry(rsrc, 0, rtVersion)
+	for level := 0; ok && level < 2 && offset&0x80000000 != 0; level++ {
+		offset, ok = resourceEntry(rsrc, offset&0x7fffffff, -1)
+	}
+	if !ok {
+		return nil, nil
+	}
+	if offset&0x80000000 != 0 || int(offset)+16 > len(rsrc) {
+		return nil, errors.New("malformed resource directory")
+	}
+	dataRVA := binary.LittleEndian.Uint32(rsrc[offset:])
+	size := binary.LittleEndian.Uint32(rsrc[offset+4:])
+	start := int64(dataRVA) - int64(rva)
+	if start < 0 || start+int64(size) > int64(len(rsrc)) {
+		return nil, errors.New("version resource outside of the resource section")
+	}
+	return rsrc[start : start+int64(size)], nil
+}
+
+// resourceEntry returns the offset field of the entry with the given ID of the resource directory at dir,
+// or of its first entry if id is negative
+func resourceEntry(rsrc []byte, dir uint32, id int) (uint32, bool) {
+	if int(dir)+16 > len(rsrc) {
+		return 0, false
+	}
+	named := binary.LittleEndian.Uint16(rsrc[dir+12:])
+	ids := binary.LittleEndian.Uint16(rsrc[dir+14:])
+	for i := 0; i < int(named)+int(ids); i++ {
+		entry := int(dir) + 16 + 8*i
+		if entry+8 > len(rsrc) {
+			return 0, false
+		}
+		name := binary.LittleEndian.Uint32(rsrc[entry:])
+		if id < 0 || name == uint32(id) {
+			return binary.LittleEndian.Uint32(rsrc[entry+4:]), true
+		}
+	}
+	return 0, false
+}
+
+// versionBlock is a node of the VS_VERSIONINFO structure
+type versionBlock struct {
+	key      string
+	value    []byte
+	text     bool
+	children []versionBlock
+}
+
+// parseVersionBlock parses the block at the start of data: length, value length, type, key, value and children,
+// each aligned to 32 bits
+func parseVersionBlock(data []byte) (versionBlock, int, error) {
+	var block versionBlock
+	if len(data) < 6 {
+		return block, 0, errors.New("truncated version block")
+	}
+	length := int(binary.LittleEndian.Uint16(data))
+	valueLength := int(binary.LittleEndian.Uint16(data[2:]))
+	block.text = binary.LittleEndian.Uint16(data[4:]) == 1
+	if length < 6 || length > len(data) {
+		return block, 0, errors.New("invalid version block length")
+	}
+	data = data[:length]
+
+	pos := 6
+	var key []uint16
+	for ; pos+1 < len(data); pos += 2 {
+		c := binary.LittleEndian.Uint16(data[pos:])
+		if c == 0 {
+			pos += 2
+			break
+		}
+		key = append(key, c)
+	}
+	block.key = string(utf16.Decode(key))
+	pos = align4(pos)
+
+	if block.text {
+		// the length of text values is counted in characters
+		valueLength *= 2
+	}
+	if pos+valueLength > len(data) {
+		valueLength = len(data) - pos
+	}
+	block.value = data[pos : pos+valueLength]
+	pos = align4(pos + valueLength)
+
+	for pos < len(data) {
+		child, n, err := parseVersionBlock(data[pos:])
+		if err != nil {
+			return block, 0, err
+		}
+		block.children = append(block.children, child)
+		pos = align4(pos + n)
+	}
+	return block, length, nil
+}
+
+func align4(n int) int {
+	return (n + 3) &^ 3
+}
+
+// parseVersionInfo parses a VS_VERSIONINFO resource, the strings of its first string table and the product
+// version of the fixed file info
+func parseVersionInfo(data []byte) (*FileVersionInfo, error) {
+	root, _, err := parseVersionBlock(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.key != "VS_VERSION_INFO" {
+		return nil, fmt.Errorf("unexpected version resource key %q", root.key)
+	}
+
+	info := &FileVersionInfo{}
+	// VS_FIXEDFILEINFO: signature 0xfeef04bd, struct version, file version MS/LS, product version MS/LS, ...
+	if len(root.value) >= 24 && binary.LittleEndian.Uint32(root.value) == 0xfeef04bd {
+		ms := binary.LittleEndian.Uint32(root.value[16:])
+		ls := binary.LittleEndian.Uint32(root.value[20:])
+		info.Version = [4]uint16{uint16(ms >> 16), uint16(ms), uint16(ls >> 16), uint16(ls)}
+	}
+
+	for _, child := range root.children {
+		if child.key != "StringFileInfo" || len(child.children) == 0 {
+			continue
+		}
+		for _, str := range child.children[0].children {
+			value := decodeUTF16Value(str.value)
+			switch str.key {
+			case "CompanyName":
+				info.CompanyName = value
+			case "ProductName":
+				info.ProductName = value
+			case "ProductVersion":
+				info.ProductVersion = value
+			case "FileVersion":
+				info.FileVersion = value
+			case "FileDescription":
+				info.FileDescription = value
+			}
+		}
+	}
+	return info, nil
+}
+
+// decodeUTF16Value decodes a null terminated UTF-16LE string value
+func decodeUTF16Value(value []byte) string {
+	units := make([]uint16, 0, len(value)/2)
+	for i := 0; i+1 < len(value); i += 2 {
+		c := binary.LittleEndian.Uint16(value[i:])
+		if c == 0 {
+			break
+		}
+		units = append(units, c)
+	}
+	return strings.TrimSpace(string(utf16.Decode(units)))
+}
+
+// javaVersion converts the product version to a java.version string: 1.8.0_351 for 8.0.3510.10 (JDK 8 and
+// earlier encode the update times ten), 17.0.9 for 17.0.9.0. It is empty if the resource has no version.
+func (v *FileVersionInfo) javaVersion() string {
+	major, minor, patch := int(v.Version[0]), int(v.Version[1]), int(v.Version[2])
+	switch {
+	case major == 0:
+		return ""
+	case major <= 8:
+		return fmt.Sprintf("1.%d.%d_%02d", major, minor, patch/10)
+	default:
+		return strconv.Itoa(major) + "." + strconv.Itoa(minor) + "." + strconv.Itoa(patch)
+	}
+}
+
+// versionInfoMismatch compares the VERSIONINFO resource with the version and vendor reported by the runtime and
+// describes the difference, an executable of another version or vendor than its runtime is repackaged or tampered
+func versionInfoMismatch(info *FileVersionInfo, props *JavaProperties) string {
+	if info == nil || props == nil || props.Version == "" {
+		return ""
+	}
+	if version := info.javaVersion(); version != "" {
+		major, update := parseJavaVersion(version)
+		if major != props.Major || update != props.Update {
+			return fmt.Sprintf("executable version %s differs from runtime version %s", version, props.Version)
+		}
+	}
+	company := strings.Fields(strings.ToLower(info.CompanyName))
+	if len(company) > 0 && props.Vendor != "" && !strings.Contains(strings.ToLower(props.Vendor), strings.Trim(company[0], ",.")) {
+		return fmt.Sprintf("executable company %s differs from runtime vendor %s", info.CompanyName, props.Vendor)
+	}
+	return ""
+}
+
+// applyVersionInfoFallback fills version and vendor from the VERSIONINFO resource when java.exe could not be run
+// and there is no release file
+func (j *JavaRuntimeJSON) applyVersionInfoFallback(info *FileVersionInfo) {
+	if info == nil || j.VersionSource != "" {
+		return
+	}
+	version := info.javaVersion()
+	if version == "" {
+		return
+	}
+
+	j.VersionSource = versionSourceVersionInfo
+	j.JavaVersion = version
+	j.JavaVendor = info.CompanyName
+	j.IsOracle = strings.Contains(j.JavaVendor, "Oracle")
+	j.VersionMajor, j.VersionUpdate = parseJavaVersion(version)
+	j.Distribution = normalizeDistribution(j.JavaVendor, "")
+	j.checkLicenseRequirement()
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+// versionNode builds a VS_VERSIONINFO block, text values are null terminated strings
+func versionNode(key string, value []byte, text bool, children ...[]byte) []byte {
+	data := make([]byte, 6)
+	for _, c := range utf16.Encode([]rune(key)) {
+		data = binary.LittleEndian.AppendUint16(data, c)
+	}
+	data = append(data, 0, 0)
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+	valueLength := len(value)
+	if text {
+		valueLength /= 2
+	}
+	data = append(data, value...)
+	for _, child := range children {
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+		data = append(data, child...)
+	}
+	binary.LittleEndian.PutUint16(data, uint16(len(data)))
+	binary.LittleEndian.PutUint16(data[2:], uint16(valueLength))
+	if text {
+		binary.LittleEndian.PutUint16(data[4:], 1)
+	}
+	return data
+}
+
+func versionString(key, value string) []byte {
+	var encoded []byte
+	for _, c := range utf16.Encode([]rune(value)) {
+		encoded = binary.LittleEndian.AppendUint16(encoded, c)
+	}
+	return versionNode(key, append(encoded, 0, 0), true)
+}
+
+func testVersionInfo(version [4]uint16, company, productVersion string) []byte {
+	fixed := make([]byte, 52)
+	binary.LittleEndian.PutUint32(fixed, 0xfeef04bd)
+	binary.LittleEndian.PutUint32(fixed[16:], uint32(version[0])<<16|uint32(version[1]))
+	binary.LittleEndian.PutUint32(fixed[20:], uint32(version[2])<<16|uint32(version[3]))
+	table := versionNode("040904b0", nil, true,
+		versionString("CompanyName", company),
+		versionString("FileDescription", "Java(TM) Platform SE binary"),
+		versionString("ProductName", "Java(TM) Platform SE 8"),
+		versionString("ProductVersion", productVersion))
+	return versionNode("VS_VERSION_INFO", fixed, false,
+		versionNode("StringFileInfo", nil, true, table),
+		versionNode("VarFileInfo", nil, true))
+}
+
+func TestParseVersionInfo(t *testing.T) {
+	info, err := parseVersionInfo(testVersionInfo([4]uint16{8, 0, 3510, 10}, "Oracle Corporation", "8.0.3510.10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.CompanyName != "Oracle Corporation" || info.ProductVersion != "8.0.3510.10" ||
+		info.ProductName != "Java(TM) Platform SE 8" || info.FileDescription != "Java(TM) Platform SE binary" {
+		t.Errorf("unexpected strings %+v", info)
+	}
+	if got := info.javaVersion(); got != "1.8.0_351" {
+		t.Errorf("javaVersion() = %s, want 1.8.0_351", got)
+	}
+
+	if _, err := parseVersionInfo(versionNode("OTHER", nil, false)); err == nil {
+		t.Error("expected error for unexpected root key")
+	}
+	if _, err := parseVersionInfo([]byte{0xff, 0x00}); err == nil {
+		t.Error("expected error for truncated resource")
+	}
+}
+
+func TestJavaVersionOfVersionInfo(t *testing.T) {
+	tests := []struct {
+		version [4]uint16
+		want    string
+	}{
+		{[4]uint16{17, 0, 9, 0}, "17.0.9"},
+		{[4]uint16{7, 0, 800, 15}, "1.7.0_80"},
+		{[4]uint16{8, 0, 2020, 8}, "1.8.0_202"},
+		{[4]uint16{}, ""},
+	}
+	for _, test := range tests {
+		info := &FileVersionInfo{Version: test.version}
+		if got := info.javaVersion(); got != test.want {
+			t.Errorf("javaVersion(%v) = %q, want %q", test.version, got, test.want)
+		}
+	}
+}
+
+func TestVersionResource(t *testing.T) {
+	blob := testVersionInfo([4]uint16{17, 0, 9, 0}, "Eclipse Adoptium", "17.0.9.0")
+	const rva = 0x5000
+
+	// type, name and language directories with one entry each, the data entry and the resource data
+	rsrc := make([]byte, 3*24+16)
+	dir := func(offset int, id uint32, next uint32) {
+		binary.LittleEndian.PutUint16(rsrc[offset+14:], 1)
+		binary.LittleEndian.PutUint32(rsrc[offset+16:], id)
+		binary.LittleEndian.PutUint32(rsrc[offset+20:], next)
+	}
+	dir(0, rtVersion, 0x80000000|24)
+	dir(24, 1, 0x80000000|48)
+	dir(48, 0x409, 72)
+	binary.LittleEndian.PutUint32(rsrc[72:], rva+uint32(len(rsrc)))
+	binary.LittleEndian.PutUint32(rsrc[76:], uint32(len(blob)))
+	rsrc = append(rsrc, blob...)
+
+	data, err := versionResource(rsrc, rva)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseVersionInfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.CompanyName != "Eclipse Adoptium" || info.javaVersion() != "17.0.9" {
+		t.Errorf("unexpected version info %+v", info)
+	}
+
+	// resources without a version
+	binary.LittleEndian.PutUint32(rsrc[16:], 3)
+	if data, err := versionResource(rsrc, rva); data != nil || err != nil {
+		t.Errorf("expected no version resource, got %d bytes, %v", len(data), err)
+	}
+}
+
+func TestVersionInfoMismatch(t *testing.T) {
+	info := &FileVersionInfo{CompanyName: "Oracle Corporation", Version: [4]uint16{8, 0, 3510, 10}}
+	props := &JavaProperties{Version: "1.8.0_351", Vendor: "Oracle Corporation", Major: 8, Update: 351}
+	if got := versionInfoMismatch(info, props); got != "" {
+		t.Errorf("unexpected mismatch %q", got)
+	}
+
+	azul := &FileVersionInfo{CompanyName: "Azul Systems, Inc.", Version: [4]uint16{17, 0, 9, 0}}
+	if got := versionInfoMismatch(azul, &JavaProperties{Version: "17.0.9", Vendor: "Azul Systems, Inc.", Major: 17, Update: 9}); got != "" {
+		t.Errorf("unexpected mismatch %q", got)
+	}
+
+	if got := versionInfoMismatch(info, &JavaProperties{Version: "1.8.0_202", Vendor: "Oracle Corporation", Major: 8, Update: 202}); got == "" {
+		t.Error("expected version mismatch")
+	}
+	if got := versionInfoMismatch(info, &JavaProperties{Version: "1.8.0_351", Vendor: "Eclipse Adoptium", Major: 8, Update: 351}); got == "" {
+		t.Error("expected vendor mismatch")
+	}
+	if got := versionInfoMismatch(nil, props); got != "" {
+		t.Errorf("unexpected mismatch without version info %q", got)
+	}
+}
+
+func TestApplyVersionInfoFallback(t *testing.T) {
+	runtime := JavaRuntimeJSON{}
+	runtime.applyVersionInfoFallback(&FileVersionInfo{CompanyName: "Oracle Corporation", Version: [4]uint16{8, 0, 3510, 10}})
+	if runtime.VersionSource != versionSourceVersionInfo || runtime.JavaVersion != "1.8.0_351" ||
+		runtime.VersionMajor != 8 || runtime.VersionUpdate != 351 || !runtime.IsOracle {
+		t.Errorf("unexpected fallback %+v", runtime)
+	}
+
+	// the release file takes precedence
+	runtime = JavaRuntimeJSON{VersionSource: versionSourceRelease, JavaVersion: "17.0.9"}
+	runtime.applyVersionInfoFallback(&FileVersionInfo{Version: [4]uint16{17, 0, 8, 0}})
+	if runtime.VersionSource != versionSourceRelease || runtime.JavaVersion != "17.0.9" {
+		t.Errorf("release fallback overwritten: %+v", runtime)
+	}
+}
+
+func TestReadVersionInfoNonPE(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "java")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho java\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := readVersionInfo(path); info != nil || err != nil {
+		t.Errorf("expected no version info for shell script, got %+v, %v", info, err)
+	}
+}
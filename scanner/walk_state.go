@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// dirsBucket holds the directory records of the walk state, keyed by directory path
+var dirsBucket = []byte("dirs")
+
+// dirRecord is the content of a directory at the last walk
+type dirRecord struct {
+	ModTime int64    `json:"mtime"`
+	Subdirs []string `json:"subdirs,omitempty"`
+	Java    []string `json:"java,omitempty"`
+	// Installers are the files named like a JDK installer, recorded also when installers are not searched
+	Installers []string `json:"installers,omitempty"`
+}
+
+// walkState is the local state database of incremental scans. It records the modification time, the
+// subdirectories and the java executables of every walked directory, a directory whose modification time did not
+// change is not read again. Subdirectories are still visited, changes deep in a tree do not change the
+// modification time of its parents.
+type walkState struct {
+	db *bolt.DB
+	// mu guards updates, which are written when the scan completed
+	mu      sync.Mutex
+	updates map[string]dirRecord
+	// full (--full) reads every directory as in a cold scan and replaces the recorded state
+	full bool
+	// reused counts the directories taken from the state instead of read
+	reused atomic.Int64
+}
+
+// defaultStatePath returns the location of the state database in the user cache directory
+func defaultStatePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "jfind", "state.db")
+}
+
+// openWalkState opens or creates the state database, a database in use by another scan is an error
+func openWalkState(path string) (*walkState, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating state directory: %v", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening state database %s: %v", path, err)
+	}
+	return &walkState{db: db, updates: make(map[string]dirRecord)}, nil
+}
+
+// unchanged returns the record of a directory if it was not modified since the last walk,
+// and the current modification time of the directory
+func (s *walkState) unchanged(dir string) (dirRecord, time.Time, bool) {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return dirRecord{}, time.Time{}, false
+	}
+	if s.full {
+		return dirRecord{}, info.ModTime(), false
+	}
+
+	var record dirRecord
+	found := false
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(dirsBucket)
+		if bucket == nil {
+			return nil
+		}
+		if value := bucket.Get([]byte(dir)); value != nil {
+			found = json.Unmarshal(value, &record) == nil
+		}
+		return nil
+	})
+	if !found || record.ModTime != info.ModTime().UnixNano() {
+		return dirRecord{}, info.ModTime(), false
+	}
+	s.reused.Add(1)
+	s.record(dir, record)
+	return record, info.ModTime(), true
+}
+
+// record stores the content of a walked directory
+func (s *walkState) record(dir string, record dirRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates[dir] = record
+}
+
+// save replaces the records below the roots with the directories of this walk,
+// records of directories that were not walked this time are removed
+func (s *walkState) save(roots []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(dirsBucket)
+		if err != nil {
+			return err
+		}
+		for _, root := range roots {
+			var stale [][]byte
+			cursor := bucket.Cursor()
+			prefix := []byte(root)
+			for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+				if _, ok := s.updates[string(key)]; !ok && isBelow(string(key), root) {
+					stale = append(stale, append([]byte{}, key...))
+				}
+			}
+			for _, key := range stale {
+				if err := bucket.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+		for dir, record := range s.updates {
+			value, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(dir), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the state database
+func (s *walkState) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// incrementalFind walks root with the state database at statePath, as a cold scan with full, and saves the state
+func incrementalFind(t *testing.T, root, statePath string, full bool) ([]*JavaResult, int64) {
+	state, err := openWalkState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer state.Close()
+	state.full = full
+
+	finder := NewJavaFinder(root, -1, false)
+	finder.state = state
+	results, err := finder.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.save([]string{root}); err != nil {
+		t.Fatal(err)
+	}
+	return results, state.reused.Load()
+}
+
+func TestIncrementalWalk(t *testing.T) {
+	root := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "state.db")
+	writeFakeJava(t, filepath.Join(root, "opt", "jdk-17", "bin"))
+	writeFakeJava(t, filepath.Join(root, "usr", "lib", "jvm", "jdk-21", "bin"))
+
+	results, reused := incrementalFind(t, root, statePath, false)
+	if len(results) != 2 || reused != 0 {
+		t.Fatalf("first scan: expected 2 results and no reused directories, got %d and %d", len(results), reused)
+	}
+
+	// root, opt, jdk-17, bin, usr, lib, jvm, jdk-21, bin
+	results, reused = incrementalFind(t, root, statePath, false)
+	if len(results) != 2 || reused != 9 {
+		t.Errorf("second scan: expected 2 results and 9 reused directories, got %d and %d", len(results), reused)
+	}
+
+	// a new installation deep in the tree changes only the modification time of its parent
+	writeFakeJava(t, filepath.Join(root, "usr", "lib", "jvm", "jdk-25", "bin"))
+	if err := os.RemoveAll(filepath.Join(root, "opt", "jdk-17")); err != nil {
+		t.Fatal(err)
+	}
+	results, reused = incrementalFind(t, root, statePath, false)
+	if len(results) != 2 || results[1].Path != filepath.Join(root, "usr", "lib", "jvm", "jdk-25", "bin", "java") {
+		t.Errorf("third scan: unexpected results %d", len(results))
+	}
+	// jvm and opt changed, jdk-25 and its bin are new
+	if reused != 5 {
+		t.Errorf("third scan: expected 5 reused directories, got %d", reused)
+	}
+
+	state, err := openWalkState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer state.Close()
+	_ = state.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(dirsBucket).Get([]byte(filepath.Join(root, "opt", "jdk-17"))) != nil {
+			t.Errorf("expected the record of the removed directory to be gone")
+		}
+		return nil
+	})
+}
+
+func TestFullScanRebuildsState(t *testing.T) {
+	root := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "state.db")
+	writeFakeJava(t, filepath.Join(root, "opt", "jdk-17", "bin"))
+	incrementalFind(t, root, statePath, false)
+
+	// root, opt, jdk-17, bin are read again
+	results, reused := incrementalFind(t, root, statePath, true)
+	if len(results) != 1 || reused != 0 {
+		t.Errorf("full scan: expected 1 result and no reused directories, got %d and %d", len(results), reused)
+	}
+	if _, reused := incrementalFind(t, root, statePath, false); reused != 4 {
+		t.Errorf("expected the full scan to record the state, got %d reused directories", reused)
+	}
+}
+
+func TestIncrementalWalkInstallers(t *testing.T) {
+	root := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "state.db")
+	writeFakeJava(t, filepath.Join(root, "opt", "jdk-17", "bin"))
+	downloads := filepath.Join(root, "home", "alice", "Downloads")
+	if err := os.MkdirAll(downloads, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(downloads, "jdk-8u202-windows-x64.exe"), []byte("MZ"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// the installers are recorded also by a scan not searching them
+	incrementalFind(t, root, statePath, false)
+
+	state, err := openWalkState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer state.Close()
+	finder := NewJavaFinder(root, -1, false)
+	finder.state = state
+	finder.findInstallers = true
+	if _, err := finder.Find(); err != nil {
+		t.Fatal(err)
+	}
+	if state.reused.Load() == 0 || len(finder.installers) != 1 {
+		t.Errorf("expected the installer of the unchanged directory, got %d reused directories and %+v",
+			state.reused.Load(), finder.installers)
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchSettleDelay is how long a java candidate must stay quiet before it is evaluated.
+// Installers usually create the file first and write content or set the mode afterwards.
+const watchSettleDelay = 2 * time.Second
+
+// watchMaxReported bounds the reported executables remembered by a long running watch. Removals are usually seen as
+// events, the executables gone without one are pruned when the bound is reached.
+const watchMaxReported = 10000
+
+type watchConfig struct {
+	roots      stringList
+	maxDepth   int
+	jsonOutput bool
+	logs       logFlags
+	help       bool
+}
+
+// JavaWatcher watches directory trees and reports java executables as soon as they appear
+type JavaWatcher struct {
+	roots      []string
+	maxDepth   int
+	jsonOutput bool
+	watcher    *fsnotify.Watcher
+	evaluator  *JavaFinder
+	mu         sync.Mutex
+	pending    map[string]*time.Timer
+	reported   map[string]bool
+}
+
+// runWatch implements the 'watch' subcommand
+func runWatch(args []string) int {
+	config := parseWatchFlags(args)
+
+	roots := make([]string, 0, len(config.roots))
+	for _, root := range config.roots {
+		absPath, err := filepath.Abs(root)
+		if err != nil {
+			slog.Error("resolving path", "error", err)
+			return 1
+		}
+		if _, err := os.Stat(absPath); err != nil {
+			slog.Warn("skipping watch root", "path", absPath, "error", err)
+			continue
+		}
+		roots = append(roots, absPath)
+	}
+	if len(roots) == 0 {
+		slog.Error("no existing directories to watch")
+		return 1
+	}
+
+	w, err := NewJavaWatcher(roots, config.maxDepth, config.jsonOutput)
+	if err != nil {
+		slog.Error(err.Error())
+		return 1
+	}
+	defer w.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := w.Run(ctx); err != nil {
+		slog.Error("watch failed", "error", err)
+		return 1
+	}
+	return 0
+}
+
+func parseWatchFlags(args []string) watchConfig {
+	var config watchConfig
+
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s watch [-path <dir>]... [options]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Watch directories and report new java executables as they are installed.")
+		fmt.Fprintf(os.Stderr, "Default roots: %s\n\n", strings.Join(defaultWatchRoots(), ", "))
+		fmt.Fprintln(os.Stderr, "Options:")
+		flags.PrintDefaults()
+	}
+
+	flags.Var(&config.roots, "path", "Directory to watch, can be repeated (default: platform specific roots)")
+	flags.IntVar(&config.maxDepth, "depth", -1, "Maximum depth to watch below each root (-1 for unlimited)")
+	flags.BoolVar(&config.jsonOutput, "json", false, "Output one JSON object per detected runtime")
+	config.logs.register(flags)
+	flags.BoolVar(&config.help, "h", false, "Show help message")
+	flags.BoolVar(&config.help, "help", false, "Show help message")
+
+	_ = flags.Parse(args)
+
+	if config.help {
+		flags.Usage()
+		os.Exit(0)
+	}
+	if err := config.logs.setup(os.Stderr); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	if len(config.roots) == 0 {
+		config.roots = defaultWatchRoots()
+	}
+
+	return config
+}
+
+// defaultWatchRoots returns the directories where Java runtimes are typically installed
+func defaultWatchRoots() []string {
+	var roots []string
+	switch runtime.GOOS {
+	case "windows":
+		for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+			if dir := os.Getenv(env); dir != "" {
+				roots = append(roots, dir)
+			}
+		}
+	case "darwin":
+		roots = append(roots, "/Library/Java/JavaVirtualMachines", "/opt")
+	default:
+		roots = append(roots, "/usr/lib/jvm", "/usr/java", "/opt")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		roots = append(roots, home)
+	}
+	return roots
+}
+
+// NewJavaWatcher creates a new JavaWatcher for the given absolute root directories
+func NewJavaWatcher(roots []string, maxDepth int, jsonOutput bool) (*JavaWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file system watcher: %v", err)
+	}
+	return &JavaWatcher{
+		roots:      roots,
+		maxDepth:   maxDepth,
+		jsonOutput: jsonOutput,
+		watcher:    watcher,
+		evaluator:  NewJavaFinder("", -1, true),
+		pending:    make(map[string]*time.Timer),
+		reported:   make(map[string]bool),
+	}, nil
+}
+
+// Close stops watching and cancels pending evaluations
+func (w *JavaWatcher) Close() {
+	w.mu.Lock()
+	for path, timer := range w.pending {
+		timer.Stop()
+		delete(w.pending, path)
+	}
+	w.mu.Unlock()
+	_ = w.watcher.Close()
+}
+
+// Run registers all roots and processes file system events until ctx is done
+func (w *JavaWatcher) Run(ctx context.Context) error {
+	// an evaluation running on shutdown is killed
+	w.evaluator.ctx = ctx
+	for _, root := range w.roots {
+		w.addTree(root, false)
+	}
+	slog.Info("Watching directories", "directories", len(w.watcher.WatchList()), "roots", strings.Join(w.roots, ", "))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Error("watch error", "error", err)
+		}
+	}
+}
+
+// rootOf returns the watch root containing path
+func (w *JavaWatcher) rootOf(path string) string {
+	for _, root := range w.roots {
+		if pathDepth(root, path) >= 0 {
+			return root
+		}
+	}
+	return ""
+}
+
+// addTree adds watches for dir and all its subdirectories within the depth limit.
+// If report is set, java executables already present in the tree are reported,
+// which covers files created before the watch on a new directory was registered.
+func (w *JavaWatcher) addTree(dir string, report bool) {
+	root := w.rootOf(dir)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsPermission(err) {
+				slog.Info("Permission denied", "path", path)
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.IsDir() {
+			if report && isJavaExecutable(d.Name()) {
+				w.schedule(path)
+			}
+			return nil
+		}
+
+		if d.Name() == ".git" || (w.maxDepth >= 0 && pathDepth(root, path) > w.maxDepth) {
+			return filepath.SkipDir
+		}
+
+		if err := w.watcher.Add(path); err != nil {
+			slog.Warn("cannot watch directory", "path", path, "error", err)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// handleEvent dispatches a single file system event
+func (w *JavaWatcher) handleEvent(event fsnotify.Event) {
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		w.forget(event.Name)
+		return
+	}
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Lstat(event.Name); err == nil && info.IsDir() {
+			w.addTree(event.Name, true)
+			return
+		}
+	}
+
+	if isJavaExecutable(filepath.Base(event.Name)) {
+		w.schedule(event.Name)
+	}
+}
+
+// schedule (re)starts the settle timer for a java candidate
+func (w *JavaWatcher) schedule(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.reported[path] {
+		return
+	}
+	if timer, ok := w.pending[path]; ok {
+		timer.Reset(watchSettleDelay)
+		return
+	}
+	w.pending[path] = time.AfterFunc(watchSettleDelay, func() { w.check(path) })
+}
+
+// forget drops state for a removed path, so a reinstall is reported again
+func (w *JavaWatcher) forget(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	prefix := path + string(os.PathSeparator)
+	for p, timer := range w.pending {
+		if p == path || strings.HasPrefix(p, prefix) {
+			timer.Stop()
+			delete(w.pending, p)
+		}
+	}
+	for p := range w.reported {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(w.reported, p)
+		}
+	}
+}
+
+// pruneReported drops the reported executables which no longer exist, and all of them if the bound is still
+// reached. An executable dropped while it exists is reported again after its next change. w.mu must be held.
+func (w *JavaWatcher) pruneReported() {
+	for path := range w.reported {
+		if _, err := os.Lstat(path); err != nil {
+			delete(w.reported, path)
+		}
+	}
+	if len(w.reported) >= watchMaxReported {
+		slog.Debug("forgetting the reported java executables", "count", len(w.reported))
+		clear(w.reported)
+	}
+}
+
+// check evaluates a settled java candidate and reports it
+func (w *JavaWatcher) check(path string) {
+	w.mu.Lock()
+	delete(w.pending, path)
+	w.mu.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || !isExecutable(info) {
+		// not ready yet, a later chmod or write event will schedule it again
+		return
+	}
+
+	w.mu.Lock()
+	if w.reported[path] {
+		w.mu.Unlock()
+		return
+	}
+	if len(w.reported) >= watchMaxReported {
+		w.pruneReported()
+	}
+	w.reported[path] = true
+	w.mu.Unlock()
+
+	result := w.evaluator.evaluateJava(path)
+	w.report(&result)
+}
+
+// report prints a detected runtime in the configured output format
+func (w *JavaWatcher) report(result *JavaResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	runtime := createRuntimeJSON(result, true)
+	if w.jsonOutput {
+		jsonData, err := json.Marshal(runtime)
+		if err != nil {
+			slog.Error("marshaling JSON", "error", err)
+			return
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	slog.Info("Detected new java executable", "time", time.Now().Format(time.RFC3339))
+	printResult(result, &runtime)
+	printf("\n")
+}
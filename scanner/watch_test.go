@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestPathDepth(t *testing.T) {
+	sep := string(os.PathSeparator)
+	root := sep + filepath.Join("opt", "java")
+	tests := map[string]int{
+		root:                                   0,
+		root + sep:                             0,
+		filepath.Join(root, "jdk-17"):          1,
+		filepath.Join(root, "jdk-17", "bin"):   2,
+		sep + filepath.Join("opt", "javaapps"): -1,
+		sep + "opt":                            -1,
+	}
+	for path, want := range tests {
+		if got := pathDepth(root, path); got != want {
+			t.Errorf("pathDepth(%s, %s) = %d, want %d", root, path, got, want)
+		}
+	}
+	if got := pathDepth(sep, filepath.Join(root, "jdk-17")); got != 3 {
+		t.Errorf("expected depth 3 below the file system root, got %d", got)
+	}
+}
+
+func TestJavaWatcher(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX executables")
+	}
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "opt")
+	sibling := filepath.Join(dir, "optional")
+	for _, d := range []string{root, sibling} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w, err := NewJavaWatcher([]string{root}, -1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.rootOf(filepath.Join(sibling, "jdk-17")) != "" {
+		t.Errorf("expected %s not to be below the watch root %s", sibling, root)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	// a runtime extracted into a new directory is reported once it settled
+	for deadline := time.Now().Add(5 * time.Second); len(w.watcher.WatchList()) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the root was not watched")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	java := writeFakeJava(t, filepath.Join(root, "jdk-17", "bin"))
+	reported := func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.reported[java]
+	}
+	for deadline := time.Now().Add(watchSettleDelay + 10*time.Second); !reported(); {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the new java executable to be reported")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// a removed runtime is forgotten, a reinstall is reported again
+	if err := os.RemoveAll(filepath.Join(root, "jdk-17")); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); reported(); {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the removed java executable to be forgotten")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestPruneReported(t *testing.T) {
+	dir := t.TempDir()
+	java := writeFakeJava(t, filepath.Join(dir, "jdk-17", "bin"))
+	w := &JavaWatcher{reported: map[string]bool{java: true, filepath.Join(dir, "gone", "bin", "java"): true}}
+	w.pruneReported()
+	if len(w.reported) != 1 || !w.reported[java] {
+		t.Errorf("expected only the existing executable to be kept, got %v", w.reported)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Kinds of legacy deployment components, Java Web Start and the browser plugin were removed in Java 11
+const (
+	legacyWebStart     = "web-start"
+	legacyPlugin       = "browser-plugin"
+	legacyDeployConfig = "deployment-config"
+	legacyDeployCache  = "deployment-cache"
+)
+
+// LegacyComponent is a Java Web Start or browser plugin component, a licensing and a security cleanup target
+type LegacyComponent struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	// JavaHome is the runtime the component belongs to, empty for user and system wide components
+	JavaHome string `json:"java_home,omitempty"`
+	// Target is the file a plugin link points to
+	Target string `json:"target,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	// Settings are set for deployment.properties files
+	Settings *DeploymentSettings `json:"settings,omitempty"`
+}
+
+// runtimeLegacyFiles are the Web Start and plugin files of a JRE 8 or earlier, relative to its home
+var runtimeLegacyFiles = []struct {
+	path string
+	kind string
+}{
+	{filepath.Join("bin", "javaws"), legacyWebStart},
+	{filepath.Join("bin", "javaws.exe"), legacyWebStart},
+	{filepath.Join("lib", "javaws.jar"), legacyWebStart},
+	{filepath.Join("bin", "jp2launcher.exe"), legacyPlugin},
+	{filepath.Join("bin", "ssv.dll"), legacyPlugin},
+	{filepath.Join("bin", "jp2iexp.dll"), legacyPlugin},
+	{filepath.Join("bin", "plugin2", "npjp2.dll"), legacyPlugin},
+	{filepath.Join("lib", "amd64", "libnpjp2.so"), legacyPlugin},
+	{filepath.Join("lib", "i386", "libnpjp2.so"), legacyPlugin},
+	{filepath.Join("lib", "deployment.config"), legacyDeployConfig},
+}
+
+// deploymentDirs returns the user and system wide deployment directories holding deployment.properties,
+// deployment.config and the cache of Web Start applications and applets
+func deploymentDirs(goos string, homes []string) []string {
+	var dirs []string
+	switch goos {
+	case "windows":
+		if windir := os.Getenv("WINDIR"); windir != "" {
+			dirs = append(dirs, filepath.Join(windir, "Sun", "Java", "Deployment"))
+		}
+		for _, home := range homes {
+			dirs = append(dirs, filepath.Join(home, "AppData", "LocalLow", "Sun", "Java", "Deployment"))
+		}
+	case "darwin":
+		dirs = append(dirs, "/Library/Application Support/Oracle/Java/Deployment")
+		for _, home := range homes {
+			dirs = append(dirs, filepath.Join(home, "Library", "Application Support", "Oracle", "Java", "Deployment"))
+		}
+	default:
+		dirs = append(dirs, "/etc/.java/deployment")
+		for _, home := range homes {
+			dirs = append(dirs, filepath.Join(home, ".java", "deployment"))
+		}
+	}
+	return dirs
+}
+
+// browserPluginPaths returns the locations browsers load the Java plugin from, usually links into a JRE
+func browserPluginPaths(goos string, homes []string) []string {
+	switch goos {
+	case "windows":
+		// the plugin is registered from the JRE, Web Start launchers were copied to javapath
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			return []string{filepath.Join(dir, "Oracle", "Java", "javapath", "javaws.exe")}
+		}
+		return nil
+	case "darwin":
+		paths := []string{"/Library/Internet Plug-Ins/JavaAppletPlugin.plugin"}
+		for _, home := range homes {
+			paths = append(paths, filepath.Join(home, "Library", "Internet Plug-Ins", "JavaAppletPlugin.plugin"))
+		}
+		return paths
+	default:
+		paths := []string{"/usr/lib/mozilla/plugins/libnpjp2.so", "/usr/lib64/mozilla/plugins/libnpjp2.so"}
+		for _, home := range homes {
+			paths = append(paths, filepath.Join(home, ".mozilla", "plugins", "libnpjp2.so"))
+		}
+		return paths
+	}
+}
+
+// runtimeLegacyComponents returns the Web Start and plugin files of a runtime, for JDK 8 also of its embedded JRE
+func runtimeLegacyComponents(javaHome string) []LegacyComponent {
+	var components []LegacyComponent
+	for _, home := range []string{javaHome, filepath.Join(javaHome, "jre")} {
+		for _, file := range runtimeLegacyFiles {
+			path := filepath.Join(home, file.path)
+			if _, err := os.Lstat(path); err == nil {
+				components = append(components, LegacyComponent{Path: path, Kind: file.kind, JavaHome: javaHome})
+			}
+		}
+	}
+	return components
+}
+
+// findLegacyComponents returns the Web Start and browser plugin components of the discovered runtimes and of the
+// user and system wide locations, sorted by path
+func findLegacyComponents(results []*JavaResult, homes []string) []LegacyComponent {
+	var components []LegacyComponent
+	for _, result := range results {
+		if result.JavaHome != "" {
+			components = append(components, runtimeLegacyComponents(result.JavaHome)...)
+		}
+	}
+	return mergeLegacyComponents(components, hostLegacyComponents(homes))
+}
+
+// mergeLegacyComponents merges the components of runtimes with those of the host, each path once, and inspects
+// their deployment settings
+func mergeLegacyComponents(runtimeComponents, hostComponents []LegacyComponent) []LegacyComponent {
+	var components []LegacyComponent
+	seen := make(map[string]bool)
+	for _, component := range append(append([]LegacyComponent(nil), runtimeComponents...), hostComponents...) {
+		if !seen[component.Path] {
+			seen[component.Path] = true
+			components = append(components, component)
+		}
+	}
+	return inspectDeploymentConfigs(components)
+}
+
+// hostLegacyComponents returns the browser plugins, deployment settings and caches of the user and system wide
+// locations, their deployment settings are not inspected yet
+func hostLegacyComponents(homes []string) []LegacyComponent {
+	var components []LegacyComponent
+	for _, path := range browserPluginPaths(runtime.GOOS, homes) {
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+		kind := legacyPlugin
+		if filepath.Base(path) == "javaws.exe" {
+			kind = legacyWebStart
+		}
+		component := LegacyComponent{Path: path, Kind: kind}
+		if target, err := filepath.EvalSymlinks(path); err == nil && target != path {
+			component.Target = target
+		} else if err != nil {
+			component.Detail = "dangling link"
+		}
+		components = append(components, component)
+	}
+
+	for _, dir := range deploymentDirs(runtime.GOOS, homes) {
+		for _, name := range []string{"deployment.properties", "deployment.config"} {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				components = append(components, LegacyComponent{Path: path, Kind: legacyDeployConfig})
+			}
+		}
+		cache := filepath.Join(dir, "cache")
+		if info, err := os.Stat(cache); err == nil && info.IsDir() {
+			files, size := treeUsage(cache)
+			components = append(components, LegacyComponent{Path: cache, Kind: legacyDeployCache, Detail: fmt.Sprintf("%d files, %s", files, humanize.Bytes(uint64(size)))})
+		}
+	}
+	return components
+}
+
+// treeUsage returns the number and total size of the regular files below dir
+func treeUsage(dir string) (int, int64) {
+	var files int
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size
+}
+
+// printLegacyComponents prints the legacy deployment components in text output mode
+func printLegacyComponents(components []LegacyComponent) {
+	if len(components) == 0 {
+		return
+	}
+	printf("Java Web Start and browser plugin remnants:\n")
+	for _, c := range components {
+		switch {
+		case c.Target != "":
+			printf("- %s: %s (links to %s)\n", c.Path, c.Kind, c.Target)
+		case c.Detail != "":
+			printf("- %s: %s (%s)\n", c.Path, c.Kind, c.Detail)
+		default:
+			printf("- %s: %s\n", c.Path, c.Kind)
+		}
+		if c.Settings != nil {
+			for _, risk := range c.Settings.Risks {
+				printf("  Risk: %s\n", risk)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDeploymentDirs(t *testing.T) {
+	home := filepath.Join("home", "alice")
+	tests := map[string]string{
+		"linux":   filepath.Join(home, ".java", "deployment"),
+		"darwin":  filepath.Join(home, "Library", "Application Support", "Oracle", "Java", "Deployment"),
+		"windows": filepath.Join(home, "AppData", "LocalLow", "Sun", "Java", "Deployment"),
+	}
+	for goos, want := range tests {
+		dirs := deploymentDirs(goos, []string{home})
+		if len(dirs) == 0 || dirs[len(dirs)-1] != want {
+			t.Errorf("%s: unexpected deployment directories %v", goos, dirs)
+		}
+	}
+}
+
+func TestFindLegacyComponents(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses the Linux deployment and plugin locations")
+	}
+
+	root := t.TempDir()
+	jdk := filepath.Join(root, "jdk1.8.0_202")
+	writeFakeJava(t, filepath.Join(jdk, "bin"))
+	writeTestFile(t, filepath.Join(jdk, "jre", "bin", "javaws"), "#!/bin/sh\n")
+	plugin := filepath.Join(jdk, "jre", "lib", "amd64", "libnpjp2.so")
+	writeTestFile(t, plugin, "ELF")
+	modern := filepath.Join(root, "jdk-17")
+	writeFakeJava(t, filepath.Join(modern, "bin"))
+
+	home := filepath.Join(root, "home", "alice")
+	deployment := filepath.Join(home, ".java", "deployment")
+	writeTestFile(t, filepath.Join(deployment, "deployment.properties"), "deployment.security.level=MEDIUM\n")
+	writeTestFile(t, filepath.Join(deployment, "cache", "6.0", "12", "applet.jar"), "PK12345")
+	if err := os.MkdirAll(filepath.Join(home, ".mozilla", "plugins"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(home, ".mozilla", "plugins", "libnpjp2.so")
+	if err := os.Symlink(plugin, link); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*JavaResult{
+		{Path: filepath.Join(jdk, "bin", "java"), JavaHome: jdk},
+		{Path: filepath.Join(modern, "bin", "java"), JavaHome: modern},
+	}
+	components := findLegacyComponents(results, []string{home})
+	want := []LegacyComponent{
+		{Path: filepath.Join(deployment, "cache"), Kind: legacyDeployCache, Detail: "1 files, 7 B"},
+		{Path: filepath.Join(deployment, "deployment.properties"), Kind: legacyDeployConfig},
+		{Path: link, Kind: legacyPlugin, Target: plugin},
+		{Path: filepath.Join(jdk, "jre", "bin", "javaws"), Kind: legacyWebStart, JavaHome: jdk},
+		{Path: plugin, Kind: legacyPlugin, JavaHome: jdk},
+	}
+	if len(components) != len(want) {
+		t.Fatalf("expected %d components, got %+v", len(want), components)
+	}
+	if settings := components[1].Settings; settings == nil || settings.SecurityLevel != "MEDIUM" {
+		t.Errorf("expected the settings of deployment.properties, got %+v", settings)
+	}
+	for i := range want {
+		components[i].Settings = nil
+		if components[i] != want[i] {
+			t.Errorf("component %d: got %+v, want %+v", i, components[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// wellKnownJVMDirs are the conventional parent directories of Java installations on Linux,
+// distribution packages, vendor RPMs and manual installs put each runtime in a subdirectory
+var wellKnownJVMDirs = []string{
+	"/usr/lib/jvm",
+	"/usr/lib64/jvm",
+	"/usr/java",
+	"/usr/local/java",
+	"/usr/local",
+	"/opt/java",
+	"/opt/jdk",
+	"/opt",
+}
+
+// installJavaPaths are the java executables of an installation directory (JDK 8 also contains a JRE)
+var installJavaPaths = []string{
+	filepath.Join("bin", "java"),
+	filepath.Join("jre", "bin", "java"),
+}
+
+// wellKnownCandidates lists the candidate java executables of the well-known JVM directories below the start path,
+// so the most common installations are found within the first second of a scan. Linked installations like
+// /usr/lib/jvm/default-java are skipped, the walk does not follow them either.
+func (f *JavaFinder) wellKnownCandidates() []string {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	return wellKnownCandidatesIn(wellKnownJVMDirs, f.startPath)
+}
+
+// wellKnownCandidatesIn lists the candidate java executables of the installations in dirs below root
+func wellKnownCandidatesIn(dirs []string, root string) []string {
+	var candidates []string
+	for _, dir := range dirs {
+		if !isBelow(dir, root) {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			for _, java := range installJavaPaths {
+				candidates = append(candidates, filepath.Join(dir, entry.Name(), java))
+			}
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// errWineHosted is the execution error of Windows runtimes in a Wine prefix, they are not run on the host
+var errWineHosted = errors.New("windows executable in a Wine prefix, not executed")
+
+// WineRuntime is the Wine or Proton prefix of a Windows runtime found on Linux or macOS, e.g. ~/.wine or a Steam
+// compatdata/<app>/pfx directory
+type WineRuntime struct {
+	Prefix string `json:"prefix"`
+	// WindowsPath is the path of the executable within the prefix, e.g. C:\Program Files\Java\jdk-17\bin\java.exe
+	WindowsPath string `json:"windows_path"`
+	// RegistryKey and RegistryVersion are set if the system.reg of the prefix registers the runtime
+	RegistryKey     string `json:"registry_key,omitempty"`
+	RegistryVersion string `json:"registry_version,omitempty"`
+}
+
+// wineRegistryEntry is a Java registration of the JavaSoft key in the registry of a prefix
+type wineRegistryEntry struct {
+	key      string
+	javaHome string
+}
+
+// wineRegistries caches the Java registrations of the prefixes by prefix
+var wineRegistries sync.Map
+
+// winePrefixOf splits a path inside the C: drive of a Wine prefix into the prefix and the Windows path
+func winePrefixOf(path string) (prefix string, windowsPath string, ok bool) {
+	if runtime.GOOS == "windows" {
+		return "", "", false
+	}
+	prefix, rest, ok := strings.Cut(path, "/drive_c/")
+	if !ok || prefix == "" {
+		return "", "", false
+	}
+	return prefix, `C:\` + strings.ReplaceAll(rest, "/", `\`), true
+}
+
+// isJavaFile reports whether a file is a java executable. java.exe in a Wine prefix usually lacks the executable
+// permission, it is found nevertheless.
+func isJavaFile(path string, info os.FileInfo) bool {
+	if !isJavaExecutable(info.Name()) {
+		return false
+	}
+	if isExecutable(info) {
+		return true
+	}
+	_, _, hosted := winePrefixOf(path)
+	return hosted && strings.EqualFold(info.Name(), "java.exe")
+}
+
+// inspectWineRuntime returns the prefix of a java.exe hosted by Wine, with its registration in the prefix registry
+func inspectWineRuntime(javaPath, javaHome string) *WineRuntime {
+	if !strings.EqualFold(filepath.Base(javaPath), "java.exe") {
+		return nil
+	}
+	prefix, windowsPath, ok := winePrefixOf(javaPath)
+	if !ok {
+		return nil
+	}
+	wine := &WineRuntime{Prefix: prefix, WindowsPath: windowsPath}
+	if _, windowsHome, ok := winePrefixOf(javaHome); ok {
+		for _, entry := range wineRegistry(prefix) {
+			if strings.EqualFold(strings.TrimRight(entry.javaHome, `\`), windowsHome) {
+				wine.RegistryKey = entry.key
+				wine.RegistryVersion = entry.key[strings.LastIndex(entry.key, `\`)+1:]
+				break
+			}
+		}
+	}
+	return wine
+}
+
+// wineRegistry returns the Java registrations of the system.reg of a prefix
+func wineRegistry(prefix string) []wineRegistryEntry {
+	if entries, ok := wineRegistries.Load(prefix); ok {
+		return entries.([]wineRegistryEntry)
+	}
+	entries := readWineRegistry(filepath.Join(prefix, "system.reg"))
+	wineRegistries.Store(prefix, entries)
+	return entries
+}
+
+// readWineRegistry reads the JavaHome values of the JavaSoft keys (also below Wow6432Node) of a Wine registry
+// file. Keys are sections like [Software\\JavaSoft\\JDK\\17.0.13] 1700000000, values lines like "JavaHome"="C:\\...".
+func readWineRegistry(path string) []wineRegistryEntry {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var entries []wineRegistryEntry
+	key := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			key = ""
+			if end := strings.Index(line, "]"); end > 0 {
+				section := unescapeWineRegistry(line[1:end])
+				lower := strings.ToLower(section)
+				if strings.HasPrefix(lower, `software\javasoft\`) || strings.HasPrefix(lower, `software\wow6432node\javasoft\`) {
+					key = section
+				}
+			}
+			continue
+		}
+		if key == "" {
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, `"JavaHome"="`); ok {
+			entries = append(entries, wineRegistryEntry{key: key, javaHome: unescapeWineRegistry(strings.TrimSuffix(value, `"`))})
+		}
+	}
+	return entries
+}
+
+// unescapeWineRegistry removes the escaping of backslashes and quotes of registry files
+func unescapeWineRegistry(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(s)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWinePrefixOf(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Wine prefixes are found on Linux and macOS")
+	}
+	prefix, windowsPath, ok := winePrefixOf("/home/alice/.steam/steam/steamapps/compatdata/440/pfx/drive_c/Program Files/Java/jre1.8.0_202/bin/java.exe")
+	if !ok || prefix != "/home/alice/.steam/steam/steamapps/compatdata/440/pfx" || windowsPath != `C:\Program Files\Java\jre1.8.0_202\bin\java.exe` {
+		t.Errorf("unexpected prefix %q and Windows path %q", prefix, windowsPath)
+	}
+	if _, _, ok := winePrefixOf("/opt/jdk-17/bin/java"); ok {
+		t.Error("expected no prefix outside of drive_c")
+	}
+}
+
+func TestWineRuntime(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Wine prefixes are found on Linux and macOS")
+	}
+
+	prefix := filepath.Join(t.TempDir(), ".wine")
+	home := filepath.Join(prefix, "drive_c", "Program Files", "Java", "jdk-17")
+	writeTestFile(t, filepath.Join(home, "bin", "java.exe"), "MZ")
+	writeTestFile(t, filepath.Join(home, "release"), "JAVA_VERSION=\"17.0.13\"\nIMPLEMENTOR=\"Oracle Corporation\"\n")
+	writeTestFile(t, filepath.Join(prefix, "system.reg"), `WINE REGISTRY Version 2
+;; All keys relative to \\Machine
+
+[Software\\JavaSoft\\JDK\\17.0.13] 1700000000
+#time=1da0c7e4b2a6f00
+"JavaHome"="C:\\Program Files\\Java\\jdk-17"
+"RuntimeLib"="C:\\Program Files\\Java\\jdk-17\\bin\\server\\jvm.dll"
+
+[Software\\Microsoft\\Windows] 1700000000
+"JavaHome"="C:\\elsewhere"
+`)
+
+	for _, threads := range []int{1, 4} {
+		finder := NewJavaFinder(filepath.Dir(prefix), -1, true)
+		finder.walkers, finder.evaluators = threads, threads
+		results, err := finder.Find()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Wine == nil {
+			t.Fatalf("threads %d: expected the java.exe of the prefix, got %+v", threads, results)
+		}
+		wine := results[0].Wine
+		if wine.Prefix != prefix || wine.WindowsPath != `C:\Program Files\Java\jdk-17\bin\java.exe` ||
+			wine.RegistryKey != `Software\JavaSoft\JDK\17.0.13` || wine.RegistryVersion != "17.0.13" {
+			t.Errorf("threads %d: unexpected Wine runtime %+v", threads, wine)
+		}
+
+		json := createRuntimeJSON(results[0], true)
+		if json.ExecFailed || json.JavaVersion != "17.0.13" || json.VersionSource != versionSourceRelease {
+			t.Errorf("threads %d: expected the version of the release file, got %+v", threads, json)
+		}
+	}
+}
@@ -2,9 +2,10 @@
 
 from typing import Optional
 
-from fastapi import APIRouter, Depends, HTTPException, status
+from fastapi import APIRouter, Depends, Header, HTTPException, Request, status
 from fastapi.responses import JSONResponse
 from loguru import logger
+from pydantic import BaseModel, ValidationError
 from sqlalchemy.ext.asyncio import AsyncSession
 
 from jfind_svc.db import get_session
@@ -19,6 +20,7 @@ from jfind_svc.jfind_db import (
     save_scanner_results,
 )
 from jfind_svc.model import JavaRuntime, MetaInfo, ScannerResult
+from jfind_svc.uploads import OffsetConflict, UploadError, append_chunk, complete_upload, create_upload, get_upload
 
 router = APIRouter(tags=["jfind"])
 
@@ -43,6 +45,89 @@ async def process_scanner_results(results: ScannerResult, session: AsyncSession
     return JSONResponse(content={"result": "ok", "scan_id": scan_info.id}, status_code=status.HTTP_200_OK)
 
 
+class UploadRequest(BaseModel):
+    """Announcement of a resumable upload."""
+
+    size: int
+    sha256: str
+
+
+@router.post("/jfind/uploads", status_code=status.HTTP_200_OK)
+async def start_upload(request: UploadRequest) -> JSONResponse:
+    """Start a resumable upload of a report, or resume the upload of the same report.
+
+    Returns:
+        200 OK with {"upload_id": <id>, "size": <size>, "offset": <bytes received>}
+        422 Unprocessable Entity if the size or digest is invalid
+    """
+    try:
+        state = create_upload(request.size, request.sha256)
+    except UploadError as e:
+        raise HTTPException(status_code=status.HTTP_422_UNPROCESSABLE_ENTITY, detail=str(e)) from e
+    return JSONResponse(content=state._asdict(), status_code=status.HTTP_200_OK)
+
+
+@router.get("/jfind/uploads/{upload_id}", status_code=status.HTTP_200_OK)
+async def query_upload(upload_id: str) -> JSONResponse:
+    """Get the number of bytes received of an upload.
+
+    Returns:
+        200 OK with {"upload_id": <id>, "size": <size>, "offset": <bytes received>}
+        404 Not Found if the upload is unknown
+    """
+    try:
+        state = get_upload(upload_id)
+    except UploadError as e:
+        raise HTTPException(status_code=status.HTTP_422_UNPROCESSABLE_ENTITY, detail=str(e)) from e
+    if state is None:
+        raise HTTPException(status_code=status.HTTP_404_NOT_FOUND, detail=f"Upload {upload_id} not found")
+    return JSONResponse(content=state._asdict(), status_code=status.HTTP_200_OK)
+
+
+@router.put("/jfind/uploads/{upload_id}", status_code=status.HTTP_200_OK)
+async def upload_chunk(
+    upload_id: str,
+    request: Request,
+    content_range: str = Header(),
+    x_chunk_sha256: Optional[str] = Header(default=None),
+    session: AsyncSession = db_session,
+) -> JSONResponse:
+    """Receive a chunk of an upload, the Content-Range header (bytes <start>-<end>/<size>) gives its offset.
+
+    Returns:
+        200 OK with the state of the upload, with "result": "ok" and the "scan_id" once the report is complete
+        404 Not Found if the upload is unknown
+        409 Conflict with the state of the upload if the chunk does not start at the acknowledged offset
+        422 Unprocessable Entity if the chunk or the report is invalid
+    """
+    try:
+        start = int(content_range.removeprefix("bytes ").split("-", 1)[0])
+    except ValueError as e:
+        raise HTTPException(status_code=status.HTTP_422_UNPROCESSABLE_ENTITY, detail="Invalid Content-Range") from e
+
+    chunk = await request.body()
+    try:
+        state = append_chunk(upload_id, start, chunk, x_chunk_sha256)
+    except KeyError as e:
+        raise HTTPException(status_code=status.HTTP_404_NOT_FOUND, detail=f"Upload {upload_id} not found") from e
+    except OffsetConflict as e:
+        return JSONResponse(content=e.state._asdict(), status_code=status.HTTP_409_CONFLICT)
+    except UploadError as e:
+        raise HTTPException(status_code=status.HTTP_422_UNPROCESSABLE_ENTITY, detail=str(e)) from e
+    if state.offset < state.size:
+        return JSONResponse(content=state._asdict(), status_code=status.HTTP_200_OK)
+
+    try:
+        results = ScannerResult.model_validate_json(complete_upload(upload_id))
+    except (UploadError, ValidationError) as e:
+        raise HTTPException(status_code=status.HTTP_422_UNPROCESSABLE_ENTITY, detail=str(e)) from e
+    scan_info = await save_scanner_results(session, results)
+    logger.info(f"Saved uploaded scan from {scan_info.computer_name} with {scan_info.count_result} Java runtimes")
+
+    content = {**state._asdict(), "result": "ok", "scan_id": scan_info.id}
+    return JSONResponse(content=content, status_code=status.HTTP_200_OK)
+
+
 @router.get("/jfind", status_code=status.HTTP_200_OK)
 async def query_scans(
     computer_name: Optional[str] = None,
@@ -0,0 +1,111 @@
+"""Resumable uploads of large scanner reports.
+
+An upload is identified by the SHA-256 digest of the report. The scanner announces the size and digest, then sends
+the report in chunks, each acknowledged with the number of bytes received so far. An interrupted transfer continues
+at the acknowledged offset, also after a restart of the scanner, as the same report has the same upload ID.
+"""
+
+import hashlib
+import json
+import os
+import re
+import tempfile
+from pathlib import Path
+from typing import NamedTuple, Optional
+
+# Directory of the partial uploads
+UPLOAD_DIR = Path(os.getenv("UPLOAD_DIR", Path(tempfile.gettempdir()) / "jfind-uploads"))
+
+# Largest accepted report
+MAX_UPLOAD_SIZE = int(os.getenv("MAX_UPLOAD_SIZE", 1024 * 1024 * 1024))
+
+_UPLOAD_ID = re.compile(r"^[0-9a-f]{64}$")
+
+
+class UploadError(Exception):
+    """Invalid upload request."""
+
+
+class UploadState(NamedTuple):
+    """State of an upload."""
+
+    upload_id: str
+    size: int
+    offset: int
+
+
+class OffsetConflict(Exception):
+    """Chunk not starting at the offset acknowledged so far."""
+
+    def __init__(self, state: UploadState):
+        super().__init__(f"Expected a chunk at offset {state.offset}")
+        self.state = state
+
+
+def _paths(upload_id: str) -> tuple[Path, Path]:
+    if not _UPLOAD_ID.match(upload_id):
+        raise UploadError(f"Invalid upload ID {upload_id}")
+    return UPLOAD_DIR / f"{upload_id}.part", UPLOAD_DIR / f"{upload_id}.json"
+
+
+def create_upload(size: int, sha256: str) -> UploadState:
+    """Create an upload, or return the state of the upload of the same report."""
+    sha256 = sha256.lower()
+    if size <= 0 or size > MAX_UPLOAD_SIZE:
+        raise UploadError(f"Invalid upload size {size}, maximum {MAX_UPLOAD_SIZE}")
+    data_path, meta_path = _paths(sha256)
+    UPLOAD_DIR.mkdir(parents=True, exist_ok=True)
+    if meta_path.exists():
+        state = get_upload(sha256)
+        if state is not None and state.size == size:
+            return state
+    data_path.write_bytes(b"")
+    meta_path.write_text(json.dumps({"size": size}))
+    return UploadState(sha256, size, 0)
+
+
+def get_upload(upload_id: str) -> Optional[UploadState]:
+    """Return the state of an upload, None if it is unknown."""
+    data_path, meta_path = _paths(upload_id)
+    if not meta_path.exists() or not data_path.exists():
+        return None
+    size = json.loads(meta_path.read_text())["size"]
+    return UploadState(upload_id, size, data_path.stat().st_size)
+
+
+def append_chunk(upload_id: str, start: int, chunk: bytes, chunk_sha256: Optional[str]) -> UploadState:
+    """Append a chunk at the offset start, which must be the offset acknowledged so far.
+
+    Raises:
+        UploadError: if the chunk does not match its digest or exceeds the size of the upload
+        OffsetConflict: if the chunk does not start at the acknowledged offset
+        KeyError: if the upload is unknown
+    """
+    state = get_upload(upload_id)
+    if state is None:
+        raise KeyError(upload_id)
+    if start != state.offset:
+        raise OffsetConflict(state)
+    if chunk_sha256 and hashlib.sha256(chunk).hexdigest() != chunk_sha256.lower():
+        raise UploadError("Chunk digest mismatch")
+    if state.offset + len(chunk) > state.size:
+        raise UploadError(f"Chunk exceeds the upload size {state.size}")
+    data_path, _ = _paths(upload_id)
+    with data_path.open("ab") as f:
+        f.write(chunk)
+    return UploadState(upload_id, state.size, state.offset + len(chunk))
+
+
+def complete_upload(upload_id: str) -> bytes:
+    """Return the report of a complete upload and remove the upload.
+
+    Raises:
+        UploadError: if the report does not match the digest of the upload
+    """
+    data_path, meta_path = _paths(upload_id)
+    data = data_path.read_bytes()
+    data_path.unlink(missing_ok=True)
+    meta_path.unlink(missing_ok=True)
+    if hashlib.sha256(data).hexdigest() != upload_id:
+        raise UploadError("Report digest mismatch, the upload has to be restarted")
+    return data
@@ -0,0 +1,101 @@
+"""Test resumable uploads of scanner results."""
+
+import hashlib
+import json
+from datetime import datetime, timezone
+
+import pytest
+from httpx import AsyncClient
+
+from jfind_svc import uploads
+
+
+@pytest.fixture(autouse=True)
+def upload_dir(tmp_path, monkeypatch):
+    """Keep the partial uploads of a test in a temporary directory."""
+    monkeypatch.setattr(uploads, "UPLOAD_DIR", tmp_path)
+    return tmp_path
+
+
+@pytest.fixture
+def report() -> bytes:
+    """Create a report as sent by the scanner."""
+    return json.dumps(
+        {
+            "meta": {
+                "scan_ts": datetime.now(timezone.utc).isoformat(),
+                "computer_name": "upload-computer",
+                "user_name": "test-user",
+                "scan_duration": "1s",
+                "has_oracle_jdk": False,
+                "count_result": 1,
+                "count_require_license": 0,
+                "scanned_dirs": 10,
+                "scan_path": "/opt",
+                "platform_info": "test-platform",
+            },
+            "runtimes": [{"java_executable": "/opt/jdk-17/bin/java", "java_version": "17.0.13"}],
+        }
+    ).encode()
+
+
+def _range(start: int, chunk: bytes, size: int) -> dict[str, str]:
+    return {
+        "Content-Range": f"bytes {start}-{start + len(chunk) - 1}/{size}",
+        "X-Chunk-SHA256": hashlib.sha256(chunk).hexdigest(),
+    }
+
+
+@pytest.mark.asyncio
+async def test_resumed_upload(test_client: AsyncClient, report: bytes):
+    """An interrupted upload continues at the acknowledged offset."""
+    digest = hashlib.sha256(report).hexdigest()
+    response = await test_client.post("/api/jfind/uploads", json={"size": len(report), "sha256": digest})
+    assert response.status_code == 200
+    assert response.json() == {"upload_id": digest, "size": len(report), "offset": 0}
+
+    first = report[:100]
+    response = await test_client.put(f"/api/jfind/uploads/{digest}", content=first, headers=_range(0, first, len(report)))
+    assert response.status_code == 200
+    assert response.json()["offset"] == 100
+
+    # the scanner restarts and announces the same report again
+    response = await test_client.post("/api/jfind/uploads", json={"size": len(report), "sha256": digest})
+    assert response.json()["offset"] == 100
+
+    # a chunk at another offset is rejected with the acknowledged offset
+    response = await test_client.put(f"/api/jfind/uploads/{digest}", content=first, headers=_range(0, first, len(report)))
+    assert response.status_code == 409
+    assert response.json()["offset"] == 100
+
+    rest = report[100:]
+    response = await test_client.put(f"/api/jfind/uploads/{digest}", content=rest, headers=_range(100, rest, len(report)))
+    assert response.status_code == 200
+    data = response.json()
+    assert data["result"] == "ok"
+    assert data["offset"] == len(report)
+    assert "scan_id" in data
+
+    response = await test_client.get(f"/api/jfind/uploads/{digest}")
+    assert response.status_code == 404
+
+
+@pytest.mark.asyncio
+async def test_corrupted_chunk(test_client: AsyncClient, report: bytes):
+    """A chunk not matching its digest is rejected."""
+    digest = hashlib.sha256(report).hexdigest()
+    await test_client.post("/api/jfind/uploads", json={"size": len(report), "sha256": digest})
+
+    headers = _range(0, report, len(report))
+    response = await test_client.put(f"/api/jfind/uploads/{digest}", content=report[::-1], headers=headers)
+    assert response.status_code == 422
+
+    response = await test_client.get(f"/api/jfind/uploads/{digest}")
+    assert response.json()["offset"] == 0
+
+
+@pytest.mark.asyncio
+async def test_invalid_upload_id(test_client: AsyncClient):
+    """Upload IDs are SHA-256 digests."""
+    response = await test_client.get("/api/jfind/uploads/not-a-digest")
+    assert response.status_code == 422